@@ -0,0 +1,239 @@
+package castle
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var (
+	ErrInvalidPostcode     = errors.New("invalid postcode")
+	ErrUnsupportedPostcode = errors.New("postcodes of country are not supported")
+
+	postcodeFormats = map[Country]postcodeFormat{
+		// Eircode: a routing key, like V94 or D6W, and a unique identifier
+		Ireland: {
+			pattern:         regexp.MustCompile(`(?i)\b([AC-FHKNPRTV-Y]\d[\dW])\s?([0-9AC-FHKNPRTV-Y]{4})\b`),
+			separator:       " ",
+			uppercase:       true,
+			patternForWhole: regexp.MustCompile(`(?i)^([AC-FHKNPRTV-Y]\d[\dW])\s?([0-9AC-FHKNPRTV-Y]{4})$`),
+		},
+		// outward code, like SL4 or EC1A, and inward code, like 1NJ
+		UK: {
+			pattern:         regexp.MustCompile(`(?i)\b([A-PR-UWYZ][A-HK-Y]?\d[A-Z\d]?)\s?(\d[ABD-HJLNP-UW-Z]{2})\b`),
+			separator:       " ",
+			uppercase:       true,
+			patternForWhole: regexp.MustCompile(`(?i)^([A-PR-UWYZ][A-HK-Y]?\d[A-Z\d]?)\s?(\d[ABD-HJLNP-UW-Z]{2})$`),
+		},
+		// código postal: CP4 and CP3, like 8970-066
+		Portugal: {
+			pattern:         regexp.MustCompile(`\b(\d{4})\s?-\s?(\d{3})\b`),
+			separator:       "-",
+			patternForWhole: regexp.MustCompile(`^(\d{4})\s?-\s?(\d{3})$`),
+		},
+	}
+)
+
+type postcodeFormat struct {
+	// finds a postcode inside a line of an address
+	pattern *regexp.Regexp
+	// matches a line that is only a postcode
+	patternForWhole *regexp.Regexp
+	// between the two parts of normalized postcodes
+	separator string
+	uppercase bool
+}
+
+func (f postcodeFormat) normalize(parts []string) string {
+	postcode := parts[1] + f.separator + parts[2]
+	if f.uppercase {
+		return strings.ToUpper(postcode)
+	}
+	return postcode
+}
+
+// ParsePostcode validates the postcode on the format of the country and
+// normalizes it, like v94dwv7 into V94 DWV7.
+func ParsePostcode(country Country, text string) (string, error) {
+	format, found := postcodeFormats[country]
+	if !found {
+		return "", fmt.Errorf("%w: [%s]", ErrUnsupportedPostcode, country)
+	}
+	parts := format.patternForWhole.FindStringSubmatch(strings.TrimSpace(text))
+	if parts == nil {
+		return "", fmt.Errorf("%w: [%s]", ErrInvalidPostcode, text)
+	}
+	return format.normalize(parts), nil
+}
+
+// IsValidPostcode tells whether the postcode is on the format of the country.
+func IsValidPostcode(country Country, postcode string) bool {
+	_, err := ParsePostcode(country, postcode)
+	return err == nil
+}
+
+// findPostcode finds the last postcode inside the line, returning the line
+// without it, like Windsor out of "Windsor SL4 1NJ".
+func findPostcode(country Country, line string) (string, string, bool) {
+	format, found := postcodeFormats[country]
+	if !found {
+		return "", line, false
+	}
+	locations := format.pattern.FindAllStringSubmatchIndex(line, -1)
+	if len(locations) == 0 {
+		return "", line, false
+	}
+	location := locations[len(locations)-1]
+	parts := []string{line[location[0]:location[1]], line[location[2]:location[3]], line[location[4]:location[5]]}
+	rest := strings.TrimSpace(line[:location[0]] + line[location[1]:])
+	return format.normalize(parts), strings.Trim(rest, ", "), true
+}
+
+// Address is the postal address of a castle, with the lines the source gave
+// it on as they were.
+type Address struct {
+	Street   string `json:"street" bson:"street"`
+	Locality string `json:"locality" bson:"locality"`
+	Town     string `json:"town" bson:"town"`
+	// county, district or any other region the country has
+	Region   string   `json:"region" bson:"region"`
+	Postcode string   `json:"postcode" bson:"postcode"`
+	Country  Country  `json:"country" bson:"country"`
+	Lines    []string `json:"lines" bson:"lines"`
+}
+
+// ParseAddress reads the lines of an address, like [Ross Castle, Ross Road,
+// Killarney, Co. Kerry, V93 V304] or [Castle Hill, Windsor SL4 1NJ]. The line
+// sharing the postcode is the town, and the one after it the region. Without
+// one, the last line is the region and the one before it the town. The lines
+// before the town are the street and the locality, a first line left over
+// being the name of the place.
+func ParseAddress(country Country, rawLines []string) Address {
+	address := Address{Country: country}
+	var lines []string
+	townIndex := -1
+	for _, rawLine := range rawLines {
+		line := strings.Join(strings.Fields(strings.Trim(strings.TrimSpace(rawLine), ",")), " ")
+		if line == "" {
+			continue
+		}
+		address.Lines = append(address.Lines, line)
+		if address.Postcode == "" {
+			if postcode, rest, found := findPostcode(country, line); found {
+				address.Postcode = postcode
+				line = rest
+				if rest != "" {
+					townIndex = len(lines)
+				}
+			}
+		}
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+	if len(lines) == 0 {
+		return address
+	}
+
+	switch {
+	case townIndex >= 0:
+		if townIndex+1 < len(lines) {
+			address.Region = lines[townIndex+1]
+		}
+	case len(lines) == 1:
+		townIndex = 0
+	default:
+		townIndex = len(lines) - 2
+		address.Region = lines[len(lines)-1]
+	}
+	address.Town = lines[townIndex]
+
+	beforeTown := lines[:townIndex]
+	switch len(beforeTown) {
+	case 0:
+	case 1:
+		address.Street = beforeTown[0]
+	case 2:
+		address.Street = beforeTown[1]
+	default:
+		address.Street, address.Locality = beforeTown[len(beforeTown)-2], beforeTown[len(beforeTown)-1]
+	}
+	return address
+}
+
+// City is the town of the address.
+func (a Address) City() string {
+	return a.Town
+}
+
+// State is the region of the address.
+func (a Address) State() string {
+	return a.Region
+}
+
+// District is the most specific part of the address under the town.
+func (a Address) District() string {
+	switch {
+	case a.Locality != "":
+		return a.Locality
+	case a.Street != "":
+		return a.Street
+	default:
+		return a.Town
+	}
+}
+
+func (a *Address) Copy() *Address {
+	newAddress := *a
+	if len(a.Lines) > 0 {
+		newAddress.Lines = make([]string, len(a.Lines))
+		copy(newAddress.Lines, a.Lines)
+	}
+	return &newAddress
+}
+
+// MergeWith keeps the address a, completing the parts it misses with the
+// ones of o.
+func (a *Address) MergeWith(o *Address) *Address {
+	if a == nil {
+		if o == nil {
+			return nil
+		}
+		return o.Copy()
+	}
+	merged := a.Copy()
+	if o == nil {
+		return merged
+	}
+	for _, part := range []struct{ mine, other *string }{
+		{mine: &merged.Street, other: &o.Street},
+		{mine: &merged.Locality, other: &o.Locality},
+		{mine: &merged.Town, other: &o.Town},
+		{mine: &merged.Region, other: &o.Region},
+		{mine: &merged.Postcode, other: &o.Postcode},
+	} {
+		if *part.mine == "" {
+			*part.mine = *part.other
+		}
+	}
+	if len(merged.Lines) == 0 {
+		merged.Lines = o.Copy().Lines
+	}
+	return merged
+}
+
+// SetAddress sets the address of the castle, deriving its city, state and
+// district from it. Parts the address misses leave the fields as they were.
+func (m *Model) SetAddress(a Address) {
+	m.Address = &a
+	if city := a.City(); city != "" {
+		m.City = city
+	}
+	if state := a.State(); state != "" {
+		m.State = state
+	}
+	if district := a.District(); district != "" {
+		m.District = district
+	}
+}
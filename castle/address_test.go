@@ -0,0 +1,131 @@
+package castle
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParsePostcode(t *testing.T) {
+	testCases := []struct {
+		name             string
+		country          Country
+		text             string
+		expectedPostcode string
+		expectedErr      error
+	}{
+		{name: "eircode", country: Ireland, text: "V94 DWV7", expectedPostcode: "V94 DWV7"},
+		{name: "eircode_without_space", country: Ireland, text: "v94dwv7", expectedPostcode: "V94 DWV7"},
+		{name: "eircode_of_dublin_6w", country: Ireland, text: "D6W XY12", expectedPostcode: "D6W XY12"},
+		{name: "eircode_with_invalid_letter", country: Ireland, text: "B94 DWV7", expectedErr: ErrInvalidPostcode},
+		{name: "uk", country: UK, text: "SL4 1NJ", expectedPostcode: "SL4 1NJ"},
+		{name: "uk_of_london", country: UK, text: "ec1a1bb", expectedPostcode: "EC1A 1BB"},
+		{name: "uk_with_invalid_inward_code", country: UK, text: "SL4 1NC", expectedErr: ErrInvalidPostcode},
+		{name: "portugal", country: Portugal, text: "8970-066", expectedPostcode: "8970-066"},
+		{name: "portugal_without_cp3", country: Portugal, text: "8970", expectedErr: ErrInvalidPostcode},
+		{name: "unsupported_country", country: Denmark, text: "8000", expectedErr: ErrUnsupportedPostcode},
+	}
+
+	for _, tt := range testCases {
+		currentTT := tt
+		t.Run(currentTT.name, func(t *testing.T) {
+			t.Helper()
+			postcode, err := ParsePostcode(currentTT.country, currentTT.text)
+
+			if !errors.Is(err, currentTT.expectedErr) {
+				t.Errorf("expected err [%v], got [%v]", currentTT.expectedErr, err)
+			}
+			if postcode != currentTT.expectedPostcode {
+				t.Errorf("expected postcode [%s], got [%s]", currentTT.expectedPostcode, postcode)
+			}
+		})
+	}
+}
+
+func TestParseAddress(t *testing.T) {
+	testCases := []struct {
+		name     string
+		country  Country
+		lines    []string
+		expected Address
+	}{
+		{
+			name:    "town_and_county",
+			country: Ireland,
+			lines:   []string{"Trim ", "Co Meath", "C15 HN90"},
+			expected: Address{
+				Town: "Trim", Region: "Co Meath", Postcode: "C15 HN90", Country: Ireland,
+				Lines: []string{"Trim", "Co Meath", "C15 HN90"},
+			},
+		},
+		{
+			name:    "place_street_town_and_county",
+			country: Ireland,
+			lines:   []string{"Ross Castle,", "Ross Road,", "Killarney,", "Co. Kerry", "V93 V304"},
+			expected: Address{
+				Street: "Ross Road", Town: "Killarney", Region: "Co. Kerry", Postcode: "V93 V304", Country: Ireland,
+				Lines: []string{"Ross Castle", "Ross Road", "Killarney", "Co. Kerry", "V93 V304"},
+			},
+		},
+		{
+			name:    "post_town_with_postcode",
+			country: UK,
+			lines:   []string{"Windsor SL4 1NJ"},
+			expected: Address{
+				Town: "Windsor", Postcode: "SL4 1NJ", Country: UK,
+				Lines: []string{"Windsor SL4 1NJ"},
+			},
+		},
+		{
+			name:    "street_and_post_town_with_postcode",
+			country: UK,
+			lines:   []string{"Castle Hill", " Dover CT16 1HU"},
+			expected: Address{
+				Street: "Castle Hill", Town: "Dover", Postcode: "CT16 1HU", Country: UK,
+				Lines: []string{"Castle Hill", "Dover CT16 1HU"},
+			},
+		},
+		{
+			name:    "portuguese_postcode_before_locality",
+			country: Portugal,
+			lines:   []string{"Rua do Castelo", "8970-066 Alcoutim", "Faro"},
+			expected: Address{
+				Street: "Rua do Castelo", Town: "Alcoutim", Region: "Faro", Postcode: "8970-066", Country: Portugal,
+				Lines: []string{"Rua do Castelo", "8970-066 Alcoutim", "Faro"},
+			},
+		},
+		{
+			name:     "without_lines",
+			country:  UK,
+			lines:    []string{" ", ""},
+			expected: Address{Country: UK},
+		},
+	}
+
+	for _, tt := range testCases {
+		currentTT := tt
+		t.Run(currentTT.name, func(t *testing.T) {
+			t.Helper()
+			if diff := cmp.Diff(currentTT.expected, ParseAddress(currentTT.country, currentTT.lines)); diff != "" {
+				t.Errorf("diff: %v", diff)
+			}
+		})
+	}
+}
+
+func TestSetAddressDerivesCityStateAndDistrict(t *testing.T) {
+	m := Model{Name: "windsor", Country: UK, State: "Berkshire, Greater London"}
+
+	m.SetAddress(ParseAddress(UK, []string{"Castle Hill", "Windsor SL4 1NJ"}))
+
+	if m.City != "Windsor" {
+		t.Errorf("expected city [Windsor], got [%s]", m.City)
+	}
+	if m.State != "Berkshire, Greater London" {
+		t.Errorf("expected state to be kept, got [%s]", m.State)
+	}
+	if m.District != "Castle Hill" {
+		t.Errorf("expected district [Castle Hill], got [%s]", m.District)
+	}
+}
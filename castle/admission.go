@@ -0,0 +1,192 @@
+package castle
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrUnrecognizedPrice = errors.New("unrecognized price")
+
+	amountMatcher = regexp.MustCompile(`(?i)([€£$])\s*(\d+(?:[.,]\d{1,2})?)|(\d+(?:[.,]\d{1,2})?)\s*(EUR|GBP|USD|euros?)\b`)
+	// symbols and names of the currencies sources give prices on
+	currencyCodes = map[string]string{
+		"€":     "EUR",
+		"eur":   "EUR",
+		"euro":  "EUR",
+		"euros": "EUR",
+		"£":     "GBP",
+		"gbp":   "GBP",
+		"$":     "USD",
+		"usd":   "USD",
+	}
+
+	// checked in order, so a family ticket for adults and children is only a family one
+	ticketCategoryKeywords = []struct {
+		category TicketCategory
+		keywords []string
+	}{
+		{category: FamilyTicket, keywords: []string{"family"}},
+		{category: StudentTicket, keywords: []string{"student"}},
+		{category: SeniorTicket, keywords: []string{"senior", "oap", "over 60", "over 65", "60+", "65+"}},
+		{category: ChildTicket, keywords: []string{"child", "kid"}},
+		{category: AdultTicket, keywords: []string{"adult"}},
+	}
+)
+
+// TicketCategory is who a price is for.
+type TicketCategory string
+
+const (
+	AdultTicket   TicketCategory = "adult"
+	ChildTicket   TicketCategory = "child"
+	SeniorTicket  TicketCategory = "senior"
+	FamilyTicket  TicketCategory = "family"
+	StudentTicket TicketCategory = "student"
+)
+
+// Price is what a ticket costs, with the ISO 4217 code of its currency. Free
+// tickets have no currency.
+type Price struct {
+	Category TicketCategory `json:"category"`
+	Amount   float64        `json:"amount"`
+	Currency string         `json:"currency"`
+}
+
+type Admission struct {
+	Prices []Price `json:"prices" bson:"prices"`
+	// nobody pays to get in
+	Free bool `json:"free" bson:"free"`
+	// what members of the Heritage Card, the OPW and alike get, as the source tells it
+	MembershipNotes string `json:"membershipNotes" bson:"membershipNotes"`
+	BookingURL      string `json:"bookingURL" bson:"bookingURL"`
+}
+
+func (a *Admission) Copy() *Admission {
+	var pricesCopy []Price
+	if len(a.Prices) > 0 {
+		pricesCopy = make([]Price, len(a.Prices))
+		copy(pricesCopy, a.Prices)
+	}
+	return &Admission{
+		Prices:          pricesCopy,
+		Free:            a.Free,
+		MembershipNotes: a.MembershipNotes,
+		BookingURL:      a.BookingURL,
+	}
+}
+
+// IsKnown tells whether the admission says anything about what visitors pay.
+func (a *Admission) IsKnown() bool {
+	return a != nil && (a.Free || len(a.Prices) > 0)
+}
+
+// AddPrices adds a price for each category of the label, like "Over 60 /
+// Student", which is left out when it has none. Categories already priced
+// keep their first price.
+func (a *Admission) AddPrices(label, rawAmount string) error {
+	amount, currency, err := ParseAmount(rawAmount)
+	if err != nil {
+		return err
+	}
+	for _, category := range TicketCategoriesOf(label) {
+		if a.priceOf(category) != nil {
+			continue
+		}
+		a.Prices = append(a.Prices, Price{Category: category, Amount: amount, Currency: currency})
+	}
+	return nil
+}
+
+func (a *Admission) priceOf(category TicketCategory) *Price {
+	for i := range a.Prices {
+		if a.Prices[i].Category == category {
+			return &a.Prices[i]
+		}
+	}
+	return nil
+}
+
+// MergeWith keeps what a tells, completing it with what o tells. The prices
+// of a win, as they all come from the same source.
+func (a *Admission) MergeWith(o *Admission) *Admission {
+	if !a.IsKnown() {
+		if o == nil {
+			return a
+		}
+		merged := o.Copy()
+		if a != nil {
+			if a.MembershipNotes != "" {
+				merged.MembershipNotes = a.MembershipNotes
+			}
+			if a.BookingURL != "" {
+				merged.BookingURL = a.BookingURL
+			}
+		}
+		return merged
+	}
+	merged := a.Copy()
+	if o == nil {
+		return merged
+	}
+	if merged.MembershipNotes == "" {
+		merged.MembershipNotes = o.MembershipNotes
+	}
+	if merged.BookingURL == "" {
+		merged.BookingURL = o.BookingURL
+	}
+	return merged
+}
+
+// TicketCategoriesOf finds the categories of a price label, like "Child
+// (5-16 years) / Disabled", each part of it split by / having one at most.
+func TicketCategoriesOf(label string) []TicketCategory {
+	var categories []TicketCategory
+	for _, part := range strings.Split(strings.ToLower(label), "/") {
+		category, ok := ticketCategoryOf(part)
+		if !ok {
+			continue
+		}
+		if !slices.Contains(categories, category) {
+			categories = append(categories, category)
+		}
+	}
+	return categories
+}
+
+func ticketCategoryOf(part string) (TicketCategory, bool) {
+	for _, candidate := range ticketCategoryKeywords {
+		for _, keyword := range candidate.keywords {
+			if strings.Contains(part, keyword) {
+				return candidate.category, true
+			}
+		}
+	}
+	return "", false
+}
+
+// ParseAmount parses prices like "€5.00", "£23.50", "5,50 EUR" or "Free",
+// which is an amount of 0 without currency.
+func ParseAmount(text string) (float64, string, error) {
+	trimmed := strings.TrimSpace(text)
+	if strings.EqualFold(trimmed, "free") {
+		return 0, "", nil
+	}
+	match := amountMatcher.FindStringSubmatch(trimmed)
+	if match == nil {
+		return 0, "", fmt.Errorf("%w: [%s]", ErrUnrecognizedPrice, text)
+	}
+	symbol, number := match[1], match[2]
+	if symbol == "" {
+		symbol, number = match[4], match[3]
+	}
+	amount, err := strconv.ParseFloat(strings.Replace(number, ",", ".", 1), 64)
+	if err != nil {
+		return 0, "", fmt.Errorf("%w: [%s]", ErrUnrecognizedPrice, text)
+	}
+	return amount, currencyCodes[strings.ToLower(symbol)], nil
+}
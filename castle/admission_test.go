@@ -0,0 +1,108 @@
+package castle
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParseAmount(t *testing.T) {
+	testCases := []struct {
+		text             string
+		expectedAmount   float64
+		expectedCurrency string
+		expectedErr      error
+	}{
+		{text: "€5.00", expectedAmount: 5, expectedCurrency: "EUR"},
+		{text: "£23.50", expectedAmount: 23.5, expectedCurrency: "GBP"},
+		{text: " € 4 ", expectedAmount: 4, expectedCurrency: "EUR"},
+		{text: "5,50 EUR", expectedAmount: 5.5, expectedCurrency: "EUR"},
+		{text: "3 euros", expectedAmount: 3, expectedCurrency: "EUR"},
+		{text: "Free", expectedAmount: 0, expectedCurrency: ""},
+		{text: "Check", expectedErr: ErrUnrecognizedPrice},
+		{text: "", expectedErr: ErrUnrecognizedPrice},
+	}
+
+	for _, tt := range testCases {
+		currentTT := tt
+		t.Run(currentTT.text, func(t *testing.T) {
+			t.Helper()
+			amount, currency, err := ParseAmount(currentTT.text)
+
+			if !errors.Is(err, currentTT.expectedErr) {
+				t.Errorf("expected err [%v], got [%v]", currentTT.expectedErr, err)
+			}
+			if amount != currentTT.expectedAmount {
+				t.Errorf("expected amount [%v], got [%v]", currentTT.expectedAmount, amount)
+			}
+			if currency != currentTT.expectedCurrency {
+				t.Errorf("expected currency [%s], got [%s]", currentTT.expectedCurrency, currency)
+			}
+		})
+	}
+}
+
+func TestTicketCategoriesOf(t *testing.T) {
+	testCases := []struct {
+		label    string
+		expected []TicketCategory
+	}{
+		{label: "Adult", expected: []TicketCategory{AdultTicket}},
+		{label: "Child (5-16 years) / Disabled", expected: []TicketCategory{ChildTicket}},
+		{label: "Over 60 / Student", expected: []TicketCategory{SeniorTicket, StudentTicket}},
+		{label: "Group/Senior", expected: []TicketCategory{SeniorTicket}},
+		{label: "Family (2 adults, up to 3 children)", expected: []TicketCategory{FamilyTicket}},
+		{label: "Groups (15 people or more)", expected: nil},
+	}
+
+	for _, tt := range testCases {
+		currentTT := tt
+		t.Run(currentTT.label, func(t *testing.T) {
+			t.Helper()
+			if diff := cmp.Diff(currentTT.expected, TicketCategoriesOf(currentTT.label)); diff != "" {
+				t.Errorf("diff: %v", diff)
+			}
+		})
+	}
+}
+
+func TestAdmissionMergeWith(t *testing.T) {
+	adult := Price{Category: AdultTicket, Amount: 5, Currency: "EUR"}
+
+	testCases := []struct {
+		name     string
+		a        *Admission
+		o        *Admission
+		expected *Admission
+	}{
+		{
+			name:     "nothing_known",
+			a:        nil,
+			o:        nil,
+			expected: nil,
+		},
+		{
+			name:     "prices_of_o_when_a_has_none",
+			a:        &Admission{BookingURL: "https://trim-castle.ticketsolve.com/"},
+			o:        &Admission{Prices: []Price{adult}, MembershipNotes: "Heritage Card holders go free"},
+			expected: &Admission{Prices: []Price{adult}, MembershipNotes: "Heritage Card holders go free", BookingURL: "https://trim-castle.ticketsolve.com/"},
+		},
+		{
+			name:     "prices_of_a_win",
+			a:        &Admission{Free: true},
+			o:        &Admission{Prices: []Price{adult}, BookingURL: "https://trim-castle.ticketsolve.com/"},
+			expected: &Admission{Free: true, BookingURL: "https://trim-castle.ticketsolve.com/"},
+		},
+	}
+
+	for _, tt := range testCases {
+		currentTT := tt
+		t.Run(currentTT.name, func(t *testing.T) {
+			t.Helper()
+			if diff := cmp.Diff(currentTT.expected, currentTT.a.MergeWith(currentTT.o)); diff != "" {
+				t.Errorf("diff: %v", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,156 @@
+package castle
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/buarki/find-castles/toascii"
+)
+
+const (
+	// about 39km x 20km, coarse enough to group castles of the same area
+	blockingGeohashPrecision = 4
+	minBlockingTokenLength   = 3
+)
+
+var (
+	nonAlphanumericPattern = regexp.MustCompile(`[^a-z0-9]+`)
+
+	// words too common on castle names to tell castles apart
+	blockingStopwords = map[string]bool{
+		"the": true, "and": true, "of": true,
+		"de": true, "do": true, "da": true, "dos": true, "das": true,
+	}
+)
+
+// NameTokens returns the ASCII words of every filtered name that are long
+// and specific enough to be used to find candidates of the same castle.
+func (m Model) NameTokens() []string {
+	var tokens []string
+	seen := make(map[string]bool)
+	for _, name := range m.filteredNames() {
+		asciiName, err := toascii.From(name)
+		if err != nil {
+			asciiName = name
+		}
+		for _, token := range nonAlphanumericPattern.Split(strings.ToLower(asciiName), -1) {
+			if len(token) < minBlockingTokenLength || blockingStopwords[token] || seen[token] {
+				continue
+			}
+			seen[token] = true
+			tokens = append(tokens, token)
+		}
+	}
+	return tokens
+}
+
+// BlockingKeys are cheap keys used to retrieve castles that could be the same
+// as this one: its name tokens, their phonetic codes and the coarse geohash of
+// its location, all scoped by country.
+func (m Model) BlockingKeys() []string {
+	keySet := make(map[string]bool)
+	for _, token := range m.NameTokens() {
+		keySet[fmt.Sprintf("%s:n:%s", m.Country, token)] = true
+		if code := Soundex(token); code != "" {
+			keySet[fmt.Sprintf("%s:p:%s", m.Country, code)] = true
+		}
+	}
+	if location, ok := m.Location(); ok {
+		keySet[fmt.Sprintf("%s:g:%s", m.Country, location.Geohash(blockingGeohashPrecision))] = true
+	}
+
+	keys := make([]string, 0, len(keySet))
+	for key := range keySet {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+// CandidateIndex indexes castles by their blocking keys to avoid comparing
+// every castle against every other.
+type CandidateIndex struct {
+	castles  []Model
+	byKey    map[string][]int
+	byID     map[string]int
+	bySource map[string]int
+}
+
+func NewCandidateIndex(castles []Model) *CandidateIndex {
+	index := &CandidateIndex{
+		castles:  castles,
+		byKey:    make(map[string][]int),
+		byID:     make(map[string]int),
+		bySource: make(map[string]int),
+	}
+	for i, c := range castles {
+		for _, key := range c.BlockingKeys() {
+			index.byKey[key] = append(index.byKey[key], i)
+		}
+		if c.ID != "" {
+			index.byID[c.ID] = i
+		}
+		for _, source := range c.Sources {
+			if _, found := index.bySource[source]; !found {
+				index.bySource[source] = i
+			}
+		}
+	}
+	return index
+}
+
+// BestMatch returns, among the indexed castles that are probably the same as
+// the given one, the one sharing more blocking keys with it.
+func (ci *CandidateIndex) BestMatch(m Model) (Model, bool) {
+	return ci.bestMatch(m, Identities{})
+}
+
+// Resolve returns the indexed castle the given one is. Asserted identities
+// come first, then the same ID, then the same source link and at last the
+// best match, never returning a castle asserted to not be the given one.
+// Castles found before the best match are the same even if they don't look
+// probably the same, so they must be merged with Model.MergeWith.
+func (ci *CandidateIndex) Resolve(m Model, identities Identities) (Model, bool) {
+	if castleID, found := identities.SameAs(m); found {
+		if i, found := ci.byID[castleID]; found {
+			return ci.castles[i], true
+		}
+	}
+	if m.ID != "" {
+		if i, found := ci.byID[m.ID]; found {
+			return ci.castles[i], true
+		}
+	}
+	for _, source := range m.Sources {
+		if i, found := ci.bySource[source]; found && !identities.NotSameAs(m, ci.castles[i].ID) {
+			return ci.castles[i], true
+		}
+	}
+	return ci.bestMatch(m, identities)
+}
+
+func (ci *CandidateIndex) bestMatch(m Model, identities Identities) (Model, bool) {
+	sharedKeys := make(map[int]int)
+	for _, key := range m.BlockingKeys() {
+		for _, i := range ci.byKey[key] {
+			sharedKeys[i]++
+		}
+	}
+
+	bestIndex, bestScore := -1, 0
+	for i, score := range sharedKeys {
+		if !m.IsProbably(ci.castles[i]) || identities.NotSameAs(m, ci.castles[i].ID) {
+			continue
+		}
+		// ties are broken by position to keep results deterministic
+		if score > bestScore || (score == bestScore && i < bestIndex) {
+			bestIndex, bestScore = i, score
+		}
+	}
+	if bestIndex < 0 {
+		return Model{}, false
+	}
+	return ci.castles[bestIndex], true
+}
@@ -0,0 +1,98 @@
+package castle
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestSoundex(t *testing.T) {
+	testCases := []struct {
+		word     string
+		expected string
+	}{
+		{word: "Robert", expected: "R163"},
+		{word: "Rupert", expected: "R163"},
+		{word: "Ashcraft", expected: "A261"},
+		{word: "Tymczak", expected: "T522"},
+		{word: "Pfister", expected: "P236"},
+		{word: "Carrickfergus", expected: "C621"},
+		{word: "Carickfergus", expected: "C621"},
+		{word: "Ó", expected: ""},
+	}
+
+	for _, tt := range testCases {
+		if received := Soundex(tt.word); received != tt.expected {
+			t.Errorf("expected soundex of [%s] to be [%s], got [%s]", tt.word, tt.expected, received)
+		}
+	}
+}
+
+func TestBlockingKeys(t *testing.T) {
+	c := Model{
+		Name:        "Castelo de Guimarães",
+		Country:     Portugal,
+		Coordinates: "41.448,-8.290",
+	}
+
+	keys := c.BlockingKeys()
+
+	for _, expected := range []string{"pt:n:guimaraes", "pt:p:G562", "pt:g:ez65"} {
+		if !slices.Contains(keys, expected) {
+			t.Errorf("expected key [%s] to be present on %v", expected, keys)
+		}
+	}
+	for _, key := range keys {
+		if key == "pt:n:de" || key == "pt:n:castelo" {
+			t.Errorf("expected key [%s] to not be present", key)
+		}
+	}
+}
+
+func TestCandidateIndexBestMatch(t *testing.T) {
+	index := NewCandidateIndex([]Model{
+		{Name: "kirby muxloe", Country: UK},
+		{Name: "windsor", Country: UK},
+		{Name: "windsor", Country: Ireland},
+	})
+
+	testCases := []struct {
+		name     string
+		castle   Model
+		found    bool
+		expected Model
+	}{
+		{
+			name:     "when there is a castle with the same name",
+			castle:   Model{Name: "Windsor Castle", Country: UK},
+			found:    true,
+			expected: Model{Name: "windsor", Country: UK},
+		},
+		{
+			name:     "when there is a castle with a similar name",
+			castle:   Model{Name: "Kirby Castle", Country: UK},
+			found:    true,
+			expected: Model{Name: "kirby muxloe", Country: UK},
+		},
+		{
+			name:   "when no castle shares any key",
+			castle: Model{Name: "Bamburgh Castle", Country: UK},
+			found:  false,
+		},
+	}
+
+	for _, tt := range testCases {
+		currentTT := tt
+		t.Run(currentTT.name, func(t *testing.T) {
+			t.Helper()
+
+			received, found := index.BestMatch(currentTT.castle)
+
+			if found != currentTT.found {
+				t.Fatalf("expected found to be [%v], got [%v]", currentTT.found, found)
+			}
+			if received.Name != currentTT.expected.Name || received.Country != currentTT.expected.Country {
+				t.Errorf("expected [%v], got [%v]", currentTT.expected, received)
+			}
+		})
+	}
+}
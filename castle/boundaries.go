@@ -0,0 +1,222 @@
+package castle
+
+import (
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+)
+
+const (
+	// boundaries are simplified, so castles on the coast or on small islands
+	// may be a bit outside them
+	boundaryToleranceInKm = 10.0
+
+	kmPerLatitudeDegree           = 110.57
+	kmPerLongitudeDegreeOnEquator = 111.32
+)
+
+var (
+	// simplified outlines of each country, as GeoJSON MultiPolygon
+	// coordinates: longitude first
+	//go:embed boundaries.json
+	boundariesData []byte
+
+	defaultBoundaries = mustParseBoundaries(boundariesData)
+
+	ErrInvalidBoundaries = errors.New("invalid boundaries")
+)
+
+// CoordinatesCheck is what checking coordinates against the boundaries of
+// the country of the castle told about them.
+type CoordinatesCheck string
+
+const (
+	CoordinatesValid CoordinatesCheck = "valid"
+	// the castle has no coordinates
+	CoordinatesMissing     CoordinatesCheck = "missing"
+	CoordinatesUnparseable CoordinatesCheck = "unparseable"
+	// latitude and longitude were swapped or had a sign flipped, and only
+	// one way of fixing them puts them in the country
+	CoordinatesCorrected CoordinatesCheck = "corrected"
+	// outside the country, and no way or more than one of fixing them puts
+	// them in it
+	CoordinatesOutsideCountry CoordinatesCheck = "outsideCountry"
+	// there are no boundaries of the country to check against
+	CoordinatesUnchecked CoordinatesCheck = "unchecked"
+)
+
+func (c CoordinatesCheck) String() string {
+	return string(c)
+}
+
+// IsProblem tells whether the coordinates need someone to look at them.
+func (c CoordinatesCheck) IsProblem() bool {
+	return c == CoordinatesUnparseable || c == CoordinatesOutsideCountry
+}
+
+// ring is a closed list of [longitude, latitude] points.
+type ring [][2]float64
+
+// Boundaries are the outlines of countries, each one a set of rings, like
+// the mainland and the islands of Portugal.
+type Boundaries struct {
+	countries map[Country][]ring
+}
+
+// ParseBoundaries reads the outlines of each country from JSON.
+func ParseBoundaries(data []byte) (*Boundaries, error) {
+	var countries map[Country][]ring
+	if err := json.Unmarshal(data, &countries); err != nil {
+		return nil, fmt.Errorf("failed to parse boundaries, got %v", err)
+	}
+	return NewBoundaries(countries)
+}
+
+func mustParseBoundaries(data []byte) *Boundaries {
+	boundaries, err := ParseBoundaries(data)
+	if err != nil {
+		panic(err)
+	}
+	return boundaries
+}
+
+// NewBoundaries fails on rings that aren't closed or have points out of range.
+func NewBoundaries(countries map[Country][]ring) (*Boundaries, error) {
+	for country, rings := range countries {
+		for i, r := range rings {
+			if len(r) < 4 || r[0] != r[len(r)-1] {
+				return nil, fmt.Errorf("%w: ring %d of [%s] must be closed and have at least 3 points", ErrInvalidBoundaries, i, country)
+			}
+			for _, point := range r {
+				if point[0] < -180 || point[0] > 180 || point[1] < -90 || point[1] > 90 {
+					return nil, fmt.Errorf("%w: point %v of [%s] out of range", ErrInvalidBoundaries, point, country)
+				}
+			}
+		}
+	}
+	return &Boundaries{countries: countries}, nil
+}
+
+// Contains tells whether the coordinates are in the country, or close
+// enough to it. The second value is false for countries without boundaries.
+func (b *Boundaries) Contains(country Country, c Coordinates) (bool, bool) {
+	rings, found := b.countries[country]
+	if !found {
+		return false, false
+	}
+	for _, r := range rings {
+		if r.contains(c) || r.distanceInKm(c) <= boundaryToleranceInKm {
+			return true, true
+		}
+	}
+	return false, true
+}
+
+// Check checks the coordinates are in the country, correcting them when
+// they were swapped or had a sign flipped and only one correction puts them
+// in it, like an Irish castle missing the W of its longitude.
+func (b *Boundaries) Check(country Country, c Coordinates) (Coordinates, CoordinatesCheck) {
+	inside, known := b.Contains(country, c)
+	switch {
+	case !known:
+		return c, CoordinatesUnchecked
+	case inside:
+		return c, CoordinatesValid
+	}
+
+	var corrections []Coordinates
+	for _, candidate := range correctionsOf(c) {
+		if inside, _ := b.Contains(country, candidate); inside {
+			corrections = append(corrections, candidate)
+		}
+	}
+	if len(corrections) != 1 {
+		return c, CoordinatesOutsideCountry
+	}
+	return corrections[0], CoordinatesCorrected
+}
+
+// CheckCoordinates checks the coordinates with the bundled boundaries.
+func CheckCoordinates(country Country, c Coordinates) (Coordinates, CoordinatesCheck) {
+	return defaultBoundaries.Check(country, c)
+}
+
+// correctionsOf are the coordinates swapped, with a sign flipped or both,
+// without repeating any, as flipping the sign of 0 changes nothing.
+func correctionsOf(c Coordinates) []Coordinates {
+	var corrections []Coordinates
+	add := func(candidate Coordinates) {
+		if candidate == c || candidate.Latitude < -90 || candidate.Latitude > 90 {
+			return
+		}
+		for _, correction := range corrections {
+			if correction == candidate {
+				return
+			}
+		}
+		corrections = append(corrections, candidate)
+	}
+	for _, base := range []Coordinates{c, {Latitude: c.Longitude, Longitude: c.Latitude}} {
+		add(base)
+		add(Coordinates{Latitude: base.Latitude, Longitude: -base.Longitude})
+		add(Coordinates{Latitude: -base.Latitude, Longitude: base.Longitude})
+		add(Coordinates{Latitude: -base.Latitude, Longitude: -base.Longitude})
+	}
+	return corrections
+}
+
+// contains casts a ray from the point and counts the edges it crosses.
+func (r ring) contains(c Coordinates) bool {
+	inside := false
+	for i, j := 0, len(r)-1; i < len(r); j, i = i, i+1 {
+		xi, yi := r[i][0], r[i][1]
+		xj, yj := r[j][0], r[j][1]
+		if (yi > c.Latitude) != (yj > c.Latitude) &&
+			c.Longitude < (xj-xi)*(c.Latitude-yi)/(yj-yi)+xi {
+			inside = !inside
+		}
+	}
+	return inside
+}
+
+// distanceInKm is the distance to the nearest edge of the ring, projecting
+// the points around the coordinates onto a plane, which is precise enough
+// over the few kilometers of the tolerance.
+func (r ring) distanceInKm(c Coordinates) float64 {
+	kmPerLongitudeDegree := kmPerLongitudeDegreeOnEquator * math.Cos(c.Latitude*math.Pi/180)
+	project := func(point [2]float64) (float64, float64) {
+		return (point[0] - c.Longitude) * kmPerLongitudeDegree, (point[1] - c.Latitude) * kmPerLatitudeDegree
+	}
+	nearest := math.Inf(1)
+	for i := 1; i < len(r); i++ {
+		ax, ay := project(r[i-1])
+		bx, by := project(r[i])
+		// the point is the origin of the plane
+		dx, dy := bx-ax, by-ay
+		t := 0.0
+		if length := dx*dx + dy*dy; length > 0 {
+			t = math.Max(0, math.Min(1, -(ax*dx+ay*dy)/length))
+		}
+		nearest = math.Min(nearest, math.Hypot(ax+t*dx, ay+t*dy))
+	}
+	return nearest
+}
+
+// ValidateCoordinates checks the coordinates of the castle are in its
+// country, correcting them when it is unambiguous how.
+func (m *Model) ValidateCoordinates() CoordinatesCheck {
+	if m.Coordinates == "" {
+		return CoordinatesMissing
+	}
+	location, err := ParseCoordinates(m.Coordinates)
+	if err != nil {
+		return CoordinatesUnparseable
+	}
+	checked, check := CheckCoordinates(m.Country, location)
+	if check == CoordinatesCorrected {
+		m.Coordinates = checked.String()
+	}
+	return check
+}
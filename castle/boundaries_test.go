@@ -0,0 +1,140 @@
+package castle
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCheckCoordinates(t *testing.T) {
+	testCases := []struct {
+		name          string
+		country       Country
+		coordinates   Coordinates
+		expected      Coordinates
+		expectedCheck CoordinatesCheck
+	}{
+		{
+			name: "inside", country: Ireland,
+			coordinates:   Coordinates{Latitude: 53.5545, Longitude: -6.7896},
+			expected:      Coordinates{Latitude: 53.5545, Longitude: -6.7896},
+			expectedCheck: CoordinatesValid,
+		},
+		{
+			name: "on_the_coast_outside_the_simplified_boundary", country: UK,
+			coordinates:   Coordinates{Latitude: 55.6090, Longitude: -1.7099},
+			expected:      Coordinates{Latitude: 55.6090, Longitude: -1.7099},
+			expectedCheck: CoordinatesValid,
+		},
+		{
+			name: "on_an_island", country: Portugal,
+			coordinates:   Coordinates{Latitude: 32.6459, Longitude: -16.9089},
+			expected:      Coordinates{Latitude: 32.6459, Longitude: -16.9089},
+			expectedCheck: CoordinatesValid,
+		},
+		{
+			name: "missing_the_west_sign", country: Ireland,
+			coordinates:   Coordinates{Latitude: 53.5545, Longitude: 6.7896},
+			expected:      Coordinates{Latitude: 53.5545, Longitude: -6.7896},
+			expectedCheck: CoordinatesCorrected,
+		},
+		{
+			name: "swapped", country: Slovakia,
+			coordinates:   Coordinates{Latitude: 18.5780, Longitude: 48.7800},
+			expected:      Coordinates{Latitude: 48.7800, Longitude: 18.5780},
+			expectedCheck: CoordinatesCorrected,
+		},
+		{
+			name: "swapped_along_with_the_sign", country: Portugal,
+			coordinates:   Coordinates{Latitude: -9.1393, Longitude: 38.7223},
+			expected:      Coordinates{Latitude: 38.7223, Longitude: -9.1393},
+			expectedCheck: CoordinatesCorrected,
+		},
+		{
+			name: "on_another_country", country: Ireland,
+			coordinates:   Coordinates{Latitude: 38.7223, Longitude: -9.1393},
+			expected:      Coordinates{Latitude: 38.7223, Longitude: -9.1393},
+			expectedCheck: CoordinatesOutsideCountry,
+		},
+		{
+			name: "country_without_boundaries", country: Country("fr"),
+			coordinates:   Coordinates{Latitude: 48.8, Longitude: 2.3},
+			expected:      Coordinates{Latitude: 48.8, Longitude: 2.3},
+			expectedCheck: CoordinatesUnchecked,
+		},
+	}
+
+	for _, tt := range testCases {
+		currentTT := tt
+		t.Run(currentTT.name, func(t *testing.T) {
+			t.Helper()
+			checked, check := CheckCoordinates(currentTT.country, currentTT.coordinates)
+
+			if check != currentTT.expectedCheck {
+				t.Errorf("expected check [%s], got [%s]", currentTT.expectedCheck, check)
+			}
+			if checked != currentTT.expected {
+				t.Errorf("expected coordinates [%v], got [%v]", currentTT.expected, checked)
+			}
+		})
+	}
+}
+
+func TestCheckCoordinatesDoesNotGuessAmongCorrections(t *testing.T) {
+	boundaries, err := NewBoundaries(map[Country][]ring{
+		Country("xx"): {
+			{{20, 10}, {21, 10}, {21, 11}, {20, 11}, {20, 10}},
+			{{-11, 20}, {-10, 20}, {-10, 21}, {-11, 21}, {-11, 20}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("expected no err, got %v", err)
+	}
+
+	// flipping the longitude and swapping with both signs flipped are both in the country
+	_, check := boundaries.Check(Country("xx"), Coordinates{Latitude: 10.5, Longitude: -20.5})
+
+	if check != CoordinatesOutsideCountry {
+		t.Errorf("expected check [%s], got [%s]", CoordinatesOutsideCountry, check)
+	}
+}
+
+func TestNewBoundariesRejectsOpenRings(t *testing.T) {
+	_, err := NewBoundaries(map[Country][]ring{
+		Ireland: {{{-10, 51}, {-6, 51}, {-6, 55}}},
+	})
+
+	if !errors.Is(err, ErrInvalidBoundaries) {
+		t.Errorf("expected err [%v], got [%v]", ErrInvalidBoundaries, err)
+	}
+}
+
+func TestValidateCoordinates(t *testing.T) {
+	testCases := []struct {
+		name                string
+		castle              Model
+		expectedCoordinates string
+		expectedCheck       CoordinatesCheck
+	}{
+		{name: "valid", castle: Model{Country: Ireland, Coordinates: "53.5545,-6.7896"}, expectedCoordinates: "53.5545,-6.7896", expectedCheck: CoordinatesValid},
+		{name: "corrected", castle: Model{Country: Ireland, Coordinates: "53.5545,6.7896"}, expectedCoordinates: "53.554500,-6.789600", expectedCheck: CoordinatesCorrected},
+		{name: "outside", castle: Model{Country: Ireland, Coordinates: "38.7223,-9.1393"}, expectedCoordinates: "38.7223,-9.1393", expectedCheck: CoordinatesOutsideCountry},
+		{name: "unparseable", castle: Model{Country: Ireland, Coordinates: "somewhere"}, expectedCoordinates: "somewhere", expectedCheck: CoordinatesUnparseable},
+		{name: "missing", castle: Model{Country: Ireland}, expectedCheck: CoordinatesMissing},
+	}
+
+	for _, tt := range testCases {
+		currentTT := tt
+		t.Run(currentTT.name, func(t *testing.T) {
+			t.Helper()
+			c := currentTT.castle
+			check := c.ValidateCoordinates()
+
+			if check != currentTT.expectedCheck {
+				t.Errorf("expected check [%s], got [%s]", currentTT.expectedCheck, check)
+			}
+			if c.Coordinates != currentTT.expectedCoordinates {
+				t.Errorf("expected coordinates [%s], got [%s]", currentTT.expectedCoordinates, c.Coordinates)
+			}
+		})
+	}
+}
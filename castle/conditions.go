@@ -1,20 +1,100 @@
 package castle
 
+import "slices"
+
+// PropertyCondition tells in what state a castle is today. Unknown, Ruins,
+// Damaged and Intact are also the coarse conditions the others are
+// projected into, see PropertyCondition.Coarse.
 type PropertyCondition string
 
 const (
 	Unknown PropertyCondition = "unknown"
 	Ruins   PropertyCondition = "ruins"
+	// standing, but not kept as it was
 	Damaged PropertyCondition = "damaged"
 	Intact  PropertyCondition = "intact"
+
+	// intact after being repaired as it was
+	Restored PropertyCondition = "restored"
+	// built again, often in a different style, like historicized castles
+	Rebuilt PropertyCondition = "rebuilt"
+	// built over or used for something else, like a hotel or a church
+	Converted    PropertyCondition = "converted"
+	PartialRuins PropertyCondition = "partialRuins"
+	// only the foundations of the walls are left
+	FoundationsOnly PropertyCondition = "foundationsOnly"
+	// nothing stands, only earthworks or excavated remains
+	ArchaeologicalSite PropertyCondition = "archaeologicalSite"
+	Destroyed          PropertyCondition = "destroyed"
+	// under the water of a reservoir or of the sea
+	Submerged PropertyCondition = "submerged"
+)
+
+var (
+	PropertyConditions = []PropertyCondition{
+		Unknown,
+		Intact,
+		Restored,
+		Rebuilt,
+		Converted,
+		Damaged,
+		PartialRuins,
+		Ruins,
+		FoundationsOnly,
+		ArchaeologicalSite,
+		Destroyed,
+		Submerged,
+	}
 )
 
 func (pc PropertyCondition) String() string {
 	return string(pc)
 }
 
-func (pc PropertyCondition) ComparisonWeight() int {
+// Coarse projects the condition into Unknown, Ruins, Damaged or Intact.
+func (pc PropertyCondition) Coarse() PropertyCondition {
 	switch pc {
+	case Intact, Restored, Rebuilt, Converted:
+		return Intact
+	case Damaged, PartialRuins:
+		return Damaged
+	case Ruins, FoundationsOnly, ArchaeologicalSite, Destroyed, Submerged:
+		return Ruins
+	default:
+		return Unknown
+	}
+}
+
+// Covered returns the conditions a filter by this one matches, which are the
+// ones projected into it for coarse conditions.
+func (pc PropertyCondition) Covered() []PropertyCondition {
+	if pc.Coarse() != pc {
+		return []PropertyCondition{pc}
+	}
+	var covered []PropertyCondition
+	for _, condition := range PropertyConditions {
+		if condition.Coarse() == pc {
+			covered = append(covered, condition)
+		}
+	}
+	return covered
+}
+
+// CoveredConditions returns the conditions any of the given ones covers.
+func CoveredConditions(conditions []PropertyCondition) []PropertyCondition {
+	var covered []PropertyCondition
+	for _, condition := range conditions {
+		for _, c := range condition.Covered() {
+			if !slices.Contains(covered, c) {
+				covered = append(covered, c)
+			}
+		}
+	}
+	return covered
+}
+
+func (pc PropertyCondition) ComparisonWeight() int {
+	switch pc.Coarse() {
 	case Unknown:
 		return 0
 	case Ruins:
@@ -0,0 +1,100 @@
+package castle
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestPropertyConditionCoarse(t *testing.T) {
+	testCases := []struct {
+		condition PropertyCondition
+		expected  PropertyCondition
+	}{
+		{condition: Intact, expected: Intact},
+		{condition: Restored, expected: Intact},
+		{condition: Rebuilt, expected: Intact},
+		{condition: Converted, expected: Intact},
+		{condition: Damaged, expected: Damaged},
+		{condition: PartialRuins, expected: Damaged},
+		{condition: Ruins, expected: Ruins},
+		{condition: FoundationsOnly, expected: Ruins},
+		{condition: ArchaeologicalSite, expected: Ruins},
+		{condition: Destroyed, expected: Ruins},
+		{condition: Submerged, expected: Ruins},
+		{condition: Unknown, expected: Unknown},
+		{condition: "", expected: Unknown},
+	}
+
+	for _, tt := range testCases {
+		currentTT := tt
+		t.Run(currentTT.condition.String(), func(t *testing.T) {
+			t.Helper()
+			if received := currentTT.condition.Coarse(); received != currentTT.expected {
+				t.Errorf("expected [%s], got [%s]", currentTT.expected, received)
+			}
+		})
+	}
+}
+
+func TestPropertyConditionCovered(t *testing.T) {
+	expected := []PropertyCondition{Intact, Restored, Rebuilt, Converted}
+	if received := Intact.Covered(); !slices.Equal(received, expected) {
+		t.Errorf("expected %v, got %v", expected, received)
+	}
+	if received := Restored.Covered(); !slices.Equal(received, []PropertyCondition{Restored}) {
+		t.Errorf("expected only restored, got %v", received)
+	}
+}
+
+func TestMergeWithPropertyCondition(t *testing.T) {
+	testCases := []struct {
+		name          string
+		m             Model
+		c             Model
+		expected      PropertyCondition
+		expectedLabel string
+	}{
+		{
+			name:          "unknown_takes_the_other",
+			m:             Model{PropertyCondition: Unknown},
+			c:             Model{PropertyCondition: Converted, PropertyConditionLabel: "überbaut"},
+			expected:      Converted,
+			expectedLabel: "überbaut",
+		},
+		{
+			name:          "best_coarse_condition_wins",
+			m:             Model{PropertyCondition: FoundationsOnly, PropertyConditionLabel: "Fundamente"},
+			c:             Model{PropertyCondition: PartialRuins, PropertyConditionLabel: "Bedeutende Reste"},
+			expected:      PartialRuins,
+			expectedLabel: "Bedeutende Reste",
+		},
+		{
+			name:          "more_specific_condition_wins",
+			m:             Model{PropertyCondition: Intact, PropertyConditionLabel: "Boa"},
+			c:             Model{PropertyCondition: Restored, PropertyConditionLabel: "restauriert"},
+			expected:      Restored,
+			expectedLabel: "restauriert",
+		},
+		{
+			name:          "worse_condition_is_ignored",
+			m:             Model{PropertyCondition: Rebuilt, PropertyConditionLabel: "wiederaufgebaut"},
+			c:             Model{PropertyCondition: Ruins, PropertyConditionLabel: "Mau"},
+			expected:      Rebuilt,
+			expectedLabel: "wiederaufgebaut",
+		},
+	}
+
+	for _, tt := range testCases {
+		currentTT := tt
+		t.Run(currentTT.name, func(t *testing.T) {
+			t.Helper()
+			merged := currentTT.m.MergeWith(currentTT.c)
+			if merged.PropertyCondition != currentTT.expected {
+				t.Errorf("expected [%s], got [%s]", currentTT.expected, merged.PropertyCondition)
+			}
+			if merged.PropertyConditionLabel != currentTT.expectedLabel {
+				t.Errorf("expected [%s], got [%s]", currentTT.expectedLabel, merged.PropertyConditionLabel)
+			}
+		})
+	}
+}
@@ -0,0 +1,112 @@
+package castle
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrInvalidCoordinates = errors.New("invalid coordinates")
+
+	// ex: 51°29'0"N, 54.9904°N, -8.3, 00°36'15"W
+	coordinatePattern = regexp.MustCompile(`^([+-]?\d+(?:\.\d+)?)°?(?:(\d+(?:\.\d+)?)['′])?(?:(\d+(?:\.\d+)?)["″])?([NSEWnsew])?$`)
+)
+
+type Coordinates struct {
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+}
+
+func (c Coordinates) String() string {
+	return fmt.Sprintf("%.6f,%.6f", c.Latitude, c.Longitude)
+}
+
+// ParseCoordinates understands the formats the enrichers collect: decimal
+// degrees ("48.780049,18.577476"), decimal degrees with hemisphere
+// ("54.9904°N,2.0000°W") and degrees, minutes and seconds
+// ("51°29'0\"N,00°36'15\"W"). Latitude comes first unless hemispheres say
+// otherwise.
+func ParseCoordinates(raw string) (Coordinates, error) {
+	raw = strings.TrimSpace(raw)
+	var parts []string
+	if strings.Contains(raw, ",") {
+		parts = strings.Split(raw, ",")
+	} else {
+		parts = strings.Fields(raw)
+	}
+	if len(parts) != 2 {
+		return Coordinates{}, fmt.Errorf("%w: [%s]", ErrInvalidCoordinates, raw)
+	}
+
+	first, firstHemisphere, err := parseCoordinate(parts[0])
+	if err != nil {
+		return Coordinates{}, fmt.Errorf("%w: [%s]", ErrInvalidCoordinates, raw)
+	}
+	second, secondHemisphere, err := parseCoordinate(parts[1])
+	if err != nil {
+		return Coordinates{}, fmt.Errorf("%w: [%s]", ErrInvalidCoordinates, raw)
+	}
+
+	c := Coordinates{Latitude: first, Longitude: second}
+	if isLongitudeHemisphere(firstHemisphere) && isLatitudeHemisphere(secondHemisphere) {
+		c = Coordinates{Latitude: second, Longitude: first}
+	}
+	if c.Latitude < -90 || c.Latitude > 90 || c.Longitude < -180 || c.Longitude > 180 {
+		return Coordinates{}, fmt.Errorf("%w: [%s] out of range", ErrInvalidCoordinates, raw)
+	}
+	return c, nil
+}
+
+func parseCoordinate(raw string) (float64, string, error) {
+	matches := coordinatePattern.FindStringSubmatch(strings.ReplaceAll(strings.TrimSpace(raw), " ", ""))
+	if matches == nil {
+		return 0, "", fmt.Errorf("invalid coordinate [%s]", raw)
+	}
+	value, err := strconv.ParseFloat(matches[1], 64)
+	if err != nil {
+		return 0, "", err
+	}
+	negative := value < 0 || strings.HasPrefix(matches[1], "-")
+	if negative {
+		value = -value
+	}
+	if matches[2] != "" {
+		minutes, _ := strconv.ParseFloat(matches[2], 64)
+		value += minutes / 60
+	}
+	if matches[3] != "" {
+		seconds, _ := strconv.ParseFloat(matches[3], 64)
+		value += seconds / 3600
+	}
+	hemisphere := strings.ToUpper(matches[4])
+	if hemisphere == "S" || hemisphere == "W" {
+		negative = !negative
+	}
+	if negative {
+		value = -value
+	}
+	return value, hemisphere, nil
+}
+
+func isLatitudeHemisphere(h string) bool {
+	return h == "N" || h == "S"
+}
+
+func isLongitudeHemisphere(h string) bool {
+	return h == "E" || h == "W"
+}
+
+// Location parses the collected coordinates of the castle.
+func (m Model) Location() (Coordinates, bool) {
+	if m.Coordinates == "" {
+		return Coordinates{}, false
+	}
+	c, err := ParseCoordinates(m.Coordinates)
+	if err != nil {
+		return Coordinates{}, false
+	}
+	return c, true
+}
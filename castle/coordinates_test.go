@@ -0,0 +1,72 @@
+package castle
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestParseCoordinates(t *testing.T) {
+	testCases := []struct {
+		raw      string
+		expected Coordinates
+		err      error
+	}{
+		{
+			raw:      "48.780049,18.577476",
+			expected: Coordinates{Latitude: 48.780049, Longitude: 18.577476},
+		},
+		{
+			raw:      "54.9904°N,2.0000°W",
+			expected: Coordinates{Latitude: 54.9904, Longitude: -2},
+		},
+		{
+			raw:      `51°29'0"N,00°36'15"W`,
+			expected: Coordinates{Latitude: 51.483333, Longitude: -0.604167},
+		},
+		{
+			raw:      "51.9925°N,0.6014°E",
+			expected: Coordinates{Latitude: 51.9925, Longitude: 0.6014},
+		},
+		{
+			raw:      "0.6014°E 51.9925°N",
+			expected: Coordinates{Latitude: 51.9925, Longitude: 0.6014},
+		},
+		{
+			raw: "somewhere",
+			err: ErrInvalidCoordinates,
+		},
+		{
+			raw: "95.1,10.2",
+			err: ErrInvalidCoordinates,
+		},
+	}
+
+	for _, tt := range testCases {
+		currentTT := tt
+		t.Run(currentTT.raw, func(t *testing.T) {
+			t.Helper()
+
+			received, err := ParseCoordinates(currentTT.raw)
+
+			if !errors.Is(err, currentTT.err) {
+				t.Fatalf("expected err [%v], got [%v]", currentTT.err, err)
+			}
+			if math.Abs(received.Latitude-currentTT.expected.Latitude) > 0.000001 ||
+				math.Abs(received.Longitude-currentTT.expected.Longitude) > 0.000001 {
+				t.Errorf("expected [%v], got [%v]", currentTT.expected, received)
+			}
+		})
+	}
+}
+
+func TestGeohash(t *testing.T) {
+	c := Coordinates{Latitude: 57.64911, Longitude: 10.40744}
+
+	if received := c.Geohash(11); received != "u4pruydqqvj" {
+		t.Errorf("expected [u4pruydqqvj], got [%s]", received)
+	}
+	if received := c.Geohash(4); received != "u4pr" {
+		t.Errorf("expected [u4pr], got [%s]", received)
+	}
+}
@@ -1,5 +1,10 @@
 package castle
 
+import (
+	"fmt"
+	"time"
+)
+
 type Country string
 
 const (
@@ -10,6 +15,25 @@ const (
 	Denmark  Country = "dk"
 )
 
+var (
+	timeZones = map[Country]string{
+		Portugal: "Europe/Lisbon",
+		UK:       "Europe/London",
+		Ireland:  "Europe/Dublin",
+		Slovakia: "Europe/Bratislava",
+		Denmark:  "Europe/Copenhagen",
+	}
+)
+
+// Location returns the time zone castles of the country open on.
+func (c Country) Location() (*time.Location, error) {
+	timeZone, found := timeZones[c]
+	if !found {
+		return nil, fmt.Errorf("unknown time zone of country [%s]", c)
+	}
+	return time.LoadLocation(timeZone)
+}
+
 func (c Country) String() string {
 	return string(c)
 }
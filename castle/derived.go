@@ -0,0 +1,88 @@
+package castle
+
+import "slices"
+
+const (
+	DerivedState       = "state"
+	DerivedCity        = "city"
+	DerivedCoordinates = "coordinates"
+)
+
+// derivableField is a field that may be derived instead of told by a
+// source, with how to know it has a value and how to clear it.
+type derivableField struct {
+	has   func(m Model) bool
+	clear func(m *Model)
+}
+
+var derivableFields = map[string]derivableField{
+	DerivedState: {
+		has: func(m Model) bool { return m.State != "" },
+		// the code and label go with the state
+		clear: func(m *Model) { m.State, m.StateCode, m.StateLabel = "", "", "" },
+	},
+	DerivedCity: {
+		has:   func(m Model) bool { return m.City != "" },
+		clear: func(m *Model) { m.City = "" },
+	},
+	DerivedCoordinates: {
+		has:   func(m Model) bool { return m.Coordinates != "" },
+		clear: func(m *Model) { m.Coordinates = "" },
+	},
+}
+
+// IsDerived tells whether the value of the field was derived, like a city
+// geocoded from the coordinates, instead of told by a source.
+func (m Model) IsDerived(field string) bool {
+	return slices.Contains(m.Derived, field)
+}
+
+// MarkDerived marks the value of the field as derived.
+func (m *Model) MarkDerived(field string) {
+	if !m.IsDerived(field) {
+		m.Derived = append(m.Derived, field)
+		slices.Sort(m.Derived)
+	}
+}
+
+// unmarkDerived marks the value of the field as told, like when a curator
+// overrides it.
+func (m *Model) unmarkDerived(field string) {
+	m.Derived = slices.DeleteFunc(slices.Clone(m.Derived), func(derived string) bool {
+		return derived == field
+	})
+	if len(m.Derived) == 0 {
+		m.Derived = nil
+	}
+}
+
+// withoutDerivedToldBy clears the derived values of m that o has told
+// values for, so the told ones are the ones merged.
+func withoutDerivedToldBy(m, o Model) Model {
+	if len(m.Derived) == 0 {
+		return m
+	}
+	cleared := m.Copy()
+	for _, field := range m.Derived {
+		derivable, found := derivableFields[field]
+		if !found || o.IsDerived(field) || !derivable.has(o) {
+			continue
+		}
+		derivable.clear(&cleared)
+		cleared.unmarkDerived(field)
+	}
+	return cleared
+}
+
+// mergeDerived are the fields derived on any of the castles, which
+// withoutDerivedToldBy left only where the other castle has no told value.
+func mergeDerived(m, c Model) []string {
+	var derived []string
+	for _, field := range append(slices.Clone(m.Derived), c.Derived...) {
+		if !slices.Contains(derived, field) {
+			derived = append(derived, field)
+		}
+	}
+	slices.Sort(derived)
+	return derived
+}
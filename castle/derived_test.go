@@ -0,0 +1,90 @@
+package castle
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestMergeWithPrefersToldValuesOverDerivedOnes(t *testing.T) {
+	testCases := []struct {
+		name     string
+		m        Model
+		c        Model
+		expected Model
+	}{
+		{
+			name: "told_city_replaces_derived_one",
+			m:    Model{Name: "trim", Country: Ireland, City: "trim"},
+			c:    Model{Name: "trim", Country: Ireland, City: "navan", Derived: []string{DerivedCity}},
+			expected: Model{
+				Name: "trim", Country: Ireland, City: "trim",
+			},
+		},
+		{
+			name: "derived_city_is_kept_while_nothing_is_told",
+			m:    Model{Name: "trim", Country: Ireland},
+			c:    Model{Name: "trim", Country: Ireland, City: "trim", Derived: []string{DerivedCity}},
+			expected: Model{
+				Name: "trim", Country: Ireland, City: "trim", Derived: []string{DerivedCity},
+			},
+		},
+		{
+			name: "told_state_replaces_derived_one_along_with_its_code",
+			m:    Model{Name: "dunsany", Country: Ireland, State: "berkshire"},
+			c:    Model{Name: "dunsany", Country: Ireland, State: "meath", StateCode: "IE-MH", Derived: []string{DerivedState}},
+			expected: Model{
+				Name: "dunsany", Country: Ireland, State: "berkshire",
+			},
+		},
+		{
+			name: "told_coordinates_replace_longer_derived_ones",
+			m:    Model{Name: "ross", Country: Ireland, Coordinates: "52.04,-9.53"},
+			c:    Model{Name: "ross", Country: Ireland, Coordinates: "52.059900,-9.504400", Derived: []string{DerivedCoordinates}},
+			expected: Model{
+				Name: "ross", Country: Ireland, Coordinates: "52.04,-9.53",
+			},
+		},
+	}
+
+	for _, tt := range testCases {
+		currentTT := tt
+		t.Run(currentTT.name, func(t *testing.T) {
+			t.Helper()
+			if diff := cmp.Diff(currentTT.expected, currentTT.m.MergeWith(currentTT.c)); diff != "" {
+				t.Errorf("diff: %v", diff)
+			}
+			if diff := cmp.Diff(currentTT.expected.Derived, currentTT.c.MergeWith(currentTT.m).Derived); diff != "" {
+				t.Errorf("expected the same derived fields merging the other way, diff: %v", diff)
+			}
+		})
+	}
+}
+
+func TestIsProbablyIgnoresDerivedStatesAndCities(t *testing.T) {
+	told := Model{Name: "ross", Country: Ireland, State: "kerry", StateCode: "IE-KY", City: "killarney"}
+	derived := Model{Name: "ross", Country: Ireland, State: "cork", StateCode: "IE-CO", City: "blarney", Derived: []string{DerivedCity, DerivedState}}
+
+	if !told.IsProbably(derived) {
+		t.Errorf("expected derived state and city not to tell castles apart")
+	}
+}
+
+func TestOverridesAreNotDerived(t *testing.T) {
+	m := Model{Name: "trim", Country: Ireland, City: "navan", Derived: []string{DerivedCity, DerivedState}}
+
+	overridden, err := m.ApplyOverrides([]Override{{Field: "city", Value: "trim", Author: "jane", Reason: "wrong town"}})
+	if err != nil {
+		t.Fatalf("expected no err, got %v", err)
+	}
+
+	if overridden.IsDerived(DerivedCity) {
+		t.Errorf("expected overridden city not to be derived")
+	}
+	if !overridden.IsDerived(DerivedState) {
+		t.Errorf("expected state to still be derived")
+	}
+	if !m.IsDerived(DerivedCity) {
+		t.Errorf("expected castle overridden to be left untouched")
+	}
+}
@@ -0,0 +1,59 @@
+package castle
+
+type FieldChange struct {
+	Field string `json:"field"`
+	Old   string `json:"old"`
+	New   string `json:"new"`
+}
+
+type diffableField struct {
+	name  string
+	value func(m Model) string
+}
+
+var (
+	diffableFields = []diffableField{
+		{name: "name", value: func(m Model) string { return m.Name }},
+		{name: "state", value: func(m Model) string { return m.State }},
+		{name: "city", value: func(m Model) string { return m.City }},
+		{name: "district", value: func(m Model) string { return m.District }},
+		{name: "postcode", value: func(m Model) string {
+			if m.Address == nil {
+				return ""
+			}
+			return m.Address.Postcode
+		}},
+		{name: "foundationPeriod", value: func(m Model) string { return m.FoundationPeriod }},
+		{name: "propertyCondition", value: func(m Model) string { return m.PropertyCondition.String() }},
+		{name: "coordinates", value: func(m Model) string { return m.Coordinates }},
+		{name: "pictureURL", value: func(m Model) string { return m.PictureURL }},
+		{name: "workingHours", value: func(m Model) string {
+			if m.VisitingInfo == nil {
+				return ""
+			}
+			return m.VisitingInfo.WorkingHours
+		}},
+		{name: "openingHours", value: func(m Model) string {
+			if m.VisitingInfo == nil || m.VisitingInfo.OpeningHours == nil {
+				return ""
+			}
+			return m.VisitingInfo.OpeningHours.String()
+		}},
+	}
+)
+
+// Diff lists the fields relevant for visitors that changed from old to new.
+func Diff(old, new Model) []FieldChange {
+	var changes []FieldChange
+	for _, field := range diffableFields {
+		oldValue, newValue := field.value(old), field.value(new)
+		if oldValue != newValue {
+			changes = append(changes, FieldChange{
+				Field: field.name,
+				Old:   oldValue,
+				New:   newValue,
+			})
+		}
+	}
+	return changes
+}
@@ -0,0 +1,76 @@
+package castle
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestDiff(t *testing.T) {
+	testCases := []struct {
+		name     string
+		old      Model
+		new      Model
+		expected []FieldChange
+	}{
+		{
+			name: "when castles are equal",
+			old: Model{
+				Name:    "trim",
+				Country: Ireland,
+				City:    "trim",
+			},
+			new: Model{
+				Name:    "trim",
+				Country: Ireland,
+				City:    "trim",
+			},
+			expected: nil,
+		},
+		{
+			name: "when city and condition changed",
+			old: Model{
+				Name:              "trim",
+				City:              "trim",
+				PropertyCondition: Unknown,
+			},
+			new: Model{
+				Name:              "trim",
+				City:              "co meath",
+				PropertyCondition: Intact,
+			},
+			expected: []FieldChange{
+				{Field: "city", Old: "trim", New: "co meath"},
+				{Field: "propertyCondition", Old: "unknown", New: "intact"},
+			},
+		},
+		{
+			name: "when opening hours were added",
+			old: Model{
+				Name: "trim",
+			},
+			new: Model{
+				Name: "trim",
+				VisitingInfo: &VisitingInfo{
+					WorkingHours: "09:30 - 16:00",
+				},
+			},
+			expected: []FieldChange{
+				{Field: "workingHours", Old: "", New: "09:30 - 16:00"},
+			},
+		},
+	}
+
+	for _, tt := range testCases {
+		currentTT := tt
+		t.Run(currentTT.name, func(t *testing.T) {
+			t.Helper()
+
+			changes := Diff(currentTT.old, currentTT.new)
+
+			if diff := cmp.Diff(currentTT.expected, changes); diff != "" {
+				t.Errorf("unexpected changes (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
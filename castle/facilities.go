@@ -0,0 +1,117 @@
+package castle
+
+import "slices"
+
+// Facility is the JSON name of something a castle may offer its visitors.
+type Facility string
+
+const (
+	AssistanceDogsAllowed Facility = "assistanceDogsAllowed"
+	Cafe                  Facility = "cafe"
+	Restrooms             Facility = "restrooms"
+	Giftshops             Facility = "giftshops"
+	PinicArea             Facility = "pinicArea"
+	Parking               Facility = "parking"
+	Exhibitions           Facility = "exhibitions"
+	WheelchairSupport     Facility = "wheelchairSupport"
+	AudioGuide            Facility = "audioGuide"
+	GuidedTours           Facility = "guidedTours"
+	FamilyFriendly        Facility = "familyFriendly"
+	AccessibleToilets     Facility = "accessibleToilets"
+	EVCharging            Facility = "evCharging"
+	// visitors pay to get in
+	AdmissionFee Facility = "admissionFee"
+)
+
+var (
+	AllFacilities = []Facility{
+		AssistanceDogsAllowed,
+		Cafe,
+		Restrooms,
+		Giftshops,
+		PinicArea,
+		Parking,
+		Exhibitions,
+		WheelchairSupport,
+		AudioGuide,
+		GuidedTours,
+		FamilyFriendly,
+		AccessibleToilets,
+		EVCharging,
+		AdmissionFee,
+	}
+)
+
+func (f Facility) IsKnown() bool {
+	return slices.Contains(AllFacilities, f)
+}
+
+// Availability tells whether a facility is available, telling a source that
+// says it isn't apart from a source that says nothing about it.
+type Availability string
+
+const (
+	AvailabilityUnknown Availability = "unknown"
+	Available           Availability = "yes"
+	NotAvailable        Availability = "no"
+)
+
+// FacilityInfo is what a source told about a facility.
+type FacilityInfo struct {
+	Availability Availability `json:"availability"`
+	// link of the page that told it, empty when set by hand
+	Source string `json:"source"`
+}
+
+// Facilities only has the facilities something is known about.
+type Facilities map[Facility]FacilityInfo
+
+func (f Facilities) Availability(facility Facility) Availability {
+	info, found := f[facility]
+	if !found || info.Availability == "" {
+		return AvailabilityUnknown
+	}
+	return info.Availability
+}
+
+// Has tells whether the facility is known to be available.
+func (f Facilities) Has(facility Facility) bool {
+	return f.Availability(facility) == Available
+}
+
+// Set records what the source tells about the facility, setting it as
+// unknown forgets it.
+func (f *Facilities) Set(facility Facility, availability Availability, source string) {
+	if availability == AvailabilityUnknown || availability == "" {
+		delete(*f, facility)
+		return
+	}
+	if *f == nil {
+		*f = make(Facilities)
+	}
+	(*f)[facility] = FacilityInfo{Availability: availability, Source: source}
+}
+
+func (f Facilities) Copy() Facilities {
+	if f == nil {
+		return nil
+	}
+	newFacilities := make(Facilities, len(f))
+	for facility, info := range f {
+		newFacilities[facility] = info
+	}
+	return newFacilities
+}
+
+// MergeWith takes what o knows about the facilities f knows nothing about.
+// When both know about a facility and disagree, f wins, as castles just
+// enriched are merged with the saved ones.
+func (f Facilities) MergeWith(o Facilities) Facilities {
+	merged := f.Copy()
+	for facility, info := range o {
+		if merged.Availability(facility) == AvailabilityUnknown {
+			merged.Set(facility, info.Availability, info.Source)
+		}
+	}
+	return merged
+}
@@ -0,0 +1,76 @@
+package castle
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestFacilitiesMergeWith(t *testing.T) {
+	const (
+		heritage = "https://heritageireland.ie/visit/places-to-visit/trim-castle/"
+		ebidat   = "https://www.ebidat.de/cgi-bin/ebidat.pl?id=1"
+	)
+
+	testCases := []struct {
+		name     string
+		f        Facilities
+		o        Facilities
+		expected Facilities
+	}{
+		{
+			name:     "unknown_is_neutral",
+			f:        nil,
+			o:        Facilities{Parking: {Availability: Available, Source: heritage}},
+			expected: Facilities{Parking: {Availability: Available, Source: heritage}},
+		},
+		{
+			name:     "known_facilities_are_kept",
+			f:        Facilities{Cafe: {Availability: NotAvailable, Source: ebidat}},
+			o:        Facilities{Parking: {Availability: Available, Source: heritage}},
+			expected: Facilities{Cafe: {Availability: NotAvailable, Source: ebidat}, Parking: {Availability: Available, Source: heritage}},
+		},
+		{
+			name:     "conflicts_keep_the_first",
+			f:        Facilities{Cafe: {Availability: NotAvailable, Source: ebidat}},
+			o:        Facilities{Cafe: {Availability: Available, Source: heritage}},
+			expected: Facilities{Cafe: {Availability: NotAvailable, Source: ebidat}},
+		},
+	}
+
+	for _, tt := range testCases {
+		currentTT := tt
+		t.Run(currentTT.name, func(t *testing.T) {
+			t.Helper()
+			if diff := cmp.Diff(currentTT.expected, currentTT.f.MergeWith(currentTT.o)); diff != "" {
+				t.Errorf("diff: %v", diff)
+			}
+		})
+	}
+}
+
+func TestMergeWithKeepsFacilitiesOfSourcesTellingThem(t *testing.T) {
+	enriched := Model{Name: "trim", Country: Ireland, VisitingInfo: &VisitingInfo{WorkingHours: "10:00 to 17:00"}}
+	saved := Model{Name: "trim", Country: Ireland, VisitingInfo: &VisitingInfo{
+		Facilities: Facilities{Parking: {Availability: Available, Source: "https://heritageireland.ie/"}},
+	}}
+
+	merged := enriched.MergeWith(saved)
+
+	if !merged.VisitingInfo.Facilities.Has(Parking) {
+		t.Errorf("expected parking to be kept, got %v", merged.VisitingInfo.Facilities)
+	}
+	if availability := merged.VisitingInfo.Facilities.Availability(Cafe); availability != AvailabilityUnknown {
+		t.Errorf("expected cafe to be unknown, got [%s]", availability)
+	}
+}
+
+func TestFacilitiesSetUnknownForgetsIt(t *testing.T) {
+	var f Facilities
+	f.Set(Cafe, Available, "")
+	f.Set(Cafe, AvailabilityUnknown, "")
+
+	if len(f) != 0 {
+		t.Errorf("expected no facilities, got %v", f)
+	}
+}
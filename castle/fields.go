@@ -0,0 +1,298 @@
+package castle
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	// URLs never contain spaces, so sources are joined by them in a single field
+	sourcesSeparator = " "
+	// names may have spaces and commas, but hardly a pipe
+	namesSeparator = " | "
+
+	localizedNamesFieldPrefix = "localizedNames."
+
+	facilitiesFieldPrefix = "visitingInfo.facilities."
+)
+
+var (
+	ErrUnknownField = errors.New("unknown castle field")
+)
+
+// flatField is a castle field handled by its JSON path, ex: contact.phone,
+// wherever castles are read or written field by field.
+type flatField struct {
+	name string
+	get  func(m Model) string
+	set  func(m *Model, value string) error
+}
+
+var (
+	flatFields = append([]flatField{
+		{
+			name: "id",
+			get:  func(m Model) string { return m.ID },
+			set:  func(m *Model, v string) error { m.ID = v; return nil },
+		},
+		{
+			name: "name",
+			get:  func(m Model) string { return m.Name },
+			set:  func(m *Model, v string) error { m.Name = v; return nil },
+		},
+		{
+			name: "country",
+			get:  func(m Model) string { return m.Country.String() },
+			set:  func(m *Model, v string) error { m.Country = Country(v); return nil },
+		},
+		{
+			name: "sources",
+			get:  func(m Model) string { return strings.Join(m.Sources, sourcesSeparator) },
+			set:  func(m *Model, v string) error { m.Sources = strings.Fields(v); return nil },
+		},
+		{
+			name: "alternateNames",
+			get:  func(m Model) string { return strings.Join(m.AlternateNames, namesSeparator) },
+			set: func(m *Model, v string) error {
+				m.AlternateNames = nil
+				for _, name := range strings.Split(v, strings.TrimSpace(namesSeparator)) {
+					if name = strings.TrimSpace(name); name != "" {
+						m.AlternateNames = append(m.AlternateNames, name)
+					}
+				}
+				return nil
+			},
+		},
+		{
+			name: "state",
+			get:  func(m Model) string { return m.State },
+			// the code and label of the state before would contradict the new one
+			set: func(m *Model, v string) error {
+				m.State, m.StateCode, m.StateLabel = v, "", ""
+				m.ResolveState()
+				return nil
+			},
+		},
+		{
+			name: "city",
+			get:  func(m Model) string { return m.City },
+			set:  func(m *Model, v string) error { m.City = v; return nil },
+		},
+		{
+			name: "district",
+			get:  func(m Model) string { return m.District },
+			set:  func(m *Model, v string) error { m.District = v; return nil },
+		},
+		{
+			name: "foundationPeriod",
+			get:  func(m Model) string { return m.FoundationPeriod },
+			set:  func(m *Model, v string) error { m.FoundationPeriod = v; return nil },
+		},
+		{
+			name: "propertyCondition",
+			get:  func(m Model) string { return m.PropertyCondition.String() },
+			set:  func(m *Model, v string) error { m.PropertyCondition = PropertyCondition(v); return nil },
+		},
+		{
+			name: "propertyConditionLabel",
+			get:  func(m Model) string { return m.PropertyConditionLabel },
+			set:  func(m *Model, v string) error { m.PropertyConditionLabel = v; return nil },
+		},
+		{
+			name: "coordinates",
+			get:  func(m Model) string { return m.Coordinates },
+			set:  func(m *Model, v string) error { m.Coordinates = v; return nil },
+		},
+		{
+			name: "derived",
+			get:  func(m Model) string { return strings.Join(m.Derived, " ") },
+			set: func(m *Model, v string) error {
+				m.Derived = nil
+				for _, field := range strings.Fields(v) {
+					if _, found := derivableFields[field]; !found {
+						return fmt.Errorf("field [%s] can't be derived", field)
+					}
+					m.MarkDerived(field)
+				}
+				return nil
+			},
+		},
+		{
+			name: "pictureURL",
+			get:  func(m Model) string { return m.PictureURL },
+			set:  func(m *Model, v string) error { m.PictureURL = v; return nil },
+		},
+		{
+			name: "status",
+			get:  func(m Model) string { return m.Status.String() },
+			set:  func(m *Model, v string) error { m.Status = Status(v); return nil },
+		},
+		{
+			name: "contact.phone",
+			get: func(m Model) string {
+				if m.Contact == nil {
+					return ""
+				}
+				return m.Contact.Phone
+			},
+			set: func(m *Model, v string) error {
+				if v != "" || m.Contact != nil {
+					m.contact().Phone = v
+				}
+				return nil
+			},
+		},
+		{
+			name: "contact.email",
+			get: func(m Model) string {
+				if m.Contact == nil {
+					return ""
+				}
+				return m.Contact.Email
+			},
+			set: func(m *Model, v string) error {
+				if v != "" || m.Contact != nil {
+					m.contact().Email = v
+				}
+				return nil
+			},
+		},
+		{
+			name: "visitingInfo.workingHours",
+			get: func(m Model) string {
+				if m.VisitingInfo == nil {
+					return ""
+				}
+				return m.VisitingInfo.WorkingHours
+			},
+			// hours parsed from the text before would contradict the new one,
+			// and only the parsers of each source know how to read it
+			set: func(m *Model, v string) error {
+				if v != "" || m.VisitingInfo != nil {
+					m.visitingInfo().WorkingHours = v
+					m.VisitingInfo.OpeningHours = nil
+				}
+				return nil
+			},
+		},
+	}, append(localizedNamesFields(), facilitiesFields()...)...)
+
+	flatFieldsByName = func() map[string]flatField {
+		byName := make(map[string]flatField, len(flatFields))
+		for _, f := range flatFields {
+			byName[f.name] = f
+		}
+		return byName
+	}()
+
+	FieldNames = func() []string {
+		names := make([]string, len(flatFields))
+		for i, f := range flatFields {
+			names[i] = f.name
+		}
+		return names
+	}()
+)
+
+// localizedNamesFields has one field per language.
+func localizedNamesFields() []flatField {
+	fields := make([]flatField, 0, len(Languages))
+	for _, language := range Languages {
+		language := language
+		fields = append(fields, flatField{
+			name: localizedNamesFieldPrefix + language.String(),
+			get:  func(m Model) string { return m.LocalizedNames[language] },
+			set: func(m *Model, v string) error {
+				if strings.TrimSpace(v) == "" {
+					delete(m.LocalizedNames, language)
+					return nil
+				}
+				m.SetLocalizedName(language, v)
+				return nil
+			},
+		})
+	}
+	return fields
+}
+
+// facilitiesFields has one field per facility, true or false when the
+// facility is known to be available or not and empty when it is unknown.
+// Facilities set through them have no source.
+func facilitiesFields() []flatField {
+	fields := make([]flatField, 0, len(AllFacilities))
+	for _, facility := range AllFacilities {
+		facility := facility
+		fields = append(fields, flatField{
+			name: facilitiesFieldPrefix + string(facility),
+			get: func(m Model) string {
+				if m.VisitingInfo == nil {
+					return ""
+				}
+				switch m.VisitingInfo.Facilities.Availability(facility) {
+				case Available:
+					return "true"
+				case NotAvailable:
+					return "false"
+				default:
+					return ""
+				}
+			},
+			set: func(m *Model, v string) error {
+				if v == "" {
+					if m.VisitingInfo != nil {
+						m.VisitingInfo.Facilities.Set(facility, AvailabilityUnknown, "")
+					}
+					return nil
+				}
+				available, err := strconv.ParseBool(v)
+				if err != nil {
+					return fmt.Errorf("expected true or false, got [%s]", v)
+				}
+				availability := NotAvailable
+				if available {
+					availability = Available
+				}
+				m.visitingInfo().Facilities.Set(facility, availability, "")
+				return nil
+			},
+		})
+	}
+	return fields
+}
+
+// Field returns the value of the field with the given JSON path.
+func (m Model) Field(name string) (string, error) {
+	field, found := flatFieldsByName[name]
+	if !found {
+		return "", fmt.Errorf("%w [%s]", ErrUnknownField, name)
+	}
+	return field.get(m), nil
+}
+
+// SetField sets the field with the given JSON path. Empty values clear it.
+func (m *Model) SetField(name, value string) error {
+	field, found := flatFieldsByName[name]
+	if !found {
+		return fmt.Errorf("%w [%s]", ErrUnknownField, name)
+	}
+	if err := field.set(m, value); err != nil {
+		return fmt.Errorf("invalid value for field [%s], %w", name, err)
+	}
+	return nil
+}
+
+func (m *Model) contact() *Contact {
+	if m.Contact == nil {
+		m.Contact = &Contact{}
+	}
+	return m.Contact
+}
+
+func (m *Model) visitingInfo() *VisitingInfo {
+	if m.VisitingInfo == nil {
+		m.VisitingInfo = &VisitingInfo{}
+	}
+	return m.VisitingInfo
+}
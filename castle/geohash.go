@@ -0,0 +1,43 @@
+package castle
+
+const (
+	geohashAlphabet = "0123456789bcdefghjkmnpqrstuvwxyz"
+)
+
+// Geohash encodes the coordinates with the given number of characters. Each
+// extra character narrows the cell, 4 characters cover about 39km x 20km.
+func (c Coordinates) Geohash(precision int) string {
+	latRange := [2]float64{-90, 90}
+	lonRange := [2]float64{-180, 180}
+	hash := make([]byte, 0, precision)
+	bit, ch := 0, 0
+	evenBit := true
+	for len(hash) < precision {
+		if evenBit {
+			mid := (lonRange[0] + lonRange[1]) / 2
+			if c.Longitude >= mid {
+				ch = ch<<1 | 1
+				lonRange[0] = mid
+			} else {
+				ch = ch << 1
+				lonRange[1] = mid
+			}
+		} else {
+			mid := (latRange[0] + latRange[1]) / 2
+			if c.Latitude >= mid {
+				ch = ch<<1 | 1
+				latRange[0] = mid
+			} else {
+				ch = ch << 1
+				latRange[1] = mid
+			}
+		}
+		evenBit = !evenBit
+		bit++
+		if bit == 5 {
+			hash = append(hash, geohashAlphabet[ch])
+			bit, ch = 0, 0
+		}
+	}
+	return string(hash)
+}
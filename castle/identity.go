@@ -0,0 +1,96 @@
+package castle
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+)
+
+type IdentityKind string
+
+const (
+	SameAs    IdentityKind = "sameAs"
+	NotSameAs IdentityKind = "notSameAs"
+)
+
+var (
+	ErrInvalidIdentityAssertion = errors.New("identity assertion must have a castle ID, a source, a kind, an author and a reason")
+)
+
+// IdentityAssertion is a decision taken about which castle the one listed by
+// a source link is, respected by every reconciliation.
+type IdentityAssertion struct {
+	CastleID  string       `json:"castleId" bson:"castleId"`
+	Source    string       `json:"source" bson:"source"`
+	Kind      IdentityKind `json:"kind" bson:"kind"`
+	Author    string       `json:"author" bson:"author"`
+	Reason    string       `json:"reason" bson:"reason"`
+	CreatedAt time.Time    `json:"createdAt" bson:"createdAt"`
+}
+
+func (a IdentityAssertion) Validate() error {
+	if a.CastleID == "" || a.Source == "" || a.Author == "" || a.Reason == "" {
+		return ErrInvalidIdentityAssertion
+	}
+	if a.Kind != SameAs && a.Kind != NotSameAs {
+		return fmt.Errorf("%w, got kind [%s]", ErrInvalidIdentityAssertion, a.Kind)
+	}
+	return nil
+}
+
+// NewID returns a random identifier for a castle saved for the first time.
+func NewID() (string, error) {
+	id := make([]byte, 12)
+	if _, err := rand.Read(id); err != nil {
+		return "", fmt.Errorf("failed to generate castle ID, got %v", err)
+	}
+	return hex.EncodeToString(id), nil
+}
+
+// Identities answers which castles the ones listed by source links are,
+// according to the asserted ones.
+type Identities struct {
+	sameAs    map[string]string
+	notSameAs map[string]map[string]bool
+}
+
+func NewIdentities(assertions []IdentityAssertion) Identities {
+	identities := Identities{
+		sameAs:    make(map[string]string),
+		notSameAs: make(map[string]map[string]bool),
+	}
+	for _, a := range assertions {
+		switch a.Kind {
+		case SameAs:
+			identities.sameAs[a.Source] = a.CastleID
+		case NotSameAs:
+			if identities.notSameAs[a.Source] == nil {
+				identities.notSameAs[a.Source] = make(map[string]bool)
+			}
+			identities.notSameAs[a.Source][a.CastleID] = true
+		}
+	}
+	return identities
+}
+
+// SameAs returns the castle one of the sources of m was asserted to be.
+func (i Identities) SameAs(m Model) (string, bool) {
+	for _, source := range m.Sources {
+		if castleID, found := i.sameAs[source]; found {
+			return castleID, true
+		}
+	}
+	return "", false
+}
+
+// NotSameAs tells whether one of the sources of m was asserted to not be the given castle.
+func (i Identities) NotSameAs(m Model, castleID string) bool {
+	for _, source := range m.Sources {
+		if i.notSameAs[source][castleID] {
+			return true
+		}
+	}
+	return false
+}
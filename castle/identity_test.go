@@ -0,0 +1,162 @@
+package castle
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCandidateIndexResolve(t *testing.T) {
+	index := NewCandidateIndex([]Model{
+		{ID: "a", Name: "windsor", Country: UK, Sources: []string{"https://medievalbritain.com/windsor"}},
+		{ID: "b", Name: "windsor", Country: UK, Sources: []string{"https://www.ebidat.de/windsor"}},
+		{ID: "c", Name: "kirby muxloe", Country: UK},
+	})
+
+	testCases := []struct {
+		name       string
+		castle     Model
+		assertions []IdentityAssertion
+		found      bool
+		expectedID string
+	}{
+		{
+			name:       "same_source_link",
+			castle:     Model{Name: "windsor castle", Country: UK, Sources: []string{"https://www.ebidat.de/windsor"}},
+			found:      true,
+			expectedID: "b",
+		},
+		{
+			name:       "same_id_even_if_not_probably_the_same",
+			castle:     Model{ID: "c", Name: "another name", Country: UK},
+			found:      true,
+			expectedID: "c",
+		},
+		{
+			name:   "asserted_same_as_wins_over_source_link",
+			castle: Model{Name: "windsor", Country: UK, Sources: []string{"https://www.ebidat.de/windsor"}},
+			assertions: []IdentityAssertion{
+				{CastleID: "c", Source: "https://www.ebidat.de/windsor", Kind: SameAs},
+			},
+			found:      true,
+			expectedID: "c",
+		},
+		{
+			name:   "asserted_not_same_as_skips_source_link",
+			castle: Model{Name: "windsor", Country: UK, Sources: []string{"https://www.ebidat.de/windsor"}},
+			assertions: []IdentityAssertion{
+				{CastleID: "b", Source: "https://www.ebidat.de/windsor", Kind: NotSameAs},
+			},
+			found:      true,
+			expectedID: "a",
+		},
+		{
+			name:   "asserted_not_same_as_every_candidate",
+			castle: Model{Name: "windsor", Country: UK, Sources: []string{"https://heritageireland.ie/windsor"}},
+			assertions: []IdentityAssertion{
+				{CastleID: "a", Source: "https://heritageireland.ie/windsor", Kind: NotSameAs},
+				{CastleID: "b", Source: "https://heritageireland.ie/windsor", Kind: NotSameAs},
+			},
+			found: false,
+		},
+	}
+
+	for _, tt := range testCases {
+		currentTT := tt
+		t.Run(currentTT.name, func(t *testing.T) {
+			t.Helper()
+
+			received, found := index.Resolve(currentTT.castle, NewIdentities(currentTT.assertions))
+
+			if found != currentTT.found {
+				t.Fatalf("expected found to be [%v], got [%v]", currentTT.found, found)
+			}
+			if received.ID != currentTT.expectedID {
+				t.Errorf("expected castle [%s], got [%s]", currentTT.expectedID, received.ID)
+			}
+		})
+	}
+}
+
+func TestMergeWithKeepsID(t *testing.T) {
+	testCases := []struct {
+		name     string
+		m        Model
+		c        Model
+		expected string
+	}{
+		{
+			name:     "new_castle_takes_id_of_saved_one",
+			m:        Model{Name: "trim", Country: Ireland},
+			c:        Model{ID: "saved", Name: "trim", Country: Ireland},
+			expected: "saved",
+		},
+		{
+			name:     "castle_with_id_keeps_it",
+			m:        Model{ID: "into", Name: "trim", Country: Ireland},
+			c:        Model{ID: "from", Name: "trim castle", Country: Ireland},
+			expected: "into",
+		},
+	}
+
+	for _, tt := range testCases {
+		currentTT := tt
+		t.Run(currentTT.name, func(t *testing.T) {
+			t.Helper()
+			merged := currentTT.m.MergeWith(currentTT.c)
+			if merged.ID != currentTT.expected {
+				t.Errorf("expected ID [%s], got [%s]", currentTT.expected, merged.ID)
+			}
+		})
+	}
+}
+
+func TestMergeWithKeepsWebNameAliases(t *testing.T) {
+	testCases := []struct {
+		name     string
+		m        Model
+		c        Model
+		expected []string
+	}{
+		{
+			name:     "renamed_saved_castle",
+			m:        Model{Name: "guimaraes", Country: Portugal},
+			c:        Model{ID: "saved", Name: "castelo de guimaraes", Country: Portugal},
+			expected: []string{"castelo-de-guimaraes-pt"},
+		},
+		{
+			name:     "saved_castle_keeping_its_name",
+			m:        Model{Name: "castelo de guimaraes", Country: Portugal},
+			c:        Model{ID: "saved", Name: "guimaraes", Country: Portugal},
+			expected: nil,
+		},
+		{
+			name:     "castle_never_saved",
+			m:        Model{Name: "guimaraes", Country: Portugal},
+			c:        Model{Name: "castelo de guimaraes", Country: Portugal},
+			expected: nil,
+		},
+		{
+			name:     "previous_aliases_are_kept",
+			m:        Model{Name: "guimaraes", Country: Portugal},
+			c:        Model{ID: "saved", Name: "castelo de guimaraes", Country: Portugal, WebNameAliases: []string{"castelo-guimaraes-pt"}},
+			expected: []string{"castelo-guimaraes-pt", "castelo-de-guimaraes-pt"},
+		},
+		{
+			name:     "web_name_back_to_an_alias",
+			m:        Model{Name: "guimaraes", Country: Portugal},
+			c:        Model{ID: "saved", Name: "guimaraes castle", Country: Portugal, WebNameAliases: []string{"guimaraes-pt"}},
+			expected: []string{"guimaraes-castle-pt"},
+		},
+	}
+
+	for _, tt := range testCases {
+		currentTT := tt
+		t.Run(currentTT.name, func(t *testing.T) {
+			t.Helper()
+			merged := currentTT.m.MergeWith(currentTT.c)
+			if !reflect.DeepEqual(merged.WebNameAliases, currentTT.expected) {
+				t.Errorf("expected aliases %v, got %v", currentTT.expected, merged.WebNameAliases)
+			}
+		})
+	}
+}
@@ -1,9 +1,37 @@
 package castle
 
+import "github.com/buarki/find-castles/toascii"
+
 type Language string
 
 const (
 	English    Language = "en"
 	Portuguese Language = "pt"
 	Slovak     Language = "sk"
+	German     Language = "de"
+	Danish     Language = "da"
+	Irish      Language = "ga"
+)
+
+var (
+	Languages = []Language{
+		English,
+		Portuguese,
+		Slovak,
+		German,
+		Danish,
+		Irish,
+	}
 )
+
+func (l Language) String() string {
+	return string(l)
+}
+
+// ToASCII transliterates the text with the rules of the language, ex: German
+// turns ä into ae. Only the search names use it, web names and blocking keys
+// use the shared rules, as the same castle must get the same keys whatever
+// the language of its name is.
+func (l Language) ToASCII(text string) (string, error) {
+	return toascii.FromLanguage(text, l.String())
+}
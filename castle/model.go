@@ -2,11 +2,9 @@ package castle
 
 import (
 	"errors"
-	"fmt"
-	"regexp"
 	"strings"
 
-	"github.com/buarki/find-castles/toascii"
+	"github.com/buarki/find-castles/openinghours"
 )
 
 var (
@@ -14,77 +12,80 @@ var (
 )
 
 type Contact struct {
-	Phone string
-	Email string
-}
-
-type Facilities struct {
-	AssistanceDogsAllowed bool `json:"assistanceDogsAllowed"`
-	Cafe                  bool `json:"cafe"`
-	Restrooms             bool `json:"restrooms"`
-	Giftshops             bool `json:"giftshops"`
-	PinicArea             bool `json:"pinicArea"`
-	Parking               bool `json:"parking"`
-	Exhibitions           bool `json:"exhibitions"`
-	WheelchairSupport     bool `json:"wheelchairSupport"`
+	Phone string `json:"phone"`
+	Email string `json:"email"`
 }
 
 type VisitingInfo struct {
-	WorkingHours string      `json:"workingHours"`
-	Facilities   *Facilities `json:"facilities"`
+	// as the source gives it, kept when it can't be parsed into OpeningHours
+	WorkingHours string                 `json:"workingHours"`
+	OpeningHours *openinghours.Schedule `json:"openingHours"`
+	Facilities   Facilities             `json:"facilities"`
+	Admission    *Admission             `json:"admission"`
 }
 
 func (vi *VisitingInfo) Copy() *VisitingInfo {
 	newVisitingInfo := &VisitingInfo{
 		WorkingHours: vi.WorkingHours,
+		OpeningHours: vi.OpeningHours,
+		Facilities:   vi.Facilities.Copy(),
 	}
-	if vi.Facilities != nil {
-		newFacilities := *vi.Facilities
-		newVisitingInfo.Facilities = &newFacilities
+	if vi.Admission != nil {
+		newVisitingInfo.Admission = vi.Admission.Copy()
 	}
 	return newVisitingInfo
 }
 
 type Model struct {
+	// stable identifier, given when a castle is first saved
+	ID string `json:"id"`
+
 	// mandatory fields
 	Name    string   `json:"name"`
-	Sources []string `json:"sourcs"`
+	Sources []string `json:"sources"`
 	Country Country  `json:"country"`
 
-	State             string            `json:"state"`
+	// names of the castle on other languages, and other names it is or was known by
+	LocalizedNames map[Language]string `json:"localizedNames"`
+	AlternateNames []string            `json:"alternateNames"`
+
+	// postal address, which state, city and district are derived from when known
+	Address *Address `json:"address"`
+	State   string   `json:"state"`
+	// ISO 3166-2 code of the state, like IE-KY, when it could be resolved
+	StateCode string `json:"stateCode" bson:"stateCode"`
+	// state as the source gives it, like "Co. Kerry", when it was resolved
+	StateLabel        string            `json:"stateLabel" bson:"stateLabel"`
 	City              string            `json:"city"`
 	District          string            `json:"district"`
 	FoundationPeriod  string            `json:"foundationPeriod"`
 	PropertyCondition PropertyCondition `json:"propertyCondition"`
-	Coordinates       string            `json:"coordinates"`
-	RawData           any               `json:"rawData"`
-	MatchingTags      []string          `json:"matchingTags"`
-	PictureURL        string            `json:"pictureLink"`
-	Contact           *Contact
-	VisitingInfo      *VisitingInfo `json:"visitingInfo"`
+	// condition as the source gives it, like "überbaut"
+	PropertyConditionLabel string `json:"propertyConditionLabel"`
+	Coordinates            string `json:"coordinates"`
+	// fields no source told, filled by geocoding, like city
+	Derived      []string      `json:"derived"`
+	RawData      any           `json:"rawData"`
+	MatchingTags []string      `json:"matchingTags"`
+	PictureURL   string        `json:"pictureURL"`
+	Contact      *Contact      `json:"contact"`
+	VisitingInfo *VisitingInfo `json:"visitingInfo"`
+	Status       Status        `json:"status"`
+	// web names the castle had before, kept so old links still find it
+	WebNameAliases []string `json:"webNameAliases"`
+	// web name given when the castle was saved, see Model.AllocateWebName
+	AllocatedWebName string `json:"-" bson:"webName"`
+	// name the web name was allocated for, as given by Model.NameSlug, which
+	// tells a rename apart from a web name disambiguated by a place
+	WebNameAllocatedFor string `json:"-" bson:"webNameFor"`
 
 	CurrentEnrichmentLink   string // current link being used on enrichment
 	CurrentEnrichmentSource string
 }
 
+// FilteredName is the name normalized with the rules of the languages of its country.
 func (m Model) FilteredName() string {
-	cleanedName := strings.ToLower(m.Name)
-	for _, keyword := range keywordsToRemove[m.Country] {
-		cleanedName = strings.ReplaceAll(cleanedName, keyword, "")
-	}
-	return cleanedName
-}
-
-func (m Model) WebName() (string, error) {
-	asciiStr, err := toascii.From(m.Name)
-	if err != nil {
-		return "", err
-	}
-	re := regexp.MustCompile(`[^\w]+`)
-	webName := re.ReplaceAllString(asciiStr, "-")
-	webName = strings.ToLower(webName)
-	webName = fmt.Sprintf("%s-%s", webName, m.Country)
-	return webName, nil
+	return NormalizeName(m.Name, defaultNameNormalizer.CountryLanguages(m.Country)...)
 }
 
 // Future plan: power it with AI
@@ -95,22 +96,34 @@ func (m Model) IsProbably(c Model) bool {
 	if len(c.Name) == 0 || len(m.Name) == 0 {
 		return false
 	}
-	mFilteredNames := m.FilteredName()
-	cFilteredNames := c.FilteredName()
-	if !strings.Contains(cFilteredNames, mFilteredNames) && !strings.Contains(mFilteredNames, cFilteredNames) {
+	// any name may be the one both sources know the castle by
+	if !m.namesMatch(c) {
 		return false
 	}
 
-	mState := strings.ToLower(m.State)
-	cState := strings.ToLower(c.State)
-	if !strings.Contains(cState, mState) && !strings.Contains(mState, cState) {
-		return false
+	// resolved states tell the same region apart however sources write it
+	// derived states and cities are of the nearest place, which may not be
+	// the one a source tells
+	switch {
+	case m.IsDerived(DerivedState) || c.IsDerived(DerivedState):
+	case m.StateCode != "" && c.StateCode != "":
+		if m.StateCode != c.StateCode {
+			return false
+		}
+	default:
+		mState := strings.ToLower(m.State)
+		cState := strings.ToLower(c.State)
+		if !strings.Contains(cState, mState) && !strings.Contains(mState, cState) {
+			return false
+		}
 	}
 
-	mCity := strings.ToLower(m.City)
-	cCity := strings.ToLower(c.City)
-	if !strings.Contains(cCity, mCity) && !strings.Contains(mCity, cCity) {
-		return false
+	if !m.IsDerived(DerivedCity) && !c.IsDerived(DerivedCity) {
+		mCity := strings.ToLower(m.City)
+		cCity := strings.ToLower(c.City)
+		if !strings.Contains(cCity, mCity) && !strings.Contains(mCity, cCity) {
+			return false
+		}
 	}
 
 	mDistrict := strings.ToLower(m.District)
@@ -119,7 +132,12 @@ func (m Model) IsProbably(c Model) bool {
 		return false
 	}
 
-	// TODO handle foundation...
+	// castles founded on periods far apart are different ones, even if named alike
+	if mFoundation, ok := m.Foundation(); ok {
+		if cFoundation, ok := c.Foundation(); ok && !mFoundation.Overlaps(cFoundation) {
+			return false
+		}
+	}
 
 	return true
 }
@@ -129,7 +147,24 @@ func (m Model) ReconcileWith(c Model) (Model, error) {
 	if !m.IsProbably(c) {
 		return Model{}, ErrCastlesShouldProbablyBeTheSameToReconcile
 	}
+	return m.MergeWith(c), nil
+}
+
+// MergeWith merges castles without checking they are probably the same, for
+// when it is already known, like when a curator asserted it. The ID and the
+// allocated web name of m are kept, falling back to the ones of c.
+func (m Model) MergeWith(c Model) Model {
+	// values told by a source win over derived ones
+	m, c = withoutDerivedToldBy(m, c), withoutDerivedToldBy(c, m)
 	newCastle := m.Copy()
+	newCastle.Derived = mergeDerived(m, c)
+
+	if newCastle.ID == "" {
+		newCastle.ID = c.ID
+	}
+	if newCastle.AllocatedWebName == "" {
+		newCastle.AllocatedWebName, newCastle.WebNameAllocatedFor = c.AllocatedWebName, c.WebNameAllocatedFor
+	}
 
 	if newCastle.Name != c.Name {
 		if len(newCastle.Name) > len(c.Name) { // always select the smaller name
@@ -137,12 +172,15 @@ func (m Model) ReconcileWith(c Model) (Model, error) {
 		}
 	}
 
-	if newCastle.State == "" {
+	newCastle.Address = newCastle.Address.MergeWith(c.Address)
+
+	// a resolved state wins over any text, and goes along with its code and label
+	switch {
+	case newCastle.StateCode == "" && c.StateCode != "":
+		newCastle.State, newCastle.StateCode, newCastle.StateLabel = c.State, c.StateCode, c.StateLabel
+	case newCastle.StateCode != "":
+	case newCastle.State == "" || len(newCastle.State) < len(c.State):
 		newCastle.State = c.State
-	} else {
-		if len(newCastle.State) < len(c.State) {
-			newCastle.State = c.State
-		}
 	}
 
 	if newCastle.City == "" {
@@ -164,7 +202,17 @@ func (m Model) ReconcileWith(c Model) (Model, error) {
 	if newCastle.FoundationPeriod == "" {
 		newCastle.FoundationPeriod = c.FoundationPeriod
 	} else {
-		if len(newCastle.FoundationPeriod) < len(c.FoundationPeriod) {
+		mFoundation, mParsed := newCastle.Foundation()
+		cFoundation, cParsed := c.Foundation()
+		switch {
+		case mParsed && cParsed:
+			// the most precise period wins
+			if cFoundation.years() < mFoundation.years() {
+				newCastle.FoundationPeriod = c.FoundationPeriod
+			}
+		case cParsed:
+			newCastle.FoundationPeriod = c.FoundationPeriod
+		case !mParsed && len(newCastle.FoundationPeriod) < len(c.FoundationPeriod):
 			newCastle.FoundationPeriod = c.FoundationPeriod
 		}
 	}
@@ -192,14 +240,24 @@ func (m Model) ReconcileWith(c Model) (Model, error) {
 		}
 	}
 
-	if newCastle.PropertyCondition == Unknown {
-		if m.PropertyCondition != Unknown {
-			newCastle.PropertyCondition = m.PropertyCondition
-		}
-	} else {
-		if newCastle.PropertyCondition.ComparisonWeight() < m.PropertyCondition.ComparisonWeight() {
-			newCastle.PropertyCondition = m.PropertyCondition
+	// the label goes with the condition it was mapped into
+	switch {
+	case newCastle.PropertyCondition == Unknown || newCastle.PropertyCondition == "":
+		if c.PropertyCondition != Unknown && c.PropertyCondition != "" {
+			newCastle.PropertyCondition = c.PropertyCondition
+			newCastle.PropertyConditionLabel = c.PropertyConditionLabel
 		}
+	case newCastle.PropertyCondition.ComparisonWeight() < c.PropertyCondition.ComparisonWeight():
+		newCastle.PropertyCondition = c.PropertyCondition
+		newCastle.PropertyConditionLabel = c.PropertyConditionLabel
+	case newCastle.PropertyCondition.ComparisonWeight() == c.PropertyCondition.ComparisonWeight() &&
+		newCastle.PropertyCondition.Coarse() == newCastle.PropertyCondition && c.PropertyCondition.Coarse() != c.PropertyCondition:
+		// same coarse condition, but c tells more about it
+		newCastle.PropertyCondition = c.PropertyCondition
+		newCastle.PropertyConditionLabel = c.PropertyConditionLabel
+	}
+	if newCastle.PropertyConditionLabel == "" && newCastle.PropertyCondition == c.PropertyCondition {
+		newCastle.PropertyConditionLabel = c.PropertyConditionLabel
 	}
 
 	var newSources []string
@@ -227,16 +285,12 @@ func (m Model) ReconcileWith(c Model) (Model, error) {
 		if c.VisitingInfo != nil {
 			if len(newCastle.VisitingInfo.WorkingHours) < len(c.VisitingInfo.WorkingHours) {
 				newCastle.VisitingInfo.WorkingHours = c.VisitingInfo.WorkingHours
+				newCastle.VisitingInfo.OpeningHours = c.VisitingInfo.OpeningHours
 			}
 
-			newCastle.VisitingInfo.Facilities.AssistanceDogsAllowed = newCastle.VisitingInfo.Facilities.AssistanceDogsAllowed && c.VisitingInfo.Facilities.AssistanceDogsAllowed
-			newCastle.VisitingInfo.Facilities.Cafe = newCastle.VisitingInfo.Facilities.Cafe && c.VisitingInfo.Facilities.Cafe
-			newCastle.VisitingInfo.Facilities.Exhibitions = newCastle.VisitingInfo.Facilities.Exhibitions && c.VisitingInfo.Facilities.Exhibitions
-			newCastle.VisitingInfo.Facilities.Giftshops = newCastle.VisitingInfo.Facilities.Giftshops && c.VisitingInfo.Facilities.Giftshops
-			newCastle.VisitingInfo.Facilities.Parking = newCastle.VisitingInfo.Facilities.Parking && c.VisitingInfo.Facilities.Parking
-			newCastle.VisitingInfo.Facilities.PinicArea = newCastle.VisitingInfo.Facilities.PinicArea && c.VisitingInfo.Facilities.PinicArea
-			newCastle.VisitingInfo.Facilities.Restrooms = newCastle.VisitingInfo.Facilities.Restrooms && c.VisitingInfo.Facilities.Restrooms
-			newCastle.VisitingInfo.Facilities.WheelchairSupport = newCastle.VisitingInfo.Facilities.WheelchairSupport && c.VisitingInfo.Facilities.WheelchairSupport
+			// facilities a source says nothing about don't erase the ones others found
+			newCastle.VisitingInfo.Facilities = newCastle.VisitingInfo.Facilities.MergeWith(c.VisitingInfo.Facilities)
+			newCastle.VisitingInfo.Admission = newCastle.VisitingInfo.Admission.MergeWith(c.VisitingInfo.Admission)
 		}
 	}
 
@@ -244,7 +298,37 @@ func (m Model) ReconcileWith(c Model) (Model, error) {
 		newCastle.PictureURL = c.PictureURL
 	}
 
-	return newCastle, nil
+	mergeNames(&newCastle, m, c)
+	newCastle.WebNameAliases = mergeWebNameAliases(newCastle, m, c)
+
+	return newCastle
+}
+
+// mergeWebNameAliases keeps as aliases every web name the merged castles had
+// but the one of the merged castle. Only castles with an ID were saved, so
+// only their web names may have been published.
+func mergeWebNameAliases(merged Model, castles ...Model) []string {
+	mergedWebName, _ := merged.WebName()
+	aliasSet := make(map[string]bool)
+	var aliases []string
+	addAlias := func(alias string) {
+		if alias != "" && alias != mergedWebName && !aliasSet[alias] {
+			aliasSet[alias] = true
+			aliases = append(aliases, alias)
+		}
+	}
+	for _, c := range castles {
+		for _, alias := range c.WebNameAliases {
+			addAlias(alias)
+		}
+		if c.ID == "" {
+			continue
+		}
+		if webName, err := c.WebName(); err == nil {
+			addAlias(webName)
+		}
+	}
+	return aliases
 }
 
 func (m *Model) CleanFields() {
@@ -282,6 +366,11 @@ func (m Model) GetMatchingTags() []string {
 		matchingTags = append(matchingTags, strings.ToLower(m.FoundationPeriod))
 	}
 
+	for _, name := range m.filteredNames()[1:] {
+		matchingTags = append(matchingTags, name)
+		matchingTags = append(matchingTags, strings.Split(name, " ")...)
+	}
+
 	return matchingTags
 }
 
@@ -292,27 +381,70 @@ func (m Model) Copy() Model {
 		copy(sourcesCopy, m.Sources)
 	}
 
+	var localizedNamesCopy map[Language]string
+	if len(m.LocalizedNames) > 0 {
+		localizedNamesCopy = make(map[Language]string, len(m.LocalizedNames))
+		for language, name := range m.LocalizedNames {
+			localizedNamesCopy[language] = name
+		}
+	}
+
+	var alternateNamesCopy []string
+	if len(m.AlternateNames) > 0 {
+		alternateNamesCopy = make([]string, len(m.AlternateNames))
+		copy(alternateNamesCopy, m.AlternateNames)
+	}
+
+	var aliasesCopy []string
+	if len(m.WebNameAliases) > 0 {
+		aliasesCopy = make([]string, len(m.WebNameAliases))
+		copy(aliasesCopy, m.WebNameAliases)
+	}
+
 	var matchingTagsCopy []string
 	if len(m.MatchingTags) > 0 {
 		matchingTagsCopy = make([]string, len(m.MatchingTags))
 		copy(matchingTagsCopy, m.MatchingTags)
 	}
 
+	var derivedCopy []string
+	if len(m.Derived) > 0 {
+		derivedCopy = make([]string, len(m.Derived))
+		copy(derivedCopy, m.Derived)
+	}
+
+	var addressCopy *Address
+	if m.Address != nil {
+		addressCopy = m.Address.Copy()
+	}
+
 	return Model{
-		Country:               m.Country,
-		Name:                  m.Name,
-		CurrentEnrichmentLink: m.CurrentEnrichmentLink,
-		Sources:               sourcesCopy,
-		State:                 m.State,
-		City:                  m.City,
-		District:              m.District,
-		FoundationPeriod:      m.FoundationPeriod,
-		PropertyCondition:     m.PropertyCondition,
-		Coordinates:           m.Coordinates,
-		RawData:               m.RawData,
-		MatchingTags:          matchingTagsCopy,
-		PictureURL:            m.PictureURL,
-		Contact:               m.Contact,
-		VisitingInfo:          m.VisitingInfo,
+		ID:                     m.ID,
+		Country:                m.Country,
+		Name:                   m.Name,
+		LocalizedNames:         localizedNamesCopy,
+		AlternateNames:         alternateNamesCopy,
+		CurrentEnrichmentLink:  m.CurrentEnrichmentLink,
+		Sources:                sourcesCopy,
+		Address:                addressCopy,
+		State:                  m.State,
+		StateCode:              m.StateCode,
+		StateLabel:             m.StateLabel,
+		City:                   m.City,
+		District:               m.District,
+		FoundationPeriod:       m.FoundationPeriod,
+		PropertyCondition:      m.PropertyCondition,
+		PropertyConditionLabel: m.PropertyConditionLabel,
+		Coordinates:            m.Coordinates,
+		Derived:                derivedCopy,
+		RawData:                m.RawData,
+		MatchingTags:           matchingTagsCopy,
+		PictureURL:             m.PictureURL,
+		Contact:                m.Contact,
+		VisitingInfo:           m.VisitingInfo,
+		Status:                 m.Status,
+		WebNameAliases:         aliasesCopy,
+		AllocatedWebName:       m.AllocatedWebName,
+		WebNameAllocatedFor:    m.WebNameAllocatedFor,
 	}
 }
@@ -231,8 +231,9 @@ func TestReconcileWith(t *testing.T) {
 				Name:    "kirby castle",
 			},
 			resultCastle: Model{
-				Country: UK,
-				Name:    "kirby castle",
+				Country:        UK,
+				Name:           "kirby castle",
+				AlternateNames: []string{"kirby muxloe castle"},
 			},
 			err: nil,
 		},
@@ -247,8 +248,9 @@ func TestReconcileWith(t *testing.T) {
 				Name:    "kirby muxloe castle",
 			},
 			resultCastle: Model{
-				Country: UK,
-				Name:    "kirby castle",
+				Country:        UK,
+				Name:           "kirby castle",
+				AlternateNames: []string{"kirby muxloe castle"},
 			},
 			err: nil,
 		},
@@ -524,6 +526,14 @@ func TestWebName(t *testing.T) {
 			c:       Model{Country: Slovakia, Name: "Nitrianska Blatnica - jurko a púst"},
 			webName: "nitrianska-blatnica-jurko-a-pust-sk",
 		},
+		{
+			c:       Model{Country: Denmark, Name: "ærøskøbing slot"},
+			webName: "aeroskobing-slot-dk",
+		},
+		{
+			c:       Model{Country: Slovakia, Name: "Schloß Strečno"},
+			webName: "schloss-strecno-sk",
+		},
 	}
 
 	for _, tt := range testCases {
@@ -0,0 +1,122 @@
+package castle
+
+import (
+	"strings"
+)
+
+// Names returns every name of the castle without repetitions: the primary
+// one first, then the localized ones and at last the alternate ones.
+func (m Model) Names() []string {
+	var names []string
+	seen := make(map[string]bool)
+	add := func(name string) {
+		name = strings.TrimSpace(name)
+		key := strings.ToLower(name)
+		if name == "" || seen[key] {
+			return
+		}
+		seen[key] = true
+		names = append(names, name)
+	}
+	add(m.Name)
+	for _, language := range Languages {
+		add(m.LocalizedNames[language])
+	}
+	for _, name := range m.AlternateNames {
+		add(name)
+	}
+	return names
+}
+
+// SetLocalizedName sets the name of the castle on the given language, empty names are ignored.
+func (m *Model) SetLocalizedName(language Language, name string) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return
+	}
+	if m.LocalizedNames == nil {
+		m.LocalizedNames = make(map[Language]string)
+	}
+	m.LocalizedNames[language] = name
+}
+
+// DisplayName returns the name of the castle on the given language, falling
+// back to its primary name.
+func (m Model) DisplayName(language Language) string {
+	if name := strings.TrimSpace(m.LocalizedNames[language]); name != "" {
+		return name
+	}
+	return m.Name
+}
+
+// filteredNames returns every name of the castle normalized with the rules of
+// the languages of its country, or of its own language for localized ones.
+func (m Model) filteredNames() []string {
+	var filtered []string
+	seen := make(map[string]bool)
+	add := func(name string) {
+		if name != "" && !seen[name] {
+			seen[name] = true
+			filtered = append(filtered, name)
+		}
+	}
+	add(m.FilteredName())
+	for _, language := range Languages {
+		if name := m.LocalizedNames[language]; name != "" {
+			add(NormalizeName(name, language))
+		}
+	}
+	for _, name := range m.AlternateNames {
+		add(NormalizeName(name, defaultNameNormalizer.CountryLanguages(m.Country)...))
+	}
+	return filtered
+}
+
+// namesMatch tells whether any name of a castle contains, or is contained
+// by, any name of the other.
+func (m Model) namesMatch(c Model) bool {
+	for _, mName := range m.filteredNames() {
+		for _, cName := range c.filteredNames() {
+			if strings.Contains(cName, mName) || strings.Contains(mName, cName) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// mergeNames keeps every name of the castles merged: localized names of m
+// win, and the primary names not chosen become alternate ones unless they
+// normalize into the chosen one or into another alternate.
+func mergeNames(merged *Model, m, c Model) {
+	for _, language := range Languages {
+		name := c.LocalizedNames[language]
+		if name == "" || merged.LocalizedNames[language] != "" {
+			continue
+		}
+		if merged.LocalizedNames == nil {
+			merged.LocalizedNames = make(map[Language]string)
+		}
+		merged.LocalizedNames[language] = name
+	}
+
+	candidates := []string{m.Name, c.Name}
+	candidates = append(candidates, m.AlternateNames...)
+	candidates = append(candidates, c.AlternateNames...)
+
+	// names telling the same once normalized, like castelo de guimaraes and
+	// guimaraes, add nothing
+	languages := defaultNameNormalizer.CountryLanguages(merged.Country)
+	seen := map[string]bool{NormalizeName(merged.Name, languages...): true}
+	var alternates []string
+	for _, name := range candidates {
+		name = strings.TrimSpace(name)
+		key := NormalizeName(name, languages...)
+		if name == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		alternates = append(alternates, name)
+	}
+	merged.AlternateNames = alternates
+}
@@ -0,0 +1,115 @@
+package castle
+
+import (
+	"reflect"
+	"slices"
+	"testing"
+)
+
+func TestDisplayName(t *testing.T) {
+	c := Model{
+		Name:           "trencin",
+		Country:        Slovakia,
+		LocalizedNames: map[Language]string{German: "Burg Trentschin", Slovak: "Trenčiansky hrad"},
+	}
+
+	testCases := []struct {
+		language Language
+		expected string
+	}{
+		{language: German, expected: "Burg Trentschin"},
+		{language: Slovak, expected: "Trenčiansky hrad"},
+		{language: English, expected: "trencin"},
+	}
+
+	for _, tt := range testCases {
+		currentTT := tt
+		t.Run(currentTT.language.String(), func(t *testing.T) {
+			t.Helper()
+			if received := c.DisplayName(currentTT.language); received != currentTT.expected {
+				t.Errorf("expected [%s], got [%s]", currentTT.expected, received)
+			}
+		})
+	}
+}
+
+func TestIsProbablyByAnyName(t *testing.T) {
+	testCases := []struct {
+		name    string
+		c1      Model
+		c2      Model
+		matches bool
+	}{
+		{
+			name:    "by_localized_name",
+			c1:      Model{Name: "trencin", Country: Slovakia, LocalizedNames: map[Language]string{German: "Burg Trentschin"}},
+			c2:      Model{Name: "trentschin", Country: Slovakia},
+			matches: true,
+		},
+		{
+			name:    "by_alternate_name",
+			c1:      Model{Name: "burg trentschin", Country: Slovakia, AlternateNames: []string{"Trenčiansky hrad"}},
+			c2:      Model{Name: "trenčiansky hrad", Country: Slovakia},
+			matches: true,
+		},
+		{
+			name:    "no_name_in_common",
+			c1:      Model{Name: "trencin", Country: Slovakia, AlternateNames: []string{"Trenčiansky hrad"}},
+			c2:      Model{Name: "bojnice", Country: Slovakia},
+			matches: false,
+		},
+	}
+
+	for _, tt := range testCases {
+		currentTT := tt
+		t.Run(currentTT.name, func(t *testing.T) {
+			t.Helper()
+			if received := currentTT.c1.IsProbably(currentTT.c2); received != currentTT.matches {
+				t.Errorf("expected [%v], got [%v]", currentTT.matches, received)
+			}
+		})
+	}
+}
+
+func TestMergeWithKeepsNames(t *testing.T) {
+	scraped := Model{
+		Name:           "castelo de guimaraes",
+		Country:        Portugal,
+		LocalizedNames: map[Language]string{Portuguese: "Castelo de Guimarães"},
+	}
+	saved := Model{
+		Name:           "guimaraes",
+		Country:        Portugal,
+		LocalizedNames: map[Language]string{English: "Guimarães Castle", Portuguese: "Castelo Guimarães"},
+		AlternateNames: []string{"castelo da fundação"},
+	}
+
+	merged := scraped.MergeWith(saved)
+
+	if merged.Name != "guimaraes" {
+		t.Errorf("expected the smaller name to stay, got [%s]", merged.Name)
+	}
+	expectedLocalizedNames := map[Language]string{English: "Guimarães Castle", Portuguese: "Castelo de Guimarães"}
+	if !reflect.DeepEqual(merged.LocalizedNames, expectedLocalizedNames) {
+		t.Errorf("expected localized names %v, got %v", expectedLocalizedNames, merged.LocalizedNames)
+	}
+	// castelo de guimaraes normalizes into guimaraes, so it is no other name
+	expectedAlternateNames := []string{"castelo da fundação"}
+	if !reflect.DeepEqual(merged.AlternateNames, expectedAlternateNames) {
+		t.Errorf("expected alternate names %v, got %v", expectedAlternateNames, merged.AlternateNames)
+	}
+	if scraped.LocalizedNames[English] != "" {
+		t.Errorf("expected merged castles to be left untouched")
+	}
+}
+
+func TestAlternateNamesFeedMatching(t *testing.T) {
+	c := Model{Name: "trencin", Country: Slovakia, AlternateNames: []string{"Trenčiansky hrad"}}
+
+	if !slices.Contains(c.GetMatchingTags(), "trenčiansky") {
+		t.Errorf("expected alternate name on matching tags, got %v", c.GetMatchingTags())
+	}
+	if !slices.Contains(c.BlockingKeys(), "sk:n:trenciansky") {
+		t.Errorf("expected alternate name tokens on blocking keys, got %v", c.BlockingKeys())
+	}
+}
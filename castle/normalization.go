@@ -0,0 +1,239 @@
+package castle
+
+import (
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// punctuation around words that doesn't change what they mean
+const wordPunctuation = ",;:!?()[]\"'-–"
+
+var (
+	//go:embed name_normalization.json
+	nameNormalizationRules []byte
+
+	defaultNameNormalizer = mustParseNameNormalizer(nameNormalizationRules)
+
+	ErrInvalidNormalizationRules = errors.New("invalid name normalization rules")
+)
+
+// NameRules are the words of a language that don't tell castles apart.
+// Prefixes and suffixes may have many words and only match at the start
+// and at the end of names, stopwords are single words matched anywhere
+// and articles are only removed from the start.
+type NameRules struct {
+	Articles      []string          `json:"articles"`
+	Stopwords     []string          `json:"stopwords"`
+	Prefixes      []string          `json:"prefixes"`
+	Suffixes      []string          `json:"suffixes"`
+	Abbreviations map[string]string `json:"abbreviations"`
+}
+
+// NormalizationRules holds the name rules of each language and the
+// languages castle names of each country are written in.
+type NormalizationRules struct {
+	Countries map[Country][]Language `json:"countries"`
+	Languages map[Language]NameRules `json:"languages"`
+}
+
+// NameNormalizer turns castle names into the form used to compare them.
+type NameNormalizer struct {
+	countries map[Country][]Language
+	languages map[Language]compiledNameRules
+}
+
+type compiledNameRules struct {
+	articles      map[string]bool
+	stopwords     map[string]bool
+	prefixes      [][]string
+	suffixes      [][]string
+	abbreviations map[string][]string
+}
+
+// ParseNameNormalizer reads the rules of a normalizer from JSON.
+func ParseNameNormalizer(data []byte) (*NameNormalizer, error) {
+	var rules NormalizationRules
+	if err := json.Unmarshal(data, &rules); err != nil {
+		return nil, fmt.Errorf("failed to parse name normalization rules, got %v", err)
+	}
+	return NewNameNormalizer(rules)
+}
+
+func mustParseNameNormalizer(data []byte) *NameNormalizer {
+	normalizer, err := ParseNameNormalizer(data)
+	if err != nil {
+		panic(err)
+	}
+	return normalizer
+}
+
+func NewNameNormalizer(rules NormalizationRules) (*NameNormalizer, error) {
+	normalizer := &NameNormalizer{
+		countries: rules.Countries,
+		languages: make(map[Language]compiledNameRules, len(rules.Languages)),
+	}
+	for country, languages := range rules.Countries {
+		for _, language := range languages {
+			if _, found := rules.Languages[language]; !found {
+				return nil, fmt.Errorf("%w: country [%s] uses language [%s] without rules", ErrInvalidNormalizationRules, country, language)
+			}
+		}
+	}
+	for language, languageRules := range rules.Languages {
+		compiled, err := compileNameRules(languageRules)
+		if err != nil {
+			return nil, fmt.Errorf("%w: language [%s], %v", ErrInvalidNormalizationRules, language, err)
+		}
+		normalizer.languages[language] = compiled
+	}
+	return normalizer, nil
+}
+
+func compileNameRules(rules NameRules) (compiledNameRules, error) {
+	compiled := compiledNameRules{
+		articles:      make(map[string]bool),
+		stopwords:     make(map[string]bool),
+		abbreviations: make(map[string][]string),
+	}
+	for _, article := range rules.Articles {
+		words := strings.Fields(strings.ToLower(article))
+		if len(words) != 1 {
+			return compiledNameRules{}, fmt.Errorf("article [%s] must be a single word", article)
+		}
+		compiled.articles[words[0]] = true
+	}
+	for _, stopword := range rules.Stopwords {
+		words := strings.Fields(strings.ToLower(stopword))
+		if len(words) != 1 {
+			return compiledNameRules{}, fmt.Errorf("stopword [%s] must be a single word", stopword)
+		}
+		compiled.stopwords[words[0]] = true
+	}
+	for _, prefix := range rules.Prefixes {
+		words := strings.Fields(strings.ToLower(prefix))
+		if len(words) == 0 {
+			return compiledNameRules{}, errors.New("prefixes must not be empty")
+		}
+		compiled.prefixes = append(compiled.prefixes, words)
+	}
+	for _, suffix := range rules.Suffixes {
+		words := strings.Fields(strings.ToLower(suffix))
+		if len(words) == 0 {
+			return compiledNameRules{}, errors.New("suffixes must not be empty")
+		}
+		compiled.suffixes = append(compiled.suffixes, words)
+	}
+	for abbreviation, expansion := range rules.Abbreviations {
+		words := strings.Fields(strings.ToLower(expansion))
+		if len(words) == 0 {
+			return compiledNameRules{}, fmt.Errorf("abbreviation [%s] must have an expansion", abbreviation)
+		}
+		compiled.abbreviations[strings.ToLower(abbreviation)] = words
+	}
+	return compiled, nil
+}
+
+// CountryLanguages returns the languages names of castles of the country are written in.
+func (n *NameNormalizer) CountryLanguages(country Country) []Language {
+	return n.countries[country]
+}
+
+// Normalize returns the name lower cased, with abbreviations expanded and
+// without the articles, prefixes, suffixes and stopwords of the given
+// languages. Words are only removed while some other word is left, so a
+// name made only of such words is kept. Normalizing a normalized name
+// doesn't change it.
+func (n *NameNormalizer) Normalize(name string, languages ...Language) string {
+	var rules []compiledNameRules
+	for _, language := range languages {
+		if languageRules, found := n.languages[language]; found {
+			rules = append(rules, languageRules)
+		}
+	}
+
+	var words []string
+	for _, word := range strings.Fields(strings.ToLower(name)) {
+		if strings.Trim(word, wordPunctuation+".") == "" {
+			continue
+		}
+		words = append(words, expandAbbreviation(word, rules)...)
+	}
+
+	// removing a prefix can leave an article at the start, and so on
+	for {
+		stripped := stripNameWords(words, rules)
+		if slices.Equal(stripped, words) {
+			break
+		}
+		words = stripped
+	}
+	return strings.Join(words, " ")
+}
+
+// NormalizeName normalizes the name with the rules the project ships with.
+func NormalizeName(name string, languages ...Language) string {
+	return defaultNameNormalizer.Normalize(name, languages...)
+}
+
+// wordKey is the word as rules list it.
+func wordKey(word string) string {
+	return strings.Trim(word, wordPunctuation+".")
+}
+
+func expandAbbreviation(word string, rules []compiledNameRules) []string {
+	key := strings.Trim(word, wordPunctuation)
+	for _, languageRules := range rules {
+		if expansion, found := languageRules.abbreviations[key]; found {
+			return expansion
+		}
+	}
+	return []string{word}
+}
+
+func stripNameWords(words []string, rules []compiledNameRules) []string {
+	if len(words) > 1 && slices.ContainsFunc(rules, func(r compiledNameRules) bool { return r.articles[wordKey(words[0])] }) {
+		return words[1:]
+	}
+	if length := longestPhrase(words, rules, func(r compiledNameRules) [][]string { return r.prefixes }, true); length > 0 && length < len(words) {
+		return words[length:]
+	}
+	if length := longestPhrase(words, rules, func(r compiledNameRules) [][]string { return r.suffixes }, false); length > 0 && length < len(words) {
+		return words[:len(words)-length]
+	}
+
+	var kept []string
+	for _, word := range words {
+		if !slices.ContainsFunc(rules, func(r compiledNameRules) bool { return r.stopwords[wordKey(word)] }) {
+			kept = append(kept, word)
+		}
+	}
+	if len(kept) == 0 {
+		return words
+	}
+	return kept
+}
+
+// longestPhrase returns how many words the longest phrase found at the start,
+// or at the end, of the words has.
+func longestPhrase(words []string, rules []compiledNameRules, phrasesOf func(compiledNameRules) [][]string, atStart bool) int {
+	longest := 0
+	for _, languageRules := range rules {
+		for _, phrase := range phrasesOf(languageRules) {
+			if len(phrase) <= longest || len(phrase) > len(words) {
+				continue
+			}
+			candidate := words[len(words)-len(phrase):]
+			if atStart {
+				candidate = words[:len(phrase)]
+			}
+			if slices.EqualFunc(candidate, phrase, func(word, phraseWord string) bool { return wordKey(word) == phraseWord }) {
+				longest = len(phrase)
+			}
+		}
+	}
+	return longest
+}
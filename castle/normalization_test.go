@@ -0,0 +1,117 @@
+package castle
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"testing"
+)
+
+const nameNormalizationCorpusPath = "../data/name-normalization.json"
+
+type nameNormalizationCase struct {
+	Name      string     `json:"name"`
+	Languages []Language `json:"languages"`
+	Expected  string     `json:"expected"`
+}
+
+func loadNameNormalizationCorpus(t *testing.T) []nameNormalizationCase {
+	t.Helper()
+	b, err := os.ReadFile(nameNormalizationCorpusPath)
+	if err != nil {
+		t.Fatalf("expected to have err nil, got %v", err)
+	}
+	var corpus []nameNormalizationCase
+	if err := json.Unmarshal(b, &corpus); err != nil {
+		t.Fatalf("expected to have err nil, got %v", err)
+	}
+	return corpus
+}
+
+func TestNormalizeName(t *testing.T) {
+	for _, tt := range loadNameNormalizationCorpus(t) {
+		currentTT := tt
+		t.Run(currentTT.Name, func(t *testing.T) {
+			t.Helper()
+			if received := NormalizeName(currentTT.Name, currentTT.Languages...); received != currentTT.Expected {
+				t.Errorf("expected [%s], got [%s]", currentTT.Expected, received)
+			}
+		})
+	}
+}
+
+func TestNormalizeNameIsIdempotent(t *testing.T) {
+	for _, tt := range loadNameNormalizationCorpus(t) {
+		currentTT := tt
+		t.Run(currentTT.Name, func(t *testing.T) {
+			t.Helper()
+			normalized := NormalizeName(currentTT.Name, currentTT.Languages...)
+			if received := NormalizeName(normalized, currentTT.Languages...); received != normalized {
+				t.Errorf("expected [%s] to be kept, got [%s]", normalized, received)
+			}
+		})
+	}
+}
+
+func TestFilteredNameUsesCountryLanguages(t *testing.T) {
+	testCases := []struct {
+		name     string
+		c        Model
+		expected string
+	}{
+		{name: "portugal", c: Model{Name: "Castelo de Torres Vedras", Country: Portugal}, expected: "torres vedras"},
+		{name: "ireland_english", c: Model{Name: "Trim Castle", Country: Ireland}, expected: "trim"},
+		{name: "ireland_irish", c: Model{Name: "Caisleán Bhaile Átha Troim", Country: Ireland}, expected: "bhaile átha troim"},
+		{name: "denmark", c: Model{Name: "Egeskov Slot", Country: Denmark}, expected: "egeskov"},
+		{name: "english_rules_not_used_on_portugal", c: Model{Name: "Castle of Almourol", Country: Portugal}, expected: "castle of almourol"},
+	}
+
+	for _, tt := range testCases {
+		currentTT := tt
+		t.Run(currentTT.name, func(t *testing.T) {
+			t.Helper()
+			if received := currentTT.c.FilteredName(); received != currentTT.expected {
+				t.Errorf("expected [%s], got [%s]", currentTT.expected, received)
+			}
+		})
+	}
+}
+
+func TestParseNameNormalizer(t *testing.T) {
+	testCases := []struct {
+		name        string
+		rules       string
+		expectedErr error
+	}{
+		{
+			name:  "valid",
+			rules: `{"countries": {"pt": ["pt"]}, "languages": {"pt": {"prefixes": ["castelo de"]}}}`,
+		},
+		{
+			name:        "country_language_without_rules",
+			rules:       `{"countries": {"pt": ["pt"]}, "languages": {}}`,
+			expectedErr: ErrInvalidNormalizationRules,
+		},
+		{
+			name:        "stopword_with_many_words",
+			rules:       `{"languages": {"en": {"stopwords": ["tower house"]}}}`,
+			expectedErr: ErrInvalidNormalizationRules,
+		},
+		{
+			name:        "abbreviation_without_expansion",
+			rules:       `{"languages": {"en": {"abbreviations": {"st.": " "}}}}`,
+			expectedErr: ErrInvalidNormalizationRules,
+		},
+	}
+
+	for _, tt := range testCases {
+		currentTT := tt
+		t.Run(currentTT.name, func(t *testing.T) {
+			t.Helper()
+			_, err := ParseNameNormalizer([]byte(currentTT.rules))
+			if !errors.Is(err, currentTT.expectedErr) {
+				t.Errorf("expected err [%v], got [%v]", currentTT.expectedErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,95 @@
+package castle
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+)
+
+var (
+	ErrFieldCannotBeOverridden  = errors.New("field cannot be overridden")
+	ErrMissingOverrideAuthor    = errors.New("override must have an author and a reason")
+	ErrUnknownPropertyCondition = errors.New("unknown property condition")
+)
+
+var (
+	// id, name and country identify a castle, while sources and status are
+	// kept by the enrichment itself
+	notOverridableFields = map[string]bool{
+		"id":      true,
+		"name":    true,
+		"country": true,
+		"sources": true,
+		"status":  true,
+	}
+)
+
+// Override is a value pinned by a curator to a field of a castle, which
+// always wins over the scraped one.
+type Override struct {
+	// ID of the castle, which the override is kept by as web names can change
+	CastleID string `json:"castleId" bson:"castleId"`
+	// web name the castle had when the override was saved
+	WebName   string    `json:"webName" bson:"webName"`
+	Field     string    `json:"field" bson:"field"`
+	Value     string    `json:"value" bson:"value"`
+	Author    string    `json:"author" bson:"author"`
+	Reason    string    `json:"reason" bson:"reason"`
+	CreatedAt time.Time `json:"createdAt" bson:"createdAt"`
+}
+
+func (o Override) Validate() error {
+	if o.Author == "" || o.Reason == "" {
+		return ErrMissingOverrideAuthor
+	}
+	if notOverridableFields[o.Field] {
+		return fmt.Errorf("%w [%s]", ErrFieldCannotBeOverridden, o.Field)
+	}
+	var scratch Model
+	if err := scratch.SetField(o.Field, o.Value); err != nil {
+		return err
+	}
+	// empty values clear the field
+	if o.Value == "" {
+		return nil
+	}
+	switch o.Field {
+	case "propertyCondition":
+		if !slices.Contains(PropertyConditions, scratch.PropertyCondition) {
+			return fmt.Errorf("%w: [%s]", ErrUnknownPropertyCondition, o.Value)
+		}
+	case "coordinates":
+		if _, err := ParseCoordinates(o.Value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ApplyOverrides returns a copy of the castle with the values of the
+// given overrides, the castle itself is not changed.
+func (m Model) ApplyOverrides(overrides []Override) (Model, error) {
+	if len(overrides) == 0 {
+		return m, nil
+	}
+	overridden := m.Copy()
+	if m.Contact != nil {
+		contact := *m.Contact
+		overridden.Contact = &contact
+	}
+	if m.VisitingInfo != nil {
+		overridden.VisitingInfo = m.VisitingInfo.Copy()
+	}
+	for _, o := range overrides {
+		if err := o.Validate(); err != nil {
+			return Model{}, err
+		}
+		if err := overridden.SetField(o.Field, o.Value); err != nil {
+			return Model{}, err
+		}
+		// curators tell the value, it is derived no more
+		overridden.unmarkDerived(o.Field)
+	}
+	return overridden, nil
+}
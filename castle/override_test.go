@@ -0,0 +1,213 @@
+package castle
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/buarki/find-castles/openinghours"
+)
+
+func TestApplyOverrides(t *testing.T) {
+	scraped := Model{
+		Name:    "trim",
+		Country: Ireland,
+		State:   "county",
+		Contact: &Contact{Phone: "123"},
+		VisitingInfo: &VisitingInfo{
+			WorkingHours: "10:00 to 17:00",
+			Facilities:   Facilities{Cafe: {Availability: Available}},
+		},
+	}
+
+	testCases := []struct {
+		name          string
+		overrides     []Override
+		field         string
+		expected      string
+		expectedError error
+	}{
+		{
+			name:      "no_overrides",
+			overrides: nil,
+			field:     "state",
+			expected:  "county",
+		},
+		{
+			name: "plain_field",
+			overrides: []Override{
+				{Field: "state", Value: "meath", Author: "curator", Reason: "address split wrongly"},
+			},
+			field:    "state",
+			expected: "meath",
+		},
+		{
+			name: "nested_field",
+			overrides: []Override{
+				{Field: "contact.phone", Value: "+353 46 943 8619", Author: "curator", Reason: "outdated phone"},
+			},
+			field:    "contact.phone",
+			expected: "+353 46 943 8619",
+		},
+		{
+			name: "facility",
+			overrides: []Override{
+				{Field: "visitingInfo.facilities.cafe", Value: "false", Author: "curator", Reason: "cafe closed"},
+			},
+			field:    "visitingInfo.facilities.cafe",
+			expected: "false",
+		},
+		{
+			name: "cleared_nested_field",
+			overrides: []Override{
+				{Field: "contact.phone", Value: "", Author: "curator", Reason: "number no longer in use"},
+			},
+			field:    "contact.phone",
+			expected: "",
+		},
+		{
+			name: "cleared_facility",
+			overrides: []Override{
+				{Field: "visitingInfo.facilities.cafe", Value: "", Author: "curator", Reason: "nobody knows"},
+			},
+			field:    "visitingInfo.facilities.cafe",
+			expected: "",
+		},
+		{
+			name: "cleared_working_hours",
+			overrides: []Override{
+				{Field: "visitingInfo.workingHours", Value: "", Author: "curator", Reason: "outdated hours"},
+			},
+			field:    "visitingInfo.workingHours",
+			expected: "",
+		},
+		{
+			name: "known_property_condition",
+			overrides: []Override{
+				{Field: "propertyCondition", Value: "ruins", Author: "curator", Reason: "it fell"},
+			},
+			field:    "propertyCondition",
+			expected: "ruins",
+		},
+		{
+			name: "unknown_property_condition",
+			overrides: []Override{
+				{Field: "propertyCondition", Value: "haunted", Author: "curator", Reason: "ghosts"},
+			},
+			expectedError: ErrUnknownPropertyCondition,
+		},
+		{
+			name: "coordinates_out_of_range",
+			overrides: []Override{
+				{Field: "coordinates", Value: "553.6,-6.79", Author: "curator", Reason: "wrong place"},
+			},
+			expectedError: ErrInvalidCoordinates,
+		},
+		{
+			name: "identity_field",
+			overrides: []Override{
+				{Field: "name", Value: "trim castle", Author: "curator", Reason: "rename"},
+			},
+			expectedError: ErrFieldCannotBeOverridden,
+		},
+		{
+			name: "unknown_field",
+			overrides: []Override{
+				{Field: "moat", Value: "true", Author: "curator", Reason: "it has one"},
+			},
+			expectedError: ErrUnknownField,
+		},
+		{
+			name: "missing_author",
+			overrides: []Override{
+				{Field: "state", Value: "meath"},
+			},
+			expectedError: ErrMissingOverrideAuthor,
+		},
+	}
+
+	for _, tt := range testCases {
+		currentTT := tt
+		t.Run(currentTT.name, func(t *testing.T) {
+			t.Helper()
+			overridden, err := scraped.ApplyOverrides(currentTT.overrides)
+			if currentTT.expectedError != nil {
+				if !errors.Is(err, currentTT.expectedError) {
+					t.Errorf("expected error %v, got %v", currentTT.expectedError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("expected err nil, got %v", err)
+			}
+			value, _ := overridden.Field(currentTT.field)
+			if value != currentTT.expected {
+				t.Errorf("expected [%s], got [%s]", currentTT.expected, value)
+			}
+		})
+	}
+
+	if scraped.Contact.Phone != "123" || !scraped.VisitingInfo.Facilities.Has(Cafe) {
+		t.Errorf("expected scraped castle to be left untouched, got %+v", scraped)
+	}
+}
+
+func TestOverridingWorkingHoursClearsOpeningHours(t *testing.T) {
+	scraped := Model{
+		Name:    "trim",
+		Country: Ireland,
+		VisitingInfo: &VisitingInfo{
+			WorkingHours: "10:00 to 17:00",
+			OpeningHours: &openinghours.Schedule{},
+		},
+	}
+
+	overridden, err := scraped.ApplyOverrides([]Override{
+		{Field: "visitingInfo.workingHours", Value: "closed for works", Author: "curator", Reason: "closed"},
+	})
+	if err != nil {
+		t.Fatalf("expected err nil, got %v", err)
+	}
+	if overridden.VisitingInfo.OpeningHours != nil {
+		t.Errorf("expected opening hours to be cleared, got %+v", overridden.VisitingInfo.OpeningHours)
+	}
+	if scraped.VisitingInfo.OpeningHours == nil {
+		t.Errorf("expected scraped castle to be left untouched, got %+v", scraped.VisitingInfo)
+	}
+}
+
+func TestOverridingStateResolvesIt(t *testing.T) {
+	scraped := Model{Name: "trim", Country: Ireland, State: "kerry", StateCode: "IE-KY", StateLabel: "co. kerry"}
+
+	testCases := []struct {
+		name          string
+		value         string
+		expectedState string
+		expectedCode  string
+		expectedLabel string
+	}{
+		{name: "known_state", value: "Co. Meath", expectedState: "meath", expectedCode: "IE-MH", expectedLabel: "Co. Meath"},
+		{name: "unknown_state", value: "nowhere", expectedState: "nowhere"},
+	}
+
+	for _, tt := range testCases {
+		currentTT := tt
+		t.Run(currentTT.name, func(t *testing.T) {
+			t.Helper()
+			overridden, err := scraped.ApplyOverrides([]Override{
+				{Field: "state", Value: currentTT.value, Author: "curator", Reason: "wrong county"},
+			})
+			if err != nil {
+				t.Fatalf("expected err nil, got %v", err)
+			}
+			if overridden.State != currentTT.expectedState {
+				t.Errorf("expected state [%s], got [%s]", currentTT.expectedState, overridden.State)
+			}
+			if overridden.StateCode != currentTT.expectedCode {
+				t.Errorf("expected state code [%s], got [%s]", currentTT.expectedCode, overridden.StateCode)
+			}
+			if overridden.StateLabel != currentTT.expectedLabel {
+				t.Errorf("expected state label [%s], got [%s]", currentTT.expectedLabel, overridden.StateLabel)
+			}
+		})
+	}
+}
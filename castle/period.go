@@ -0,0 +1,264 @@
+package castle
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+var (
+	ErrUnrecognizedPeriod = errors.New("unrecognized period")
+
+	// ex: "1258-1270", "séc. XII a XIII", "12.-13. Jh.", "XII/XIII"
+	periodRangeSeparator = regexp.MustCompile(`\s*[-/]\s*|\s+(?:a|ao|e|und|bis|to)\s+`)
+	// ex: "12th", "12.", "12º", "XII"
+	centuryPattern = regexp.MustCompile(`^(\d{1,2})(\.|º|ª|st|nd|rd|th)?$`)
+	romanPattern   = regexp.MustCompile(`^[ivx]+$`)
+	yearPattern    = regexp.MustCompile(`^\d{3,4}$`)
+	// ex: "1250s", "1250er", "1250er jahre", "década de 1250"
+	decadePattern = regexp.MustCompile(`^(?:década de |decade of )?(\d{2,3}0)(?:s|er|er jahre)?$`)
+)
+
+// PeriodPrecision tells how much a period is known, as sources date
+// castles to the year, to the decade or only to the century.
+type PeriodPrecision string
+
+const (
+	YearPrecision    PeriodPrecision = "year"
+	DecadePrecision  PeriodPrecision = "decade"
+	CenturyPrecision PeriodPrecision = "century"
+)
+
+func (p PeriodPrecision) rank() int {
+	switch p {
+	case YearPrecision:
+		return 0
+	case DecadePrecision:
+		return 1
+	default:
+		return 2
+	}
+}
+
+// Period is the range of years a castle was founded on. Centuries start on
+// years ending with 00, so the 12th century goes from 1100 to 1199.
+type Period struct {
+	Earliest  int             `json:"earliest"`
+	Latest    int             `json:"latest"`
+	Precision PeriodPrecision `json:"precision"`
+	// as the source gives it
+	Text string `json:"text"`
+}
+
+// Overlaps tells whether both periods have a year in common.
+func (p Period) Overlaps(o Period) bool {
+	return p.Earliest <= o.Latest && o.Earliest <= p.Latest
+}
+
+func (p Period) years() int {
+	return p.Latest - p.Earliest
+}
+
+// a part of a century some sources narrow periods to, like "2. Hälfte"
+type centuryPart struct {
+	prefixes []string
+	from     int
+	to       int
+}
+
+var (
+	centuryParts = []centuryPart{
+		{prefixes: []string{"1. hälfte des", "1. hälfte", "1. h.", "first half of the", "first half of", "1.ª metade do", "1ª metade do", "primeira metade do"}, from: 0, to: 49},
+		{prefixes: []string{"2. hälfte des", "2. hälfte", "2. h.", "second half of the", "second half of", "2.ª metade do", "2ª metade do", "segunda metade do"}, from: 50, to: 99},
+		{prefixes: []string{"anfang des", "anfang", "a.", "frühes", "early", "inícios do", "início do", "princípios do", "princípio do"}, from: 0, to: 32},
+		{prefixes: []string{"mitte des", "mitte", "m.", "mid", "meados do"}, from: 33, to: 66},
+		{prefixes: []string{"ende des", "ende", "e.", "spätes", "late", "finais do", "fins do", "final do"}, from: 67, to: 99},
+	}
+
+	centuryPrefixes = []string{"séculos", "século", "séc.", "sec."}
+	centurySuffixes = []string{"jahrhunderts", "jahrhundert", "jhs.", "jh.", "jh", "centuries", "century", "c."}
+	circaPrefixes   = []string{"cerca de", "circa", "ca.", "c.", "um", "around"}
+)
+
+// ParsePeriod understands explicit years ("1258", "c. 1250", "1258-1270"),
+// decades ("1250s", "1250er Jahre"), and centuries written with Roman
+// numerals ("XII", "séc. XII a XIII"), with German notation ("12. Jh.",
+// "2.H.13.Jh.") and with English ordinals ("12th", "late 12th century").
+func ParsePeriod(text string) (Period, error) {
+	normalized := normalizePeriodText(text)
+	if normalized == "" {
+		return Period{}, fmt.Errorf("%w: [%s]", ErrUnrecognizedPeriod, text)
+	}
+	marked := hasCenturyMarker(normalized)
+
+	var parts []Period
+	for _, rawPart := range periodRangeSeparator.Split(normalized, -1) {
+		// "1258-70" only gives the last digits of the second year
+		if len(parts) > 0 && parts[len(parts)-1].Precision == YearPrecision && len(rawPart) == 2 && !marked {
+			if shortYear, err := strconv.Atoi(rawPart); err == nil {
+				century := parts[len(parts)-1].Earliest / 100 * 100
+				rawPart = strconv.Itoa(century + shortYear)
+			}
+		}
+		part, err := parsePeriodPart(rawPart, marked)
+		if err != nil {
+			return Period{}, fmt.Errorf("%w: [%s]", ErrUnrecognizedPeriod, text)
+		}
+		parts = append(parts, part)
+	}
+
+	period := Period{
+		Earliest:  parts[0].Earliest,
+		Latest:    parts[len(parts)-1].Latest,
+		Precision: parts[0].Precision,
+		Text:      strings.TrimSpace(text),
+	}
+	for _, part := range parts {
+		if part.Precision.rank() > period.Precision.rank() {
+			period.Precision = part.Precision
+		}
+	}
+	if period.Earliest > period.Latest {
+		return Period{}, fmt.Errorf("%w: [%s] ends before it starts", ErrUnrecognizedPeriod, text)
+	}
+	return period, nil
+}
+
+// normalizePeriodText lower cases the text, drops the parentheses around it
+// and splits abbreviations written together, like "2.h.13.jh.".
+func normalizePeriodText(text string) string {
+	text = strings.ToLower(strings.TrimSpace(text))
+	text = strings.Trim(text, "()[] ")
+	text = strings.NewReplacer("–", "-", "—", "-", "mid-", "mid ").Replace(text)
+
+	var b strings.Builder
+	runes := []rune(text)
+	for i, r := range runes {
+		b.WriteRune(r)
+		if r == '.' && i+1 < len(runes) && runes[i+1] < unicode.MaxASCII && (unicode.IsLetter(runes[i+1]) || unicode.IsDigit(runes[i+1])) {
+			b.WriteRune(' ')
+		}
+	}
+	return strings.Join(strings.Fields(b.String()), " ")
+}
+
+func hasCenturyMarker(text string) bool {
+	for _, word := range strings.Fields(text) {
+		// "c." is also written before years meaning circa
+		if word != "c." && (slices.Contains(centuryPrefixes, word) || slices.Contains(centurySuffixes, word)) {
+			return true
+		}
+	}
+	return false
+}
+
+func parsePeriodPart(part string, marked bool) (Period, error) {
+	var narrowedTo *centuryPart
+	for i := range centuryParts {
+		if rest, found := cutPhrasePrefix(part, centuryParts[i].prefixes); found {
+			narrowedTo = &centuryParts[i]
+			part = rest
+			break
+		}
+	}
+	if rest, found := cutPhrasePrefix(part, centuryPrefixes); found {
+		part = rest
+	}
+	for _, suffix := range centurySuffixes {
+		if rest, found := strings.CutSuffix(part, " "+suffix); found {
+			part = rest
+			break
+		}
+	}
+
+	if century, found := centuryOf(part, marked); found {
+		start := (century - 1) * 100
+		period := Period{Earliest: start, Latest: start + 99, Precision: CenturyPrecision}
+		if narrowedTo != nil {
+			period.Earliest = start + narrowedTo.from
+			period.Latest = start + narrowedTo.to
+		}
+		return period, nil
+	}
+	if narrowedTo != nil {
+		return Period{}, fmt.Errorf("only centuries can be narrowed, got [%s]", part)
+	}
+
+	if matches := decadePattern.FindStringSubmatch(part); matches != nil && part != matches[1] {
+		decade, _ := strconv.Atoi(matches[1])
+		return Period{Earliest: decade, Latest: decade + 9, Precision: DecadePrecision}, nil
+	}
+
+	circa := false
+	if rest, found := cutPhrasePrefix(part, circaPrefixes); found {
+		circa = true
+		part = rest
+	}
+	if yearPattern.MatchString(part) {
+		year, _ := strconv.Atoi(part)
+		if circa {
+			return Period{Earliest: year - 5, Latest: year + 5, Precision: DecadePrecision}, nil
+		}
+		return Period{Earliest: year, Latest: year, Precision: YearPrecision}, nil
+	}
+	return Period{}, fmt.Errorf("unknown period [%s]", part)
+}
+
+// centuryOf accepts bare numbers only when the text says it has centuries,
+// as "12" alone could be anything.
+func centuryOf(token string, marked bool) (int, bool) {
+	var century int
+	if romanPattern.MatchString(token) {
+		century = romanToInt(token)
+	} else if matches := centuryPattern.FindStringSubmatch(token); matches != nil {
+		if matches[2] == "" && !marked {
+			return 0, false
+		}
+		century, _ = strconv.Atoi(matches[1])
+	}
+	if century < 1 || century > 21 {
+		return 0, false
+	}
+	return century, true
+}
+
+func romanToInt(roman string) int {
+	values := map[rune]int{'i': 1, 'v': 5, 'x': 10}
+	total := 0
+	runes := []rune(roman)
+	for i, r := range runes {
+		value := values[r]
+		if i+1 < len(runes) && value < values[runes[i+1]] {
+			total -= value
+		} else {
+			total += value
+		}
+	}
+	return total
+}
+
+func cutPhrasePrefix(text string, phrases []string) (string, bool) {
+	for _, phrase := range phrases {
+		if rest, found := strings.CutPrefix(text, phrase+" "); found {
+			return rest, true
+		}
+	}
+	return text, false
+}
+
+// Foundation returns the period the castle was founded on, when its
+// foundation period can be parsed.
+func (m Model) Foundation() (Period, bool) {
+	if m.FoundationPeriod == "" {
+		return Period{}, false
+	}
+	p, err := ParsePeriod(m.FoundationPeriod)
+	if err != nil {
+		return Period{}, false
+	}
+	return p, true
+}
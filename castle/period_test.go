@@ -0,0 +1,117 @@
+package castle
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParsePeriod(t *testing.T) {
+	testCases := []struct {
+		text     string
+		expected Period
+	}{
+		{text: "1172", expected: Period{Earliest: 1172, Latest: 1172, Precision: YearPrecision}},
+		{text: "1258-1270", expected: Period{Earliest: 1258, Latest: 1270, Precision: YearPrecision}},
+		{text: "1258–70", expected: Period{Earliest: 1258, Latest: 1270, Precision: YearPrecision}},
+		{text: "c. 1250", expected: Period{Earliest: 1245, Latest: 1255, Precision: DecadePrecision}},
+		{text: "um 1250", expected: Period{Earliest: 1245, Latest: 1255, Precision: DecadePrecision}},
+		{text: "1250s", expected: Period{Earliest: 1250, Latest: 1259, Precision: DecadePrecision}},
+		{text: "1250er Jahre", expected: Period{Earliest: 1250, Latest: 1259, Precision: DecadePrecision}},
+		{text: "XX", expected: Period{Earliest: 1900, Latest: 1999, Precision: CenturyPrecision}},
+		{text: "Séc. XII", expected: Period{Earliest: 1100, Latest: 1199, Precision: CenturyPrecision}},
+		{text: "séculos XII e XIII", expected: Period{Earliest: 1100, Latest: 1299, Precision: CenturyPrecision}},
+		{text: "XIV/XV", expected: Period{Earliest: 1300, Latest: 1499, Precision: CenturyPrecision}},
+		{text: "segunda metade do séc. XIV", expected: Period{Earliest: 1350, Latest: 1399, Precision: CenturyPrecision}},
+		{text: "12th", expected: Period{Earliest: 1100, Latest: 1199, Precision: CenturyPrecision}},
+		{text: "late 12th century", expected: Period{Earliest: 1167, Latest: 1199, Precision: CenturyPrecision}},
+		{text: "mid-15th century", expected: Period{Earliest: 1433, Latest: 1466, Precision: CenturyPrecision}},
+		{text: "10.Jh.", expected: Period{Earliest: 900, Latest: 999, Precision: CenturyPrecision}},
+		{text: "12. Jahrhundert", expected: Period{Earliest: 1100, Latest: 1199, Precision: CenturyPrecision}},
+		{text: "2.H.13.Jh.", expected: Period{Earliest: 1250, Latest: 1299, Precision: CenturyPrecision}},
+		{text: "Ende des 10. Jhs.", expected: Period{Earliest: 967, Latest: 999, Precision: CenturyPrecision}},
+		{text: "12.-13. Jh.", expected: Period{Earliest: 1100, Latest: 1299, Precision: CenturyPrecision}},
+		{text: "13. Jh. - 1320", expected: Period{Earliest: 1200, Latest: 1320, Precision: CenturyPrecision}},
+	}
+
+	for _, tt := range testCases {
+		currentTT := tt
+		t.Run(currentTT.text, func(t *testing.T) {
+			t.Helper()
+			received, err := ParsePeriod(currentTT.text)
+			if err != nil {
+				t.Fatalf("expected err nil, got %v", err)
+			}
+			expected := currentTT.expected
+			expected.Text = currentTT.text
+			if received != expected {
+				t.Errorf("expected [%+v], got [%+v]", expected, received)
+			}
+		})
+	}
+}
+
+func TestParsePeriodUnrecognized(t *testing.T) {
+	for _, text := range []string{"", "unbekannt", "(ant. a 958)", "12", "1300-1200", "late 1250", "XXX"} {
+		if _, err := ParsePeriod(text); !errors.Is(err, ErrUnrecognizedPeriod) {
+			t.Errorf("expected [%s] to be unrecognized, got %v", text, err)
+		}
+	}
+}
+
+func TestIsProbablyComparesFoundation(t *testing.T) {
+	c := Model{Name: "castelo velho", Country: Portugal, City: "alcoutim", FoundationPeriod: "séc. XIII"}
+
+	testCases := []struct {
+		name             string
+		foundationPeriod string
+		expected         bool
+	}{
+		{name: "overlapping_periods", foundationPeriod: "1258", expected: true},
+		{name: "unparseable_period", foundationPeriod: "desconhecida", expected: true},
+		{name: "unknown_period", foundationPeriod: "", expected: true},
+		{name: "periods_far_apart", foundationPeriod: "XVII", expected: false},
+	}
+
+	for _, tt := range testCases {
+		currentTT := tt
+		t.Run(currentTT.name, func(t *testing.T) {
+			t.Helper()
+			other := c
+			other.FoundationPeriod = currentTT.foundationPeriod
+			if received := c.IsProbably(other); received != currentTT.expected {
+				t.Errorf("expected [%v], got [%v]", currentTT.expected, received)
+			}
+		})
+	}
+}
+
+func TestMergeWithKeepsMostPreciseFoundation(t *testing.T) {
+	century := Model{Name: "castelo velho", Country: Portugal, FoundationPeriod: "Séc. XIII"}
+	year := Model{Name: "castelo velho", Country: Portugal, FoundationPeriod: "1258"}
+
+	if received := century.MergeWith(year).FoundationPeriod; received != "1258" {
+		t.Errorf("expected [1258], got [%s]", received)
+	}
+	if received := year.MergeWith(century).FoundationPeriod; received != "1258" {
+		t.Errorf("expected [1258], got [%s]", received)
+	}
+}
+
+func TestSortByFoundation(t *testing.T) {
+	older := Model{Name: "guimaraes", Country: Portugal, FoundationPeriod: "X"}
+	newer := Model{Name: "almourol", Country: Portugal, FoundationPeriod: "1171"}
+	unknown := Model{Name: "leiria", Country: Portugal}
+
+	if received := SortByFoundation.Compare(older, newer); received >= 0 {
+		t.Errorf("expected older castle first, got [%d]", received)
+	}
+	if received := SortByWebName.Compare(older, newer); received <= 0 {
+		t.Errorf("expected almourol first, got [%d]", received)
+	}
+	if SortByFoundation.Lists(unknown) {
+		t.Errorf("expected castle with unknown foundation not to be listed")
+	}
+	if _, err := ParseSortOrder("size"); !errors.Is(err, ErrUnknownSortOrder) {
+		t.Errorf("expected err [%v], got [%v]", ErrUnknownSortOrder, err)
+	}
+}
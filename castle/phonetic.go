@@ -0,0 +1,52 @@
+package castle
+
+import "strings"
+
+var (
+	soundexCodes = map[rune]byte{
+		'b': '1', 'f': '1', 'p': '1', 'v': '1',
+		'c': '2', 'g': '2', 'j': '2', 'k': '2', 'q': '2', 's': '2', 'x': '2', 'z': '2',
+		'd': '3', 't': '3',
+		'l': '4',
+		'm': '5', 'n': '5',
+		'r': '6',
+	}
+)
+
+// Soundex returns the american soundex code of an ASCII word, so that
+// slightly different spellings like "Carrickfergus" and "Carickfergus"
+// share a key. Non ASCII letters are ignored.
+func Soundex(word string) string {
+	word = strings.ToLower(word)
+	var code []byte
+	var lastCode byte
+	for _, r := range word {
+		if r < 'a' || r > 'z' {
+			continue
+		}
+		digit, hasCode := soundexCodes[r]
+		if len(code) == 0 {
+			code = append(code, byte(r-'a'+'A'))
+			lastCode = digit
+			continue
+		}
+		switch {
+		case hasCode && digit != lastCode:
+			code = append(code, digit)
+			lastCode = digit
+		case !hasCode && r != 'h' && r != 'w':
+			// vowels separate equal codes, h and w do not
+			lastCode = 0
+		}
+		if len(code) == 4 {
+			break
+		}
+	}
+	if len(code) == 0 {
+		return ""
+	}
+	for len(code) < 4 {
+		code = append(code, '0')
+	}
+	return string(code)
+}
@@ -0,0 +1,149 @@
+package castle
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrInvalidBoundingBox = errors.New("invalid bounding box")
+	ErrUnknownSortOrder   = errors.New("unknown sort order")
+)
+
+type BoundingBox struct {
+	MinLongitude float64
+	MinLatitude  float64
+	MaxLongitude float64
+	MaxLatitude  float64
+}
+
+// ParseBoundingBox follows the GeoJSON order: "minLon,minLat,maxLon,maxLat".
+func ParseBoundingBox(raw string) (BoundingBox, error) {
+	parts := strings.Split(raw, ",")
+	if len(parts) != 4 {
+		return BoundingBox{}, fmt.Errorf("%w: [%s]", ErrInvalidBoundingBox, raw)
+	}
+	var values [4]float64
+	for i, part := range parts {
+		value, err := strconv.ParseFloat(strings.TrimSpace(part), 64)
+		if err != nil {
+			return BoundingBox{}, fmt.Errorf("%w: [%s]", ErrInvalidBoundingBox, raw)
+		}
+		values[i] = value
+	}
+	b := BoundingBox{
+		MinLongitude: values[0],
+		MinLatitude:  values[1],
+		MaxLongitude: values[2],
+		MaxLatitude:  values[3],
+	}
+	if b.MinLongitude > b.MaxLongitude || b.MinLatitude > b.MaxLatitude {
+		return BoundingBox{}, fmt.Errorf("%w: [%s] min values must be smaller than max ones", ErrInvalidBoundingBox, raw)
+	}
+	return b, nil
+}
+
+func (b BoundingBox) Contains(c Coordinates) bool {
+	return c.Latitude >= b.MinLatitude && c.Latitude <= b.MaxLatitude &&
+		c.Longitude >= b.MinLongitude && c.Longitude <= b.MaxLongitude
+}
+
+// Query filters castles, empty fields do not filter anything. Coarse
+// conditions also match the conditions projected into them, and facilities
+// must be known to be available. BuiltBefore and
+// BuiltAfter are years and only match castles whose whole foundation period
+// is before, or from, them.
+type Query struct {
+	Countries   []Country
+	States      []string
+	Conditions  []PropertyCondition
+	Facilities  []Facility
+	BoundingBox *BoundingBox
+	BuiltBefore int
+	BuiltAfter  int
+}
+
+func (q Query) Matches(m Model) bool {
+	if len(q.Countries) > 0 && !slices.Contains(q.Countries, m.Country) {
+		return false
+	}
+	if len(q.States) > 0 && !slices.Contains(q.States, strings.ToLower(m.State)) &&
+		(m.StateCode == "" || !slices.Contains(q.States, strings.ToLower(m.StateCode))) {
+		return false
+	}
+	if len(q.Conditions) > 0 && !slices.Contains(CoveredConditions(q.Conditions), m.PropertyCondition) {
+		return false
+	}
+	for _, facility := range q.Facilities {
+		if m.VisitingInfo == nil || !m.VisitingInfo.Facilities.Has(facility) {
+			return false
+		}
+	}
+	if q.BoundingBox != nil {
+		location, ok := m.Location()
+		if !ok || !q.BoundingBox.Contains(location) {
+			return false
+		}
+	}
+	if q.BuiltBefore != 0 || q.BuiltAfter != 0 {
+		foundation, ok := m.Foundation()
+		if !ok {
+			return false
+		}
+		if q.BuiltBefore != 0 && foundation.Latest >= q.BuiltBefore {
+			return false
+		}
+		if q.BuiltAfter != 0 && foundation.Earliest < q.BuiltAfter {
+			return false
+		}
+	}
+	return true
+}
+
+// SortOrder is the order castles are listed in.
+type SortOrder string
+
+const (
+	SortByWebName SortOrder = "webName"
+	// oldest first, castles with an unknown foundation period are not listed
+	SortByFoundation SortOrder = "foundation"
+)
+
+// ParseSortOrder sorts by web name when no order is given.
+func ParseSortOrder(raw string) (SortOrder, error) {
+	switch SortOrder(raw) {
+	case "", SortByWebName:
+		return SortByWebName, nil
+	case SortByFoundation:
+		return SortByFoundation, nil
+	default:
+		return "", fmt.Errorf("%w: [%s]", ErrUnknownSortOrder, raw)
+	}
+}
+
+// Lists tells whether castles sorted this way include the given one.
+func (o SortOrder) Lists(m Model) bool {
+	if o == SortByFoundation {
+		_, ok := m.Foundation()
+		return ok
+	}
+	return true
+}
+
+// Compare compares castles listed on this order, ties are broken by web name.
+func (o SortOrder) Compare(a, b Model) int {
+	aWebName, _ := a.WebName()
+	bWebName, _ := b.WebName()
+	if o == SortByFoundation {
+		aFoundation, _ := a.Foundation()
+		bFoundation, _ := b.Foundation()
+		if aFoundation.Earliest != bFoundation.Earliest {
+			return cmp.Compare(aFoundation.Earliest, bFoundation.Earliest)
+		}
+	}
+	return strings.Compare(aWebName, bWebName)
+}
@@ -0,0 +1,75 @@
+package castle
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseBoundingBox(t *testing.T) {
+	b, err := ParseBoundingBox("-10.5,36.9,-6.1,42.2")
+	if err != nil {
+		t.Fatalf("expected err nil, got %v", err)
+	}
+	expected := BoundingBox{MinLongitude: -10.5, MinLatitude: 36.9, MaxLongitude: -6.1, MaxLatitude: 42.2}
+	if b != expected {
+		t.Errorf("expected [%v], got [%v]", expected, b)
+	}
+
+	for _, raw := range []string{"", "1,2,3", "a,b,c,d", "10,10,0,0"} {
+		if _, err := ParseBoundingBox(raw); !errors.Is(err, ErrInvalidBoundingBox) {
+			t.Errorf("expected [%s] to be invalid, got %v", raw, err)
+		}
+	}
+}
+
+func TestQueryMatches(t *testing.T) {
+	guimaraes := Model{
+		Name:              "guimaraes",
+		Country:           Portugal,
+		State:             "braga",
+		StateCode:         "PT-03",
+		PropertyCondition: Intact,
+		Coordinates:       "41.448,-8.290",
+		FoundationPeriod:  "séc. X",
+		VisitingInfo: &VisitingInfo{
+			Facilities: Facilities{Parking: {Availability: Available}, Cafe: {Availability: NotAvailable}},
+		},
+	}
+	portugal := BoundingBox{MinLongitude: -10.5, MinLatitude: 36.9, MaxLongitude: -6.1, MaxLatitude: 42.2}
+	ireland := BoundingBox{MinLongitude: -10.7, MinLatitude: 51.4, MaxLongitude: -5.9, MaxLatitude: 55.4}
+
+	testCases := []struct {
+		name    string
+		query   Query
+		matches bool
+	}{
+		{name: "when query is empty", query: Query{}, matches: true},
+		{name: "when country matches", query: Query{Countries: []Country{Portugal, Ireland}}, matches: true},
+		{name: "when country does not match", query: Query{Countries: []Country{Ireland}}, matches: false},
+		{name: "when state matches", query: Query{States: []string{"braga"}}, matches: true},
+		{name: "when state code matches", query: Query{States: []string{"pt-03"}}, matches: true},
+		{name: "when state does not match", query: Query{States: []string{"pt-13", "porto"}}, matches: false},
+		{name: "when condition does not match", query: Query{Conditions: []PropertyCondition{Ruins}}, matches: false},
+		{name: "when coarse condition covers it", query: Query{Conditions: []PropertyCondition{Intact}}, matches: true},
+		{name: "when more specific condition does not match", query: Query{Conditions: []PropertyCondition{Restored}}, matches: false},
+		{name: "when facility is available", query: Query{Facilities: []Facility{Parking}}, matches: true},
+		{name: "when facility is not available", query: Query{Facilities: []Facility{Cafe}}, matches: false},
+		{name: "when facility is unknown", query: Query{Facilities: []Facility{Restrooms}}, matches: false},
+		{name: "when castle is inside bounding box", query: Query{BoundingBox: &portugal}, matches: true},
+		{name: "when castle is outside bounding box", query: Query{BoundingBox: &ireland}, matches: false},
+		{name: "when castle was built before", query: Query{BuiltBefore: 1200}, matches: true},
+		{name: "when castle was not built before", query: Query{BuiltBefore: 950}, matches: false},
+		{name: "when castle was built after", query: Query{BuiltAfter: 900}, matches: true},
+		{name: "when castle was not built after", query: Query{BuiltAfter: 1000}, matches: false},
+	}
+
+	for _, tt := range testCases {
+		currentTT := tt
+		t.Run(currentTT.name, func(t *testing.T) {
+			t.Helper()
+			if received := currentTT.query.Matches(guimaraes); received != currentTT.matches {
+				t.Errorf("expected [%v], got [%v]", currentTT.matches, received)
+			}
+		})
+	}
+}
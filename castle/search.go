@@ -0,0 +1,90 @@
+package castle
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/buarki/find-castles/toascii"
+)
+
+// weights of the fields castles are searched by, the same the text index
+// of the database gives them
+const (
+	nameSearchWeight       = 10
+	otherNamesSearchWeight = 8
+	citySearchWeight       = 5
+	placeSearchWeight      = 3
+)
+
+// SearchTerms splits the query into the lowercase ASCII words castles are
+// searched by, ex: "Castelo de Guimarães" into castelo, de and guimaraes.
+func SearchTerms(query string) []string {
+	ascii, err := toascii.From(query)
+	if err != nil {
+		ascii = query
+	}
+	return strings.FieldsFunc(strings.ToLower(ascii), func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9')
+	})
+}
+
+// SearchNames returns the names of the castle transliterated with the rules
+// of their languages when they differ from the shared ones, like "schloss
+// buerresheim" for the German "Schloß Bürresheim", so searching either way
+// finds it. Localized names follow their language and the name the languages
+// of the country.
+func (m Model) SearchNames() []string {
+	names := []string{}
+	add := func(name string, language Language) {
+		transliterated, err := language.ToASCII(name)
+		if err != nil {
+			return
+		}
+		transliterated = strings.ToLower(transliterated)
+		shared, err := toascii.From(name)
+		if err != nil || strings.ToLower(shared) == transliterated || slices.Contains(names, transliterated) {
+			return
+		}
+		names = append(names, transliterated)
+	}
+	for _, language := range defaultNameNormalizer.CountryLanguages(m.Country) {
+		add(m.Name, language)
+	}
+	for _, language := range Languages {
+		if name, found := m.LocalizedNames[language]; found {
+			add(name, language)
+		}
+	}
+	return names
+}
+
+// SearchScore tells how relevant the castle is to the given terms, summing
+// the weight of each field for every term among its words. Castles scoring
+// zero don't match any term.
+func (m Model) SearchScore(terms []string) int {
+	score := 0
+	add := func(weight int, texts ...string) {
+		words := make(map[string]bool)
+		for _, text := range texts {
+			for _, word := range SearchTerms(text) {
+				words[word] = true
+			}
+		}
+		for _, term := range terms {
+			if words[term] {
+				score += weight
+			}
+		}
+	}
+	add(nameSearchWeight, m.Name)
+	add(otherNamesSearchWeight, m.AlternateNames...)
+	names := m.SearchNames()
+	for _, name := range m.LocalizedNames {
+		names = append(names, name)
+	}
+	add(otherNamesSearchWeight, names...)
+	add(citySearchWeight, m.City)
+	add(placeSearchWeight, m.State)
+	add(placeSearchWeight, m.District)
+	return score
+}
@@ -0,0 +1,113 @@
+package castle
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestSearchTerms(t *testing.T) {
+	testCases := []struct {
+		name     string
+		query    string
+		expected []string
+	}{
+		{name: "accents", query: "Castelo de Guimarães", expected: []string{"castelo", "de", "guimaraes"}},
+		{name: "punctuation", query: "  St. Michael's Mount ", expected: []string{"st", "michael", "s", "mount"}},
+		{name: "empty", query: " ", expected: []string{}},
+	}
+
+	for _, tt := range testCases {
+		currentTT := tt
+		t.Run(currentTT.name, func(t *testing.T) {
+			t.Helper()
+			received := SearchTerms(currentTT.query)
+
+			if diff := cmp.Diff(currentTT.expected, received); diff != "" {
+				t.Errorf("diff: %v", diff)
+			}
+		})
+	}
+}
+
+func TestSearchScoreOfNamesTransliteratedWithTheirLanguages(t *testing.T) {
+	c := Model{Name: "spøttrup borg", Country: Denmark}
+
+	for _, query := range []string{"spottrup", "spoettrup"} {
+		if received := c.SearchScore(SearchTerms(query)); received == 0 {
+			t.Errorf("expected [%s] to match, got score [%d]", query, received)
+		}
+	}
+}
+
+func TestSearchScore(t *testing.T) {
+	c := Model{
+		Name:           "guimaraes",
+		Country:        Portugal,
+		AlternateNames: []string{"castelo da fundação"},
+		LocalizedNames: map[Language]string{English: "Guimarães Castle"},
+		City:           "guimarães",
+		State:          "braga",
+	}
+
+	testCases := []struct {
+		name     string
+		query    string
+		expected int
+	}{
+		{name: "name_localized_name_and_city", query: "Guimarães", expected: 10 + 8 + 5},
+		{name: "alternate_name", query: "fundacao", expected: 8},
+		{name: "localized_name", query: "castle", expected: 8},
+		{name: "state", query: "Braga", expected: 3},
+		{name: "any_term", query: "braga lisboa", expected: 3},
+		{name: "no_term", query: "lisboa", expected: 0},
+	}
+
+	for _, tt := range testCases {
+		currentTT := tt
+		t.Run(currentTT.name, func(t *testing.T) {
+			t.Helper()
+			received := c.SearchScore(SearchTerms(currentTT.query))
+
+			if received != currentTT.expected {
+				t.Errorf("expected [%d], got [%d]", currentTT.expected, received)
+			}
+		})
+	}
+}
+
+func TestSearchNames(t *testing.T) {
+	testCases := []struct {
+		name     string
+		castle   Model
+		expected []string
+	}{
+		{
+			name:     "name_on_the_language_of_the_country",
+			castle:   Model{Name: "spøttrup borg", Country: Denmark},
+			expected: []string{"spoettrup borg"},
+		},
+		{
+			name:     "localized_names_on_their_languages",
+			castle:   Model{Name: "kronborg", Country: Denmark, LocalizedNames: map[Language]string{Danish: "Kronborg Slot", German: "Schloss Kronborg in Dänemark"}},
+			expected: []string{"schloss kronborg in daenemark"},
+		},
+		{
+			name:     "same_with_the_shared_rules",
+			castle:   Model{Name: "castelo de guimarães", Country: Portugal},
+			expected: []string{},
+		},
+	}
+
+	for _, tt := range testCases {
+		currentTT := tt
+		t.Run(currentTT.name, func(t *testing.T) {
+			t.Helper()
+			received := currentTT.castle.SearchNames()
+
+			if diff := cmp.Diff(currentTT.expected, received); diff != "" {
+				t.Errorf("diff: %v", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,14 @@
+package castle
+
+// Status tells whether a castle is still being listed by its sources.
+type Status string
+
+const (
+	Active  Status = "active"
+	Stale   Status = "stale"
+	Removed Status = "removed"
+)
+
+func (s Status) String() string {
+	return string(s)
+}
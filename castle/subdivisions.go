@@ -0,0 +1,197 @@
+package castle
+
+import (
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/buarki/find-castles/toascii"
+)
+
+var (
+	//go:embed subdivisions.json
+	subdivisionsData []byte
+
+	defaultGazetteer = mustParseGazetteer(subdivisionsData)
+
+	ErrInvalidGazetteer = errors.New("invalid gazetteer")
+
+	subdivisionCodeMatcher = regexp.MustCompile(`^[A-Za-z]{2}-[A-Za-z0-9]{1,3}$`)
+	// states like "Berkshire, Greater London" or "Pohronie/Grantal" name more than one place
+	statePartsSeparators = regexp.MustCompile(`[,/;()]`)
+)
+
+// Subdivision is an ISO 3166-2 subdivision of a country, like IE-KY for
+// County Kerry.
+type Subdivision struct {
+	Code string `json:"code"`
+	Name string `json:"name"`
+}
+
+// GazetteerEntry is a subdivision along with the other names sources call it by.
+type GazetteerEntry struct {
+	Code    string   `json:"code"`
+	Name    string   `json:"name"`
+	Aliases []string `json:"aliases"`
+}
+
+// CountryGazetteer has the subdivisions of a country, and the words around
+// their names that don't tell them apart, like "Co." or "kraj".
+type CountryGazetteer struct {
+	Prefixes     []string         `json:"prefixes"`
+	Suffixes     []string         `json:"suffixes"`
+	Subdivisions []GazetteerEntry `json:"subdivisions"`
+}
+
+// Gazetteer resolves the names of subdivisions, on any language and written
+// as any source does, into ISO 3166-2 subdivisions.
+type Gazetteer struct {
+	countries map[Country]compiledGazetteer
+}
+
+type compiledGazetteer struct {
+	prefixes [][]string
+	suffixes [][]string
+	byKey    map[string]Subdivision
+}
+
+// ParseGazetteer reads the subdivisions of each country from JSON.
+func ParseGazetteer(data []byte) (*Gazetteer, error) {
+	var countries map[Country]CountryGazetteer
+	if err := json.Unmarshal(data, &countries); err != nil {
+		return nil, fmt.Errorf("failed to parse gazetteer, got %v", err)
+	}
+	return NewGazetteer(countries)
+}
+
+func mustParseGazetteer(data []byte) *Gazetteer {
+	gazetteer, err := ParseGazetteer(data)
+	if err != nil {
+		panic(err)
+	}
+	return gazetteer
+}
+
+// NewGazetteer fails when a name is given to different subdivisions of the
+// same country, as it couldn't tell which one the name is.
+func NewGazetteer(countries map[Country]CountryGazetteer) (*Gazetteer, error) {
+	gazetteer := &Gazetteer{countries: make(map[Country]compiledGazetteer, len(countries))}
+	for country, rules := range countries {
+		compiled := compiledGazetteer{
+			prefixes: subdivisionAffixes(rules.Prefixes),
+			suffixes: subdivisionAffixes(rules.Suffixes),
+			byKey:    make(map[string]Subdivision),
+		}
+		for _, entry := range rules.Subdivisions {
+			if !subdivisionCodeMatcher.MatchString(entry.Code) || entry.Name == "" {
+				return nil, fmt.Errorf("%w: subdivision [%s] of [%s] must have an ISO 3166-2 code and a name", ErrInvalidGazetteer, entry.Code, country)
+			}
+			subdivision := Subdivision{Code: strings.ToUpper(entry.Code), Name: entry.Name}
+			for _, name := range append([]string{entry.Name, entry.Code}, entry.Aliases...) {
+				key := compiled.keyOf(name)
+				if found, taken := compiled.byKey[key]; taken && found.Code != subdivision.Code {
+					return nil, fmt.Errorf("%w: [%s] of [%s] names both [%s] and [%s]", ErrInvalidGazetteer, name, country, found.Code, subdivision.Code)
+				}
+				compiled.byKey[key] = subdivision
+			}
+		}
+		gazetteer.countries[country] = compiled
+	}
+	return gazetteer, nil
+}
+
+// Resolve finds the subdivision of the country the text names. Texts naming
+// many places, like "Kent, South East England", resolve into the first part
+// naming a subdivision.
+func (g *Gazetteer) Resolve(country Country, text string) (Subdivision, bool) {
+	compiled, found := g.countries[country]
+	if !found {
+		return Subdivision{}, false
+	}
+	if subdivision, found := compiled.byKey[compiled.keyOf(text)]; found {
+		return subdivision, true
+	}
+	for _, part := range statePartsSeparators.Split(text, -1) {
+		if subdivision, found := compiled.byKey[compiled.keyOf(part)]; found {
+			return subdivision, true
+		}
+	}
+	return Subdivision{}, false
+}
+
+// ResolveSubdivision resolves the text with the bundled gazetteer.
+func ResolveSubdivision(country Country, text string) (Subdivision, bool) {
+	return defaultGazetteer.Resolve(country, text)
+}
+
+// keyOf is the name without case, accents, punctuation, spaces and the
+// words around it that don't tell subdivisions apart, so "Co. Kerry" and
+// "County Kerry" both are kerry.
+func (c compiledGazetteer) keyOf(name string) string {
+	words := subdivisionWords(name)
+	for _, prefix := range c.prefixes {
+		if len(prefix) < len(words) && slices.Equal(words[:len(prefix)], prefix) {
+			words = words[len(prefix):]
+			break
+		}
+	}
+	for _, suffix := range c.suffixes {
+		if len(suffix) < len(words) && slices.Equal(words[len(words)-len(suffix):], suffix) {
+			words = words[:len(words)-len(suffix)]
+			break
+		}
+	}
+	return strings.Join(words, "")
+}
+
+// subdivisionAffixes are the words of each affix, the longest first so
+// "county council" is removed before "council".
+func subdivisionAffixes(affixes []string) [][]string {
+	words := make([][]string, 0, len(affixes))
+	for _, affix := range affixes {
+		if affixWords := subdivisionWords(affix); len(affixWords) > 0 {
+			words = append(words, affixWords)
+		}
+	}
+	slices.SortStableFunc(words, func(a, b []string) int {
+		return len(b) - len(a)
+	})
+	return words
+}
+
+func subdivisionWords(text string) []string {
+	ascii, err := toascii.From(strings.ToLower(text))
+	if err != nil {
+		ascii = strings.ToLower(text)
+	}
+	return strings.FieldsFunc(ascii, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9')
+	})
+}
+
+// IsSubdivisionCode tells whether the text looks like an ISO 3166-2 code, like ie-ky.
+func IsSubdivisionCode(text string) bool {
+	return subdivisionCodeMatcher.MatchString(text)
+}
+
+// ResolveState resolves the state of the castle, as the source gives it,
+// into the subdivision it names. The state becomes the name of the
+// subdivision and the text of the source is kept as the state label.
+// Castles already resolved or without state are left as they are.
+func (m *Model) ResolveState() bool {
+	if m.StateCode != "" || m.State == "" {
+		return true
+	}
+	subdivision, found := ResolveSubdivision(m.Country, m.State)
+	if !found {
+		return false
+	}
+	m.StateLabel = m.State
+	m.State = strings.ToLower(subdivision.Name)
+	m.StateCode = subdivision.Code
+	return true
+}
@@ -0,0 +1,98 @@
+package castle
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestResolveSubdivision(t *testing.T) {
+	testCases := []struct {
+		name         string
+		country      Country
+		text         string
+		expectedCode string
+		expectedName string
+	}{
+		{name: "irish_county_abbreviated", country: Ireland, text: "Co. Kerry", expectedCode: "IE-KY", expectedName: "Kerry"},
+		{name: "irish_county_without_dot", country: Ireland, text: "co meath", expectedCode: "IE-MH", expectedName: "Meath"},
+		{name: "irish_county_in_full", country: Ireland, text: "County Limerick", expectedCode: "IE-LK", expectedName: "Limerick"},
+		{name: "irish_county_named_in_irish", country: Ireland, text: "Ciarraí", expectedCode: "IE-KY", expectedName: "Kerry"},
+		{name: "code", country: Ireland, text: "ie-ky", expectedCode: "IE-KY", expectedName: "Kerry"},
+		{name: "uk_county_among_regions", country: UK, text: "Kent, South East England", expectedCode: "GB-KEN", expectedName: "Kent"},
+		{name: "uk_county_not_in_gazetteer", country: UK, text: "berkshire, greaterlondon", expectedCode: ""},
+		{name: "uk_welsh_name", country: UK, text: "Ynys Môn", expectedCode: "GB-AGY", expectedName: "Isle of Anglesey"},
+		{name: "portuguese_district", country: Portugal, text: "Bragança", expectedCode: "PT-04", expectedName: "Bragança"},
+		{name: "portuguese_district_without_accent", country: Portugal, text: "SANTAREM", expectedCode: "PT-14", expectedName: "Santarém"},
+		{name: "portuguese_autonomous_region", country: Portugal, text: "Açores", expectedCode: "PT-20", expectedName: "Região Autónoma dos Açores"},
+		{name: "slovak_region_seat", country: Slovakia, text: "Nitra", expectedCode: "SK-NI", expectedName: "Nitriansky kraj"},
+		{name: "slovak_region_in_german", country: Slovakia, text: "Pressburg", expectedCode: "SK-BL", expectedName: "Bratislavský kraj"},
+		{name: "slovak_region_without_accents", country: Slovakia, text: "Zilinsky kraj", expectedCode: "SK-ZI", expectedName: "Žilinský kraj"},
+		{name: "danish_old_county", country: Denmark, text: "Lynge-Frederiksborg herred/Frederiksborg amt", expectedCode: "DK-84", expectedName: "Hovedstaden"},
+		{name: "danish_island", country: Denmark, text: "Lolland", expectedCode: "DK-85", expectedName: "Sjælland"},
+		{name: "danish_region_in_german", country: Denmark, text: "Region Süddänemark", expectedCode: "DK-83", expectedName: "Syddanmark"},
+		{name: "other_country", country: Country("fr"), text: "Kerry", expectedCode: ""},
+		{name: "empty", country: Ireland, text: "", expectedCode: ""},
+	}
+
+	for _, tt := range testCases {
+		currentTT := tt
+		t.Run(currentTT.name, func(t *testing.T) {
+			t.Helper()
+			subdivision, found := ResolveSubdivision(currentTT.country, currentTT.text)
+
+			if found != (currentTT.expectedCode != "") {
+				t.Errorf("expected to be resolved [%t], got [%t]", currentTT.expectedCode != "", found)
+			}
+			if subdivision.Code != currentTT.expectedCode {
+				t.Errorf("expected code [%s], got [%s]", currentTT.expectedCode, subdivision.Code)
+			}
+			if subdivision.Name != currentTT.expectedName {
+				t.Errorf("expected name [%s], got [%s]", currentTT.expectedName, subdivision.Name)
+			}
+		})
+	}
+}
+
+func TestNewGazetteerRejectsAmbiguousNames(t *testing.T) {
+	_, err := NewGazetteer(map[Country]CountryGazetteer{
+		Ireland: {Subdivisions: []GazetteerEntry{
+			{Code: "IE-D", Name: "Dublin"},
+			{Code: "IE-MH", Name: "Meath", Aliases: []string{"Dublin"}},
+		}},
+	})
+
+	if !errors.Is(err, ErrInvalidGazetteer) {
+		t.Errorf("expected err [%v], got [%v]", ErrInvalidGazetteer, err)
+	}
+}
+
+func TestResolveStateKeepsTheLabelOfTheSource(t *testing.T) {
+	m := Model{Name: "ross", Country: Ireland, State: "co. kerry"}
+
+	if !m.ResolveState() {
+		t.Fatalf("expected state to be resolved")
+	}
+
+	if m.State != "kerry" {
+		t.Errorf("expected state [kerry], got [%s]", m.State)
+	}
+	if m.StateCode != "IE-KY" {
+		t.Errorf("expected state code [IE-KY], got [%s]", m.StateCode)
+	}
+	if m.StateLabel != "co. kerry" {
+		t.Errorf("expected state label [co. kerry], got [%s]", m.StateLabel)
+	}
+}
+
+func TestIsProbablyComparesResolvedStates(t *testing.T) {
+	kerry := Model{Name: "ross", Country: Ireland, State: "kerry", StateCode: "IE-KY"}
+	cork := Model{Name: "ross", Country: Ireland, State: "cork", StateCode: "IE-CO"}
+	sameKerry := Model{Name: "ross", Country: Ireland, State: "ciarraí", StateCode: "IE-KY"}
+
+	if kerry.IsProbably(cork) {
+		t.Errorf("expected castles of different states not to be the same")
+	}
+	if !kerry.IsProbably(sameKerry) {
+		t.Errorf("expected castles of the same state named differently to be the same")
+	}
+}
@@ -0,0 +1,142 @@
+package castle
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/buarki/find-castles/toascii"
+)
+
+var (
+	nonWordPattern = regexp.MustCompile(`[^\w]+`)
+)
+
+// WebName returns the web name allocated to the castle while it still fits
+// its name, so it stays stable after a castle with the same name shows up.
+// Castles never saved, or renamed since, get the one derived from their name.
+func (m Model) WebName() (string, error) {
+	if m.AllocatedWebName != "" {
+		fits, err := m.webNameFitsName(m.AllocatedWebName)
+		if err != nil {
+			return "", err
+		}
+		if fits {
+			return m.AllocatedWebName, nil
+		}
+	}
+	return m.BaseWebName()
+}
+
+// BaseWebName derives the web name from the name and the country of the
+// castle, ignoring the allocated one.
+func (m Model) BaseWebName() (string, error) {
+	name, err := slugOf(m.Name)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s-%s", name, m.Country), nil
+}
+
+// WebNameCandidates returns, in order of preference, the web names the
+// castle may get: the base one, then disambiguated by its city and by its
+// state. When all of them are taken a numeric suffix is used.
+func (m Model) WebNameCandidates() ([]string, error) {
+	name, err := slugOf(m.Name)
+	if err != nil {
+		return nil, err
+	}
+	var candidates []string
+	seen := make(map[string]bool)
+	for _, place := range []string{"", m.City, m.State} {
+		candidate := name
+		if place != "" {
+			placeSlug, err := slugOf(place)
+			if err != nil {
+				return nil, err
+			}
+			if placeSlug == "" || placeSlug == name {
+				continue
+			}
+			candidate = fmt.Sprintf("%s-%s", name, placeSlug)
+		}
+		candidate = fmt.Sprintf("%s-%s", candidate, m.Country)
+		if !seen[candidate] {
+			seen[candidate] = true
+			candidates = append(candidates, candidate)
+		}
+	}
+	return candidates, nil
+}
+
+// AllocateWebName returns the web name the castle must be saved with. The
+// allocated one is kept until the castle is renamed, otherwise the first of its
+// candidates not taken by another castle is chosen, at last numbering it.
+func (m Model) AllocateWebName(taken func(webName string) bool) (string, error) {
+	if m.AllocatedWebName != "" && !taken(m.AllocatedWebName) {
+		fits, err := m.webNameFitsName(m.AllocatedWebName)
+		if err != nil {
+			return "", err
+		}
+		if fits {
+			return m.AllocatedWebName, nil
+		}
+	}
+	candidates, err := m.WebNameCandidates()
+	if err != nil {
+		return "", err
+	}
+	for _, candidate := range candidates {
+		if !taken(candidate) {
+			return candidate, nil
+		}
+	}
+	name, err := slugOf(m.Name)
+	if err != nil {
+		return "", err
+	}
+	for i := 2; ; i++ {
+		candidate := fmt.Sprintf("%s-%d-%s", name, i, m.Country)
+		if !taken(candidate) {
+			return candidate, nil
+		}
+	}
+}
+
+// WebNamePrefix is how every web name the castle may get starts.
+func (m Model) WebNamePrefix() (string, error) {
+	name, err := m.NameSlug()
+	if err != nil {
+		return "", err
+	}
+	return name + "-", nil
+}
+
+// NameSlug is the name of the castle as it is written on its web names.
+func (m Model) NameSlug() (string, error) {
+	return slugOf(m.Name)
+}
+
+// webNameFitsName tells whether the web name was allocated for the current
+// name of the castle. Places added to it aren't checked, so filling or
+// resolving the city or the state later doesn't move it. Web names saved
+// before the name they were allocated for was kept only need to start with
+// the current one.
+func (m Model) webNameFitsName(webName string) (bool, error) {
+	name, err := m.NameSlug()
+	if err != nil {
+		return false, err
+	}
+	if m.WebNameAllocatedFor != "" && m.WebNameAllocatedFor != name {
+		return false, nil
+	}
+	return strings.HasPrefix(webName, name+"-") && strings.HasSuffix(webName, "-"+m.Country.String()), nil
+}
+
+func slugOf(s string) (string, error) {
+	asciiStr, err := toascii.From(s)
+	if err != nil {
+		return "", err
+	}
+	return strings.ToLower(nonWordPattern.ReplaceAllString(asciiStr, "-")), nil
+}
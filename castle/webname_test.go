@@ -0,0 +1,123 @@
+package castle
+
+import (
+	"testing"
+)
+
+func TestAllocateWebName(t *testing.T) {
+	castelo := Model{Name: "castelo velho", Country: Portugal, City: "Alcoutim", State: "Faro"}
+
+	withAllocated := func(c Model, webName string) Model {
+		c.AllocatedWebName = webName
+		return c
+	}
+	renamed := castelo
+	renamed.Name = "castelo novo"
+
+	testCases := []struct {
+		name     string
+		c        Model
+		taken    []string
+		expected string
+	}{
+		{
+			name:     "base_web_name_free",
+			c:        castelo,
+			expected: "castelo-velho-pt",
+		},
+		{
+			name:     "disambiguated_by_city",
+			c:        castelo,
+			taken:    []string{"castelo-velho-pt"},
+			expected: "castelo-velho-alcoutim-pt",
+		},
+		{
+			name:     "disambiguated_by_state",
+			c:        castelo,
+			taken:    []string{"castelo-velho-pt", "castelo-velho-alcoutim-pt"},
+			expected: "castelo-velho-faro-pt",
+		},
+		{
+			name:     "numbered",
+			c:        castelo,
+			taken:    []string{"castelo-velho-pt", "castelo-velho-alcoutim-pt", "castelo-velho-faro-pt", "castelo-velho-2-pt"},
+			expected: "castelo-velho-3-pt",
+		},
+		{
+			name:     "numbered_without_places",
+			c:        Model{Name: "castelo velho", Country: Portugal},
+			taken:    []string{"castelo-velho-pt"},
+			expected: "castelo-velho-2-pt",
+		},
+		{
+			name:     "allocated_kept",
+			c:        withAllocated(castelo, "castelo-velho-alcoutim-pt"),
+			expected: "castelo-velho-alcoutim-pt",
+		},
+		{
+			name:     "reallocated_after_rename",
+			c:        withAllocated(renamed, "castelo-velho-alcoutim-pt"),
+			expected: "castelo-novo-pt",
+		},
+		{
+			name:     "numbered_kept",
+			c:        withAllocated(castelo, "castelo-velho-3-pt"),
+			expected: "castelo-velho-3-pt",
+		},
+		{
+			name:     "reallocated_after_rename_to_a_prefix",
+			c:        withAllocated(Model{Name: "castelo", Country: Portugal, WebNameAllocatedFor: "castelo-velho"}, "castelo-velho-pt"),
+			expected: "castelo-pt",
+		},
+		{
+			name:     "kept_after_moving",
+			c:        withAllocated(Model{Name: "castelo velho", Country: Portugal, City: "Mértola"}, "castelo-velho-alcoutim-pt"),
+			expected: "castelo-velho-alcoutim-pt",
+		},
+	}
+
+	for _, tt := range testCases {
+		currentTT := tt
+		t.Run(currentTT.name, func(t *testing.T) {
+			t.Helper()
+			taken := make(map[string]bool)
+			for _, webName := range currentTT.taken {
+				taken[webName] = true
+			}
+			received, err := currentTT.c.AllocateWebName(func(webName string) bool { return taken[webName] })
+			if err != nil {
+				t.Errorf("expected err nil, got %v", err)
+			}
+			if received != currentTT.expected {
+				t.Errorf("expected [%s], got [%s]", currentTT.expected, received)
+			}
+		})
+	}
+}
+
+func TestWebNameUsesAllocatedWhileItFitsName(t *testing.T) {
+	c := Model{Name: "castelo velho", Country: Portugal, City: "Alcoutim", AllocatedWebName: "castelo-velho-alcoutim-pt"}
+
+	if received, _ := c.WebName(); received != "castelo-velho-alcoutim-pt" {
+		t.Errorf("expected allocated web name, got [%s]", received)
+	}
+
+	c.Name = "castelo novo"
+	if received, _ := c.WebName(); received != "castelo-novo-pt" {
+		t.Errorf("expected web name of the new name, got [%s]", received)
+	}
+}
+
+func TestMergeWithKeepsAllocatedWebName(t *testing.T) {
+	scraped := Model{Name: "castelo velho", Country: Portugal, Sources: []string{"https://example.com/castelo-velho"}}
+	saved := Model{ID: "a1", Name: "castelo velho", Country: Portugal, City: "Alcoutim", AllocatedWebName: "castelo-velho-alcoutim-pt"}
+
+	merged := scraped.MergeWith(saved)
+
+	if merged.AllocatedWebName != saved.AllocatedWebName {
+		t.Errorf("expected [%s], got [%s]", saved.AllocatedWebName, merged.AllocatedWebName)
+	}
+	if len(merged.WebNameAliases) > 0 {
+		t.Errorf("expected no aliases while the web name is kept, got %v", merged.WebNameAliases)
+	}
+}
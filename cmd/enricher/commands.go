@@ -0,0 +1,65 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/buarki/find-castles/db"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// commands besides the enrichment itself, which runs when no command is given
+var commands = map[string]func(args []string) error{
+	"diff":     diffCommand,
+	"export":   exportCommand,
+	"import":   importCommand,
+	"merge":    mergeCommand,
+	"migrate":  migrateCommand,
+	"override": overrideCommand,
+	"split":    splitCommand,
+}
+
+func runCommand(name string, args []string) error {
+	command, found := commands[name]
+	if !found {
+		available := make([]string, 0, len(commands))
+		for c := range commands {
+			available = append(available, c)
+		}
+		slices.Sort(available)
+		return fmt.Errorf("unknown command [%s], available ones are [%s]", name, strings.Join(available, ", "))
+	}
+	return command(args)
+}
+
+func connectToDB(ctx context.Context) (*mongo.Client, *mongo.Database, error) {
+	mongoURI := os.Getenv("DB_URI")
+	if mongoURI == "" {
+		return nil, nil, errors.New("missing env var DB_URI")
+	}
+	dbClient, err := db.NewClient(ctx, mongoURI)
+	if err != nil {
+		return nil, nil, err
+	}
+	return dbClient, dbClient.Database(databaseName), nil
+}
+
+// ensureNoPendingMigrations fails when saved castles aren't on the current
+// schema. A database without castles, like a fresh one, needs no migration.
+func ensureNoPendingMigrations(ctx context.Context, database *mongo.Database) error {
+	if _, err := db.RecordMigrationsOfEmptyCastles(ctx, database.Collection(collectionName), database.Collection(migrationsCollectionName)); err != nil {
+		return err
+	}
+	pending, err := db.PendingMigrations(ctx, database.Collection(migrationsCollectionName))
+	if err != nil {
+		return err
+	}
+	if len(pending) > 0 {
+		return fmt.Errorf("there are %d pending migrations, run the migrate command first", len(pending))
+	}
+	return nil
+}
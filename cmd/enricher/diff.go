@@ -0,0 +1,113 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/buarki/find-castles/castle"
+	"github.com/buarki/find-castles/db"
+	"github.com/buarki/find-castles/diffreport"
+	"github.com/buarki/find-castles/fileloader"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+const (
+	commandTimeout = 5 * time.Minute
+
+	dbSnapshot        = "db"
+	runSnapshotPrefix = "run:"
+)
+
+func diffCommand(args []string) error {
+	flags := flag.NewFlagSet("diff", flag.ExitOnError)
+	oldSnapshot := flags.String("old", "", "snapshot to compare from: db, run:<run ID> or a path to a JSON file")
+	newSnapshot := flags.String("new", dbSnapshot, "snapshot to compare to: db, run:<run ID> or a path to a JSON file")
+	format := flags.String("format", string(diffreport.Markdown), "report format: markdown or json")
+	output := flags.String("output", "", "file to write the report to, defaults to stdout")
+	flags.Parse(args)
+
+	if *oldSnapshot == "" {
+		return fmt.Errorf("missing flag -old")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), commandTimeout)
+	defer cancel()
+
+	loader := &snapshotLoader{}
+	defer loader.close()
+
+	oldCastles, err := loader.load(ctx, *oldSnapshot)
+	if err != nil {
+		return err
+	}
+	newCastles, err := loader.load(ctx, *newSnapshot)
+	if err != nil {
+		return err
+	}
+
+	report, err := diffreport.Compare(*oldSnapshot, oldCastles, *newSnapshot, newCastles)
+	if err != nil {
+		return err
+	}
+
+	var w io.Writer = os.Stdout
+	if *output != "" {
+		f, err := os.Create(*output)
+		if err != nil {
+			return fmt.Errorf("failed to create report file [%s], got %v", *output, err)
+		}
+		defer f.Close()
+		w = f
+	}
+	return diffreport.Write(w, report, diffreport.Format(*format))
+}
+
+// snapshotLoader connects to the DB only if some snapshot needs it.
+type snapshotLoader struct {
+	dbClient *mongo.Client
+	database *mongo.Database
+}
+
+func (l *snapshotLoader) load(ctx context.Context, snapshot string) ([]castle.Model, error) {
+	switch {
+	case snapshot == dbSnapshot:
+		database, err := l.db(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return db.ListCastles(ctx, database.Collection(collectionName))
+	case strings.HasPrefix(snapshot, runSnapshotPrefix):
+		database, err := l.db(ctx)
+		if err != nil {
+			return nil, err
+		}
+		runID := strings.TrimPrefix(snapshot, runSnapshotPrefix)
+		return db.LoadSnapshot(ctx, database.Collection(snapshotsCollectionName), runID)
+	default:
+		return fileloader.LoadCastlesAsJSONList(snapshot)
+	}
+}
+
+func (l *snapshotLoader) db(ctx context.Context) (*mongo.Database, error) {
+	if l.database != nil {
+		return l.database, nil
+	}
+	dbClient, database, err := connectToDB(ctx)
+	if err != nil {
+		return nil, err
+	}
+	l.dbClient = dbClient
+	l.database = database
+	return database, nil
+}
+
+func (l *snapshotLoader) close() {
+	if l.dbClient != nil {
+		l.dbClient.Disconnect(context.Background())
+	}
+}
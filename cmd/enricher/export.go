@@ -0,0 +1,54 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/buarki/find-castles/castle"
+	"github.com/buarki/find-castles/db"
+	"github.com/buarki/find-castles/export"
+)
+
+func exportCommand(args []string) error {
+	flags := flag.NewFlagSet("export", flag.ExitOnError)
+	format := flags.String("format", string(export.GeoJSON), "export format: geojson, kml, gpx, csv or jsonl")
+	countries := flags.String("country", "", "comma separated country codes to export, defaults to all")
+	output := flags.String("output", "", "file to write the castles to, defaults to stdout")
+	flags.Parse(args)
+
+	var query castle.Query
+	for _, country := range strings.Split(*countries, ",") {
+		if country = strings.TrimSpace(country); country != "" {
+			query.Countries = append(query.Countries, castle.Country(strings.ToLower(country)))
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), commandTimeout)
+	defer cancel()
+
+	dbClient, database, err := connectToDB(ctx)
+	if err != nil {
+		return err
+	}
+	defer dbClient.Disconnect(context.Background())
+
+	castles, err := db.FindCastles(ctx, database.Collection(collectionName), query, castle.SortByWebName, "", 0)
+	if err != nil {
+		return err
+	}
+
+	var w io.Writer = os.Stdout
+	if *output != "" {
+		f, err := os.Create(*output)
+		if err != nil {
+			return fmt.Errorf("failed to create export file [%s], got %v", *output, err)
+		}
+		defer f.Close()
+		w = f
+	}
+	return export.Write(w, castles, export.Format(*format))
+}
@@ -0,0 +1,173 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"time"
+
+	"github.com/buarki/find-castles/castle"
+	"github.com/buarki/find-castles/db"
+)
+
+// mergeCommand merges a castle into another one, asserting the sources of the
+// merged castle are the same as the one it was merged into so next runs keep
+// them together. Every step before deleting the merged castle can be done
+// again, so running the command again completes a merge that failed midway.
+func mergeCommand(args []string) error {
+	flags := flag.NewFlagSet("merge", flag.ExitOnError)
+	intoID := flags.String("into", "", "ID of the castle to keep")
+	fromID := flags.String("from", "", "ID of the castle merged into the kept one, which is deleted")
+	author := flags.String("author", "", "who is merging the castles")
+	reason := flags.String("reason", "", "why they are the same castle")
+	flags.Parse(args)
+
+	if *intoID == "" || *fromID == "" {
+		return fmt.Errorf("missing flags -into and -from")
+	}
+	if *author == "" || *reason == "" {
+		return fmt.Errorf("missing flags -author and -reason")
+	}
+	if *intoID == *fromID {
+		return fmt.Errorf("cannot merge castle [%s] into itself", *intoID)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), commandTimeout)
+	defer cancel()
+
+	dbClient, database, err := connectToDB(ctx)
+	if err != nil {
+		return err
+	}
+	defer dbClient.Disconnect(context.Background())
+
+	if err := ensureNoPendingMigrations(ctx, database); err != nil {
+		return err
+	}
+	collections := castlesCollectionsOf(database)
+	if err := collections.addIndexes(ctx); err != nil {
+		return err
+	}
+
+	into, err := db.GetCastleByID(ctx, collections.castles, *intoID)
+	if err != nil {
+		return fmt.Errorf("failed to find castle [%s], got %v", *intoID, err)
+	}
+	from, err := db.GetCastleByID(ctx, collections.castles, *fromID)
+	if errors.Is(err, db.ErrCastleNotFound) {
+		return fmt.Errorf("failed to find castle [%s], it is deleted as the last step of merging it, got %v", *fromID, err)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to find castle [%s], got %v", *fromID, err)
+	}
+
+	now := time.Now().UTC()
+	for _, source := range from.Sources {
+		err := db.SaveIdentityAssertion(ctx, collections.identities, castle.IdentityAssertion{
+			CastleID:  into.ID,
+			Source:    source,
+			Kind:      castle.SameAs,
+			Author:    *author,
+			Reason:    *reason,
+			CreatedAt: now,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	if err := db.MoveOverrides(ctx, collections.overrides, from.ID, into.ID); err != nil {
+		return err
+	}
+	merged, err := applyOverrides(ctx, collections.overrides, []castle.Model{into.MergeWith(from)})
+	if err != nil {
+		return err
+	}
+	if _, err := db.SaveCastles(ctx, collections.castles, merged); err != nil {
+		return err
+	}
+	// only once everything else was done, as the merged castle is how running
+	// the command again finds what is left to do
+	if err := db.DeleteCastle(ctx, collections.castles, from.ID); err != nil {
+		return err
+	}
+	fmt.Printf("castle [%s] merged into castle [%s]\n", from.ID, into.ID)
+	return nil
+}
+
+// splitCommand splits a castle into one castle per source, asserting each
+// source is its own castle so next runs don't merge them again. Split castles
+// start as copies of the original one and get their own data on the next run.
+func splitCommand(args []string) error {
+	flags := flag.NewFlagSet("split", flag.ExitOnError)
+	id := flags.String("castle", "", "ID of the castle to split, its first source is kept on it")
+	author := flags.String("author", "", "who is splitting the castle")
+	reason := flags.String("reason", "", "why its sources are different castles")
+	flags.Parse(args)
+
+	if *id == "" {
+		return fmt.Errorf("missing flag -castle")
+	}
+	if *author == "" || *reason == "" {
+		return fmt.Errorf("missing flags -author and -reason")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), commandTimeout)
+	defer cancel()
+
+	dbClient, database, err := connectToDB(ctx)
+	if err != nil {
+		return err
+	}
+	defer dbClient.Disconnect(context.Background())
+
+	if err := ensureNoPendingMigrations(ctx, database); err != nil {
+		return err
+	}
+	collections := castlesCollectionsOf(database)
+	if err := collections.addIndexes(ctx); err != nil {
+		return err
+	}
+
+	original, err := db.GetCastleByID(ctx, collections.castles, *id)
+	if err != nil {
+		return fmt.Errorf("failed to find castle [%s], got %v", *id, err)
+	}
+	if len(original.Sources) < 2 {
+		return fmt.Errorf("castle [%s] has a single source, there is nothing to split", original.ID)
+	}
+
+	now := time.Now().UTC()
+	castlesToSave := []castle.Model{original}
+	for _, source := range original.Sources[1:] {
+		splitID, err := castle.NewID()
+		if err != nil {
+			return err
+		}
+		assertions := []castle.IdentityAssertion{
+			{CastleID: splitID, Source: source, Kind: castle.SameAs},
+			{CastleID: original.ID, Source: source, Kind: castle.NotSameAs},
+		}
+		for _, assertion := range assertions {
+			assertion.Author, assertion.Reason, assertion.CreatedAt = *author, *reason, now
+			if err := db.SaveIdentityAssertion(ctx, collections.identities, assertion); err != nil {
+				return err
+			}
+		}
+
+		split := original.Copy()
+		split.ID = splitID
+		split.Sources = []string{source}
+		// old links keep leading to the original castle, which keeps its web name
+		split.WebNameAliases = nil
+		split.AllocatedWebName = ""
+		castlesToSave = append(castlesToSave, split)
+		fmt.Printf("source [%s] split into castle [%s]\n", source, splitID)
+	}
+	castlesToSave[0].Sources = original.Sources[:1]
+
+	if _, err := db.SaveCastles(ctx, collections.castles, castlesToSave); err != nil {
+		return err
+	}
+	return nil
+}
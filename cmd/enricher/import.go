@@ -0,0 +1,107 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/buarki/find-castles/castle"
+	"github.com/buarki/find-castles/dataset"
+	"github.com/buarki/find-castles/run"
+)
+
+const (
+	importSource = "import"
+)
+
+// importCommand saves castles from a dataset file going through the same
+// reconciliation of the enrichment, so imported castles are merged with the
+// ones already saved.
+func importCommand(args []string) error {
+	flags := flag.NewFlagSet("import", flag.ExitOnError)
+	format := flags.String("format", string(dataset.JSONL), "dataset format: jsonl or csv")
+	input := flags.String("input", "", "file to read the castles from, defaults to stdin")
+	flags.Parse(args)
+
+	var r io.Reader = os.Stdin
+	if *input != "" {
+		f, err := os.Open(*input)
+		if err != nil {
+			return fmt.Errorf("failed to open dataset file [%s], got %v", *input, err)
+		}
+		defer f.Close()
+		r = f
+	}
+	reader, err := dataset.NewReader(r, dataset.Format(*format))
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), commandTimeout)
+	defer cancel()
+
+	dbClient, database, err := connectToDB(ctx)
+	if err != nil {
+		return err
+	}
+	defer dbClient.Disconnect(context.Background())
+
+	if err := ensureNoPendingMigrations(ctx, database); err != nil {
+		return err
+	}
+	collections := castlesCollectionsOf(database)
+	if err := collections.addIndexes(ctx); err != nil {
+		return err
+	}
+
+	startedAt := time.Now().UTC()
+	recorder := run.NewRecorder(importSource, startedAt, run.Config{
+		BufferSize: bufferSize,
+		Sources:    []string{importSource},
+	})
+
+	read := 0
+	buffer := make([]castle.Model, 0, bufferSize)
+	for {
+		c, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		read++
+		if c.Name == "" || c.Country == "" {
+			return fmt.Errorf("castle number %d has no name or country, both are mandatory", read)
+		}
+		c.CleanFields()
+		recorder.CastleEnriched(importSource)
+		if !c.ResolveState() {
+			recorder.StateUnresolved(importSource, c.State)
+		}
+		validateCoordinates(&c, importSource, recorder)
+		buffer = append(buffer, c)
+		if len(buffer) == bufferSize {
+			if err := processBuffer(ctx, collections, buffer, recorder); err != nil {
+				return err
+			}
+			buffer = buffer[:0]
+		}
+	}
+	if len(buffer) > 0 {
+		if err := processBuffer(ctx, collections, buffer, recorder); err != nil {
+			return err
+		}
+	}
+
+	record := recorder.Finish(time.Now().UTC(), false, nil)
+	fmt.Printf("imported %d castles: %d inserted, %d updated, %d unchanged, %d merged with saved ones, %d geocoded\n",
+		read, record.Inserted, record.Updated, record.Unchanged, record.Merges, record.Geocoded)
+	for _, invalid := range record.Sources[importSource].InvalidCoordinates {
+		fmt.Printf("coordinates to check: %s\n", invalid)
+	}
+	return nil
+}
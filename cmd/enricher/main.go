@@ -3,10 +3,12 @@ package main
 import (
 	"context"
 	"errors"
+	"fmt"
 	"log"
 	"log/slog"
 	"os"
 	"runtime"
+	"slices"
 	"strconv"
 	"time"
 
@@ -14,24 +16,40 @@ import (
 	"github.com/buarki/find-castles/db"
 	"github.com/buarki/find-castles/enricher"
 	"github.com/buarki/find-castles/executor"
+	"github.com/buarki/find-castles/geocoding"
 	"github.com/buarki/find-castles/htmlfetcher"
 	"github.com/buarki/find-castles/httpclient"
+	"github.com/buarki/find-castles/run"
 	"go.mongodb.org/mongo-driver/mongo"
 )
 
 const (
-	databaseName   = "find-castles"
-	collectionName = "castles"
-	bufferSize     = 10
+	databaseName       = "find-castles"
+	collectionName     = "castles"
+	runsCollectionName = "runs"
+	// copies of the castles collection taken at the end of each run
+	snapshotsCollectionName  = "snapshots"
+	migrationsCollectionName = "migrations"
+	overridesCollectionName  = "overrides"
+	identitiesCollectionName = "identities"
+	bufferSize               = 10
+	// the run record must be saved even when the enrichment timed out
+	runSavingTimeout = 30 * time.Second
 )
 
 func main() {
-	mongoURI := os.Getenv("DB_URI")
-	if mongoURI == "" {
-		log.Fatal("missing env var DB_URI")
+	if len(os.Args) > 1 {
+		if err := runCommand(os.Args[1], os.Args[2:]); err != nil {
+			log.Fatal(err)
+		}
+		return
 	}
+	runEnrichment()
+}
+
+func runEnrichment() {
 	operationTimeoutInSeconds := os.Getenv("ENRICHMENT_TIMEOUT_IN_SECONDS")
-	if mongoURI == "" {
+	if operationTimeoutInSeconds == "" {
 		log.Fatal("missing env var ENRICHMENT_TIMEOUT_IN_SECONDS")
 	}
 	timeoutAsNumber, err := strconv.ParseInt(operationTimeoutInSeconds, 10, 0)
@@ -42,15 +60,29 @@ func main() {
 	ctx, cancel := context.WithTimeout(context.Background(), connectionTimeout)
 	defer cancel()
 
-	dbClient, err := db.NewClient(ctx, mongoURI)
+	dbClient, database, err := connectToDB(ctx)
 	if err != nil {
 		log.Fatal(err)
 	}
-	defer dbClient.Disconnect(ctx)
+	defer dbClient.Disconnect(context.Background())
 
-	collection := dbClient.Database(databaseName).Collection(collectionName)
+	collections := castlesCollectionsOf(database)
+	runsCollection := database.Collection(runsCollectionName)
+	snapshotsCollection := database.Collection(snapshotsCollectionName)
 
-	if err := db.AddIndexes(ctx, collection); err != nil {
+	// castles would be saved in a shape older ones don't have yet, and
+	// indexes may only be valid for castles already migrated
+	if err := ensureNoPendingMigrations(ctx, database); err != nil {
+		log.Fatal(err)
+	}
+
+	if err := collections.addIndexes(ctx); err != nil {
+		log.Fatal(err)
+	}
+	if err := db.AddRunsIndexes(ctx, runsCollection); err != nil {
+		log.Fatal(err)
+	}
+	if err := db.AddSnapshotsIndexes(ctx, snapshotsCollection); err != nil {
 		log.Fatal(err)
 	}
 
@@ -62,83 +94,283 @@ func main() {
 		enricher.MedievalBritain:    enricher.NewMedievalBritainEnricher(httpClient, htmlfetcher.Fetch),
 	}
 	cpus := runtime.NumCPU()
-	castlesEnricher := executor.New(int(float64(cpus)*0.3), int(float64(cpus)*0.7), httpClient, enrichers)
+	collectingCPUs, extractingCPUs := int(float64(cpus)*0.3), int(float64(cpus)*0.7)
+
+	startedAt := time.Now().UTC()
+	runID, err := run.NewID(startedAt)
+	if err != nil {
+		log.Fatal(err)
+	}
+	recorder := run.NewRecorder(runID, startedAt, run.Config{
+		TimeoutInSeconds:  int(timeoutAsNumber),
+		BufferSize:        bufferSize,
+		CollectingWorkers: collectingCPUs,
+		ExtractingWorkers: extractingCPUs,
+		Sources:           sourcesOf(enrichers),
+	})
+	slog.Info("starting enrichment run", "runID", runID)
+
+	castlesEnricher := executor.New(collectingCPUs, extractingCPUs, httpClient, enrichers)
+	enrichmentErr := enrich(ctx, collections, castlesEnricher, recorder)
+
+	timedOut := errors.Is(ctx.Err(), context.DeadlineExceeded)
+	record := recorder.Finish(time.Now().UTC(), timedOut, enrichmentErr)
+
+	savingCtx, cancelSaving := context.WithTimeout(context.Background(), runSavingTimeout)
+	defer cancelSaving()
+	// runs that timed out or failed left castles behind, so their snapshot
+	// would tell they were removed
+	if enrichmentErr == nil {
+		// allows diffing what changed between runs
+		if err := db.SaveSnapshot(savingCtx, collections.castles, snapshotsCollection, record.ID); err != nil {
+			slog.Error("failed to save snapshot of run", "runID", record.ID, "error", err)
+		}
+	}
+	if err := db.SaveRun(savingCtx, runsCollection, record); err != nil {
+		log.Fatal(err)
+	}
+	slog.Info("enrichment run finished", "runID", record.ID, "status", record.Status, "duration", record.Duration())
+	for source, stats := range record.Sources {
+		if len(stats.UnresolvedStates) > 0 {
+			slog.Warn("states not resolved into subdivisions", "runID", record.ID, "source", source, "states", stats.UnresolvedStates)
+		}
+		if len(stats.InvalidCoordinates) > 0 {
+			slog.Warn("castles with coordinates outside their country", "runID", record.ID, "source", source, "castles", stats.InvalidCoordinates)
+		}
+	}
+
+	if enrichmentErr != nil {
+		log.Fatal(enrichmentErr)
+	}
+}
+
+func enrich(ctx context.Context, collections castlesCollections, castlesEnricher *executor.EnchimentExecutor, recorder *run.Recorder) error {
 	castlesChan, errChan := castlesEnricher.Enrich(ctx)
 
 	checkingCastlesBuffer := make([]castle.Model, 0, bufferSize)
 
+	// source links seen on this run, including the ones that failed on enrichment,
+	// as they were still listed by their source
+	seenSources := make(map[string]struct{})
+
 	for {
 		select {
 		case <-ctx.Done():
-			return
+			return ctx.Err()
 		case castle, ok := <-castlesChan:
 			if !ok {
 				if len(checkingCastlesBuffer) > 0 {
-					if err := processBuffer(ctx, collection, checkingCastlesBuffer); err != nil {
-						log.Fatal(err)
+					if err := processBuffer(ctx, collections, checkingCastlesBuffer, recorder); err != nil {
+						return err
+					}
+				}
+				// a run is successful only when every source managed to list its castles
+				if recorder.Successful() {
+					if err := trackUnseenCastles(ctx, collections.castles, seenSources); err != nil {
+						return err
 					}
+				} else {
+					slog.Warn("skipping tracking of unseen castles", "reason", "not all sources were collected")
 				}
-				return
+				return nil
+			}
+			recorder.CastleEnriched(castle.CurrentEnrichmentSource)
+			if !castle.ResolveState() {
+				recorder.StateUnresolved(castle.CurrentEnrichmentSource, castle.State)
+			}
+			validateCoordinates(&castle, castle.CurrentEnrichmentSource, recorder)
+			seenSources[castle.CurrentEnrichmentLink] = struct{}{}
+			for _, source := range castle.Sources {
+				seenSources[source] = struct{}{}
 			}
 			checkingCastlesBuffer = append(checkingCastlesBuffer, castle)
 			if len(checkingCastlesBuffer) == bufferSize {
-				if err := processBuffer(ctx, collection, checkingCastlesBuffer); err != nil {
-					log.Fatal(err)
+				if err := processBuffer(ctx, collections, checkingCastlesBuffer, recorder); err != nil {
+					return err
 				}
 				checkingCastlesBuffer = checkingCastlesBuffer[:0]
 			}
 		case err := <-errChan:
 			if err != nil {
 				log.Printf("error enriching castles: %v", err)
+				var enrichmentErr *executor.EnrichmentError
+				if !errors.As(err, &enrichmentErr) {
+					recorder.CollectionFailed("unknown", err)
+				} else if enrichmentErr.IsCollectionFailure() {
+					recorder.CollectionFailed(enrichmentErr.Source.String(), enrichmentErr.Err)
+				} else {
+					recorder.CastleFailed(enrichmentErr.Source.String(), err)
+					seenSources[enrichmentErr.Link] = struct{}{}
+				}
 			}
 		}
 	}
 }
 
-func processBuffer(ctx context.Context, collection *mongo.Collection, buffer []castle.Model) error {
+// castlesCollections are the ones castles are saved through.
+type castlesCollections struct {
+	castles    *mongo.Collection
+	overrides  *mongo.Collection
+	identities *mongo.Collection
+}
+
+func castlesCollectionsOf(database *mongo.Database) castlesCollections {
+	return castlesCollections{
+		castles:    database.Collection(collectionName),
+		overrides:  database.Collection(overridesCollectionName),
+		identities: database.Collection(identitiesCollectionName),
+	}
+}
+
+func (c castlesCollections) addIndexes(ctx context.Context) error {
+	if err := db.AddIndexes(ctx, c.castles); err != nil {
+		return err
+	}
+	if err := c.addOverridesIndexes(ctx); err != nil {
+		return err
+	}
+	return db.AddIdentitiesIndexes(ctx, c.identities)
+}
+
+// addOverridesIndexes first assigns the overrides saved by web name to the
+// IDs of their castles, which they are kept by now.
+func (c castlesCollections) addOverridesIndexes(ctx context.Context) error {
+	assigned, err := db.AssignOverridesToCastles(ctx, c.castles, c.overrides)
+	if err != nil {
+		return err
+	}
+	if assigned > 0 {
+		slog.Info("assigned overrides to the IDs of their castles", "overrides", assigned)
+	}
+	return db.AddOverridesIndexes(ctx, c.overrides)
+}
+
+func sourcesOf(enrichers map[enricher.Source]enricher.Enricher) []string {
+	sources := make([]string, 0, len(enrichers))
+	for source := range enrichers {
+		sources = append(sources, source.String())
+	}
+	slices.Sort(sources)
+	return sources
+}
+
+func trackUnseenCastles(ctx context.Context, collection *mongo.Collection, seenSources map[string]struct{}) error {
+	sources := make([]string, 0, len(seenSources))
+	for source := range seenSources {
+		sources = append(sources, source)
+	}
+	return db.TrackUnseenCastles(ctx, collection, sources, db.DefaultTombstonePolicy)
+}
+
+func processBuffer(ctx context.Context, collections castlesCollections, buffer []castle.Model, recorder *run.Recorder) error {
 	if len(buffer) == 0 {
 		return errors.New("cannot process empty buffer")
 	}
 
-	similarCastlesFound, err := db.TryToFindCastles(ctx, collection, buffer)
+	identities, err := db.LoadIdentities(ctx, collections.identities, buffer)
 	if err != nil {
 		return err
 	}
 
-	castlesToSave, err := reconcileCastles(buffer, similarCastlesFound)
+	similarCastlesFound, err := db.TryToFindCastles(ctx, collections.castles, buffer, identities)
 	if err != nil {
 		return err
 	}
 
-	if err := db.SaveCastles(ctx, collection, castlesToSave); err != nil {
+	castlesToSave, merges := reconcileCastles(buffer, similarCastlesFound, identities)
+	recorder.CastlesMerged(merges)
+
+	castlesToSave, geocoded := fillByGeocoding(castlesToSave)
+	recorder.CastlesGeocoded(geocoded)
+
+	castlesToSave, err = applyOverrides(ctx, collections.overrides, castlesToSave)
+	if err != nil {
 		return err
 	}
 
+	result, err := db.SaveCastles(ctx, collections.castles, castlesToSave)
+	if err != nil {
+		return err
+	}
+	recorder.CastlesSaved(result.Inserted, result.Updated, result.Unchanged)
+
 	return nil
 }
 
-func reconcileCastles(newCastles, similarCastles []castle.Model) ([]castle.Model, error) {
+// applyOverrides pins the values curators set, so they always win over scraped
+// ones. Overrides are kept by castle ID, so castles without one, never saved
+// before, have none.
+func applyOverrides(ctx context.Context, overridesCollection *mongo.Collection, castles []castle.Model) ([]castle.Model, error) {
+	castleIDs := make([]string, 0, len(castles))
+	for _, c := range castles {
+		if c.ID != "" {
+			castleIDs = append(castleIDs, c.ID)
+		}
+	}
+	if len(castleIDs) == 0 {
+		return castles, nil
+	}
+	overrides, err := db.FindOverrides(ctx, overridesCollection, castleIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]castle.Model, len(castles))
+	for i, c := range castles {
+		overridden, err := c.ApplyOverrides(overrides[c.ID])
+		if err != nil {
+			return nil, fmt.Errorf("failed to apply overrides to castle [%s], got %v", c.ID, err)
+		}
+		result[i] = overridden
+	}
+	return result, nil
+}
+
+// validateCoordinates corrects the coordinates of the castle when they were
+// swapped or had a sign flipped, and records the ones still wrong.
+func validateCoordinates(c *castle.Model, source string, recorder *run.Recorder) {
+	coordinates := c.Coordinates
+	switch check := c.ValidateCoordinates(); {
+	case check == castle.CoordinatesCorrected:
+		slog.Info("corrected coordinates of castle", "castle", c.Name, "from", coordinates, "to", c.Coordinates)
+		recorder.CoordinatesCorrected(source)
+	case check.IsProblem():
+		recorder.CoordinatesInvalid(source, fmt.Sprintf("%s [%s] %s", c.Name, coordinates, check))
+	}
+}
+
+// fillByGeocoding fills the states, cities and coordinates the merged
+// castles still miss, so values any source told are never replaced.
+func fillByGeocoding(castles []castle.Model) ([]castle.Model, int) {
+	result := make([]castle.Model, len(castles))
+	geocoded := 0
+	for i, c := range castles {
+		filled, ok := geocoding.Fill(c)
+		if ok {
+			geocoded++
+		}
+		result[i] = filled
+	}
+	return result, geocoded
+}
+
+// reconcileCastles merges the new castles with the saved ones they are,
+// respecting the identities asserted by curators.
+func reconcileCastles(newCastles, similarCastles []castle.Model, identities castle.Identities) ([]castle.Model, int) {
 	var result []castle.Model
+	merges := 0
 
-	// O(nˆ2), can we improve it?
+	candidates := castle.NewCandidateIndex(similarCastles)
 	for _, newCastle := range newCastles {
-		found := false
-		for _, existingCastle := range similarCastles {
-			if newCastle.IsProbably(existingCastle) {
-				slog.Info("found similar castle", "current castle name", newCastle.Name, "found castle name", existingCastle.Name)
-				reconciliatedCastle, err := newCastle.ReconcileWith(existingCastle)
-				if err != nil {
-					return nil, err
-				}
-				result = append(result, reconciliatedCastle)
-				found = true
-				break
-			}
-		}
+		existingCastle, found := candidates.Resolve(newCastle, identities)
 		if !found {
 			result = append(result, newCastle)
+			continue
 		}
+		slog.Info("found similar castle", "current castle name", newCastle.Name, "found castle name", existingCastle.Name)
+		result = append(result, newCastle.MergeWith(existingCastle))
+		merges++
 	}
 
-	return result, nil
+	return result, merges
 }
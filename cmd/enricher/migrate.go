@@ -0,0 +1,53 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+
+	"github.com/buarki/find-castles/db"
+)
+
+func migrateCommand(args []string) error {
+	flags := flag.NewFlagSet("migrate", flag.ExitOnError)
+	dryRun := flags.Bool("dry-run", false, "only report what each pending migration would change")
+	recompute := flags.Bool("recompute", false, "run the recompute migrations again on every castle, after the pending ones")
+	flags.Parse(args)
+
+	ctx, cancel := context.WithTimeout(context.Background(), commandTimeout)
+	defer cancel()
+
+	dbClient, database, err := connectToDB(ctx)
+	if err != nil {
+		return err
+	}
+	defer dbClient.Disconnect(context.Background())
+
+	results, err := db.Migrate(ctx, database.Collection(collectionName), database.Collection(migrationsCollectionName), *dryRun)
+	for _, r := range results {
+		if r.DryRun {
+			fmt.Printf("[dry run] migration %d (%s) would affect %d documents\n", r.Version, r.Description, r.Affected)
+		} else {
+			fmt.Printf("migration %d (%s) affected %d documents\n", r.Version, r.Description, r.Affected)
+		}
+	}
+	if err != nil {
+		return err
+	}
+	if len(results) == 0 {
+		fmt.Println("no pending migrations")
+	}
+	if !*recompute {
+		return nil
+	}
+
+	recomputed, err := db.Recompute(ctx, database.Collection(collectionName), *dryRun)
+	for _, r := range recomputed {
+		if r.DryRun {
+			fmt.Printf("[dry run] recomputing migration %d (%s) would affect %d documents\n", r.Version, r.Description, r.Affected)
+		} else {
+			fmt.Printf("recomputing migration %d (%s) affected %d documents\n", r.Version, r.Description, r.Affected)
+		}
+	}
+	return err
+}
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/buarki/find-castles/castle"
+	"github.com/buarki/find-castles/db"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+var (
+	overrideCommands = map[string]func(ctx context.Context, database *mongo.Database, args []string) error{
+		"add":    addOverride,
+		"list":   listOverrides,
+		"remove": removeOverride,
+	}
+)
+
+// overrideCommand manages the values curators pin to castle fields.
+func overrideCommand(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("missing override command: add, list or remove")
+	}
+	command, found := overrideCommands[args[0]]
+	if !found {
+		return fmt.Errorf("unknown override command [%s], available ones are add, list and remove", args[0])
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), commandTimeout)
+	defer cancel()
+
+	dbClient, database, err := connectToDB(ctx)
+	if err != nil {
+		return err
+	}
+	defer dbClient.Disconnect(context.Background())
+
+	if err := castlesCollectionsOf(database).addOverridesIndexes(ctx); err != nil {
+		return err
+	}
+	return command(ctx, database, args[1:])
+}
+
+func addOverride(ctx context.Context, database *mongo.Database, args []string) error {
+	flags := flag.NewFlagSet("override add", flag.ExitOnError)
+	webName := flags.String("castle", "", "web name of the castle")
+	field := flags.String("field", "", "field to override, ex: state or contact.phone")
+	value := flags.String("value", "", "value to pin, empty to clear the field")
+	author := flags.String("author", "", "who is overriding the field")
+	reason := flags.String("reason", "", "why the scraped value is wrong")
+	flags.Parse(args)
+
+	if *webName == "" || *field == "" {
+		return fmt.Errorf("missing flags -castle and -field")
+	}
+
+	castles := database.Collection(collectionName)
+	overrides := database.Collection(overridesCollectionName)
+	c, err := db.GetCastleByWebNameOrAlias(ctx, castles, *webName)
+	if err != nil {
+		return fmt.Errorf("failed to find castle [%s], got %v", *webName, err)
+	}
+
+	override := castle.Override{
+		CastleID:  c.ID,
+		WebName:   c.AllocatedWebName,
+		Field:     *field,
+		Value:     *value,
+		Author:    *author,
+		Reason:    *reason,
+		CreatedAt: time.Now().UTC(),
+	}
+	if err := db.SaveOverride(ctx, overrides, override); err != nil {
+		return err
+	}
+	if err := db.ApplySavedOverrides(ctx, castles, overrides, c.ID); err != nil {
+		return err
+	}
+	fmt.Printf("field [%s] of castle [%s] overridden\n", *field, *webName)
+	return nil
+}
+
+func listOverrides(ctx context.Context, database *mongo.Database, args []string) error {
+	flags := flag.NewFlagSet("override list", flag.ExitOnError)
+	webName := flags.String("castle", "", "web name of the castle, defaults to all castles")
+	flags.Parse(args)
+
+	var castleID string
+	if *webName != "" {
+		c, err := db.GetCastleByWebNameOrAlias(ctx, database.Collection(collectionName), *webName)
+		if err != nil {
+			return fmt.Errorf("failed to find castle [%s], got %v", *webName, err)
+		}
+		castleID = c.ID
+	}
+	overrides, err := db.ListOverrides(ctx, database.Collection(overridesCollectionName), castleID)
+	if err != nil {
+		return err
+	}
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(overrides)
+}
+
+func removeOverride(ctx context.Context, database *mongo.Database, args []string) error {
+	flags := flag.NewFlagSet("override remove", flag.ExitOnError)
+	webName := flags.String("castle", "", "web name of the castle")
+	field := flags.String("field", "", "overridden field")
+	flags.Parse(args)
+
+	if *webName == "" || *field == "" {
+		return fmt.Errorf("missing flags -castle and -field")
+	}
+	c, err := db.GetCastleByWebNameOrAlias(ctx, database.Collection(collectionName), *webName)
+	if err != nil {
+		return fmt.Errorf("failed to find castle [%s], got %v", *webName, err)
+	}
+	if err := db.RemoveOverride(ctx, database.Collection(overridesCollectionName), c.ID, *field); err != nil {
+		return err
+	}
+	fmt.Printf("override of field [%s] of castle [%s] removed, the scraped value is restored on the next enrichment\n", *field, *webName)
+	return nil
+}
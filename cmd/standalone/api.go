@@ -0,0 +1,433 @@
+package main
+
+import (
+	_ "embed"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	// castles are open on the time zones of their countries, which the host may not have
+	_ "time/tzdata"
+
+	"github.com/buarki/find-castles/castle"
+	"github.com/buarki/find-castles/db"
+	"github.com/buarki/find-castles/export"
+)
+
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+)
+
+//go:embed openapi.json
+var openAPIDocument []byte
+
+// castleResource is a castle as the API documents it on openapi.json, so
+// fields kept for the enrichment, like the raw data of sources, stay out.
+type castleResource struct {
+	ID      string         `json:"id"`
+	WebName string         `json:"webName"`
+	Name    string         `json:"name"`
+	Sources []string       `json:"sources"`
+	Country castle.Country `json:"country"`
+	// name on the language asked by the lang query param, or the primary one
+	DisplayName      string                     `json:"displayName"`
+	LocalizedNames   map[castle.Language]string `json:"localizedNames"`
+	AlternateNames   []string                   `json:"alternateNames"`
+	Address          *castle.Address            `json:"address"`
+	State            string                     `json:"state"`
+	StateCode        string                     `json:"stateCode"`
+	StateLabel       string                     `json:"stateLabel"`
+	City             string                     `json:"city"`
+	District         string                     `json:"district"`
+	FoundationPeriod string                     `json:"foundationPeriod"`
+	// foundation period parsed into years, when it could be
+	Foundation             *castle.Period           `json:"foundation,omitempty"`
+	PropertyCondition      castle.PropertyCondition `json:"propertyCondition"`
+	PropertyConditionLabel string                   `json:"propertyConditionLabel"`
+	Coordinates            string                   `json:"coordinates"`
+	Derived                []string                 `json:"derived"`
+	PictureURL             string                   `json:"pictureURL"`
+	Contact                *castle.Contact          `json:"contact"`
+	VisitingInfo           *castle.VisitingInfo     `json:"visitingInfo"`
+	// opening hours on the OpenStreetMap syntax, when known
+	OSMOpeningHours string        `json:"osmOpeningHours,omitempty"`
+	Status          castle.Status `json:"status"`
+	WebNameAliases  []string      `json:"webNameAliases"`
+}
+
+type countryResource struct {
+	Country castle.Country `json:"country"`
+	Count   int64          `json:"count"`
+}
+
+type castlesAPI struct {
+	store castlesStore
+}
+
+func (api *castlesAPI) register(mux *http.ServeMux) {
+	mux.HandleFunc("GET /api/castles", api.listCastles)
+	mux.HandleFunc("GET /api/castles.geojson", api.exportCastles)
+	mux.HandleFunc("GET /api/castles/search", api.searchCastles)
+	mux.HandleFunc("GET /api/castles/{webName}", api.getCastle)
+	mux.HandleFunc("GET /api/castles/{webName}/open", api.isCastleOpen)
+	mux.HandleFunc("GET /api/countries", api.listCountries)
+	mux.HandleFunc("GET /api/openapi.json", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(openAPIDocument)
+	})
+}
+
+func (api *castlesAPI) listCastles(w http.ResponseWriter, r *http.Request) {
+	query, err := queryFrom(r)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"message": err.Error()})
+		return
+	}
+	limit, err := limitFrom(r)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"message": err.Error()})
+		return
+	}
+	order, err := castle.ParseSortOrder(r.URL.Query().Get("sort"))
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"message": err.Error()})
+		return
+	}
+	afterWebName, err := decodeCursor(r.URL.Query().Get("cursor"))
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"message": "invalid cursor"})
+		return
+	}
+
+	// one more than asked tells whether there is a next page
+	castles, err := api.store.FindCastles(r.Context(), query, order, afterWebName, limit+1)
+	if err != nil {
+		log.Printf("failed to find castles: %v", err)
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"message": "failed to find castles"})
+		return
+	}
+
+	hasNextPage := len(castles) > limit
+	if hasNextPage {
+		castles = castles[:limit]
+	}
+	resources := make([]castleResource, 0, len(castles))
+	for _, c := range castles {
+		resource, err := resourceOf(c, languageFrom(r))
+		if err != nil {
+			log.Printf("failed to get web name of castle [%s]: %v", c.Name, err)
+			continue
+		}
+		resources = append(resources, resource)
+	}
+	var nextCursor string
+	if hasNextPage && len(resources) > 0 {
+		nextCursor = encodeCursor(resources[len(resources)-1].WebName)
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{
+		"data":       resources,
+		"nextCursor": nextCursor,
+	})
+}
+
+// searchCastles ranks castles by how relevant their names and places are to
+// the q param, counting the results by country and condition.
+func (api *castlesAPI) searchCastles(w http.ResponseWriter, r *http.Request) {
+	params := r.URL.Query()
+	var filters db.SearchFilters
+	for _, country := range listParam(params.Get("country")) {
+		filters.Countries = append(filters.Countries, castle.Country(strings.ToLower(country)))
+	}
+	for _, condition := range listParam(params.Get("condition")) {
+		filters.Conditions = append(filters.Conditions, castle.PropertyCondition(strings.ToLower(condition)))
+	}
+	limit, err := limitFrom(r)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"message": err.Error()})
+		return
+	}
+	page := db.Page{Number: 1, Size: int64(limit)}
+	if rawPage := params.Get("page"); rawPage != "" {
+		number, err := strconv.ParseInt(rawPage, 10, 64)
+		if err != nil || number < 1 {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"message": "page must be a number from 1"})
+			return
+		}
+		page.Number = number
+	}
+
+	result, err := api.store.SearchCastles(r.Context(), params.Get("q"), filters, page)
+	if err != nil {
+		log.Printf("failed to search castles: %v", err)
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"message": "failed to search castles"})
+		return
+	}
+	resources := make([]castleResource, 0, len(result.Castles))
+	for _, c := range result.Castles {
+		resource, err := resourceOf(c, languageFrom(r))
+		if err != nil {
+			log.Printf("failed to get web name of castle [%s]: %v", c.Name, err)
+			continue
+		}
+		resources = append(resources, resource)
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{
+		"data":   resources,
+		"total":  result.Total,
+		"page":   result.Page,
+		"facets": result.Facets,
+	})
+}
+
+// exportCastles returns every castle matching the filters as a GeoJSON feature collection.
+func (api *castlesAPI) exportCastles(w http.ResponseWriter, r *http.Request) {
+	query, err := queryFrom(r)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"message": err.Error()})
+		return
+	}
+	castles, err := api.store.FindCastles(r.Context(), query, castle.SortByWebName, "", 0)
+	if err != nil {
+		log.Printf("failed to find castles: %v", err)
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"message": "failed to find castles"})
+		return
+	}
+	w.Header().Set("Content-Type", export.GeoJSON.ContentType())
+	if err := export.WriteGeoJSON(w, castles); err != nil {
+		log.Printf("failed to export castles: %v", err)
+	}
+}
+
+func (api *castlesAPI) getCastle(w http.ResponseWriter, r *http.Request) {
+	webName := r.PathValue("webName")
+	c, err := api.store.GetCastle(r.Context(), webName)
+	if errors.Is(err, errCastleNotFound) {
+		api.redirectFromAlias(w, r, webName)
+		return
+	}
+	if err != nil {
+		log.Printf("failed to get castle: %v", err)
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"message": "failed to get castle"})
+		return
+	}
+	resource, err := resourceOf(c, languageFrom(r))
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"message": "failed to get castle"})
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"data": resource})
+}
+
+// isCastleOpen tells whether the castle is open at the time given by the at
+// query param, now when not given, on the time zone of its country.
+func (api *castlesAPI) isCastleOpen(w http.ResponseWriter, r *http.Request) {
+	at := time.Now()
+	if rawAt := r.URL.Query().Get("at"); rawAt != "" {
+		parsed, err := time.Parse(time.RFC3339, rawAt)
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"message": "at must be a RFC 3339 time, ex: 2024-03-16T11:00:00Z"})
+			return
+		}
+		at = parsed
+	}
+
+	c, err := api.store.GetCastle(r.Context(), r.PathValue("webName"))
+	if errors.Is(err, errCastleNotFound) {
+		writeJSON(w, http.StatusNotFound, map[string]string{"message": "castle not found"})
+		return
+	}
+	if err != nil {
+		log.Printf("failed to get castle: %v", err)
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"message": "failed to get castle"})
+		return
+	}
+	if c.VisitingInfo == nil || c.VisitingInfo.OpeningHours == nil {
+		writeJSON(w, http.StatusNotFound, map[string]string{"message": "opening hours of castle are unknown"})
+		return
+	}
+	location, err := c.Country.Location()
+	if err != nil {
+		log.Printf("failed to get time zone of castle: %v", err)
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"message": "failed to get time zone of castle"})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{"data": map[string]any{
+		"open":            c.VisitingInfo.OpeningHours.IsOpenAt(at, location),
+		"at":              at.In(location).Format(time.RFC3339),
+		"osmOpeningHours": c.VisitingInfo.OpeningHours.String(),
+	}})
+}
+
+// redirectFromAlias sends to the current web name of a castle that was renamed.
+func (api *castlesAPI) redirectFromAlias(w http.ResponseWriter, r *http.Request, alias string) {
+	c, err := api.store.GetCastleByAlias(r.Context(), alias)
+	if errors.Is(err, errCastleNotFound) {
+		writeJSON(w, http.StatusNotFound, map[string]string{"message": "castle not found"})
+		return
+	}
+	if err != nil {
+		log.Printf("failed to get castle by alias: %v", err)
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"message": "failed to get castle"})
+		return
+	}
+	webName, err := c.WebName()
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"message": "failed to get castle"})
+		return
+	}
+	http.Redirect(w, r, "/api/castles/"+url.PathEscape(webName), http.StatusMovedPermanently)
+}
+
+func (api *castlesAPI) listCountries(w http.ResponseWriter, r *http.Request) {
+	counts, err := api.store.CountByCountry(r.Context())
+	if err != nil {
+		log.Printf("failed to count castles by country: %v", err)
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"message": "failed to count castles"})
+		return
+	}
+	countries := make([]countryResource, 0, len(counts))
+	for country, count := range counts {
+		countries = append(countries, countryResource{Country: country, Count: count})
+	}
+	slices.SortFunc(countries, func(a, b countryResource) int {
+		return strings.Compare(a.Country.String(), b.Country.String())
+	})
+	writeJSON(w, http.StatusOK, map[string]any{"data": countries})
+}
+
+func queryFrom(r *http.Request) (castle.Query, error) {
+	params := r.URL.Query()
+	var query castle.Query
+	for _, country := range listParam(params.Get("country")) {
+		query.Countries = append(query.Countries, castle.Country(strings.ToLower(country)))
+	}
+	for _, state := range listParam(params.Get("state")) {
+		query.States = append(query.States, strings.ToLower(state))
+	}
+	for _, condition := range listParam(params.Get("condition")) {
+		query.Conditions = append(query.Conditions, castle.PropertyCondition(strings.ToLower(condition)))
+	}
+	for _, rawFacility := range listParam(params.Get("facility")) {
+		facility := castle.Facility(rawFacility)
+		if !facility.IsKnown() {
+			return castle.Query{}, fmt.Errorf("unknown facility [%s]", facility)
+		}
+		query.Facilities = append(query.Facilities, facility)
+	}
+	if rawBoundingBox := params.Get("bbox"); rawBoundingBox != "" {
+		boundingBox, err := castle.ParseBoundingBox(rawBoundingBox)
+		if err != nil {
+			return castle.Query{}, err
+		}
+		query.BoundingBox = &boundingBox
+	}
+	for param, year := range map[string]*int{"builtBefore": &query.BuiltBefore, "builtAfter": &query.BuiltAfter} {
+		rawYear := params.Get(param)
+		if rawYear == "" {
+			continue
+		}
+		parsed, err := strconv.Atoi(rawYear)
+		if err != nil || parsed < 1 {
+			return castle.Query{}, fmt.Errorf("%s must be a year", param)
+		}
+		*year = parsed
+	}
+	return query, nil
+}
+
+func limitFrom(r *http.Request) (int, error) {
+	rawLimit := r.URL.Query().Get("limit")
+	if rawLimit == "" {
+		return defaultPageSize, nil
+	}
+	limit, err := strconv.Atoi(rawLimit)
+	if err != nil || limit < 1 || limit > maxPageSize {
+		return 0, fmt.Errorf("limit must be a number between 1 and %d", maxPageSize)
+	}
+	return limit, nil
+}
+
+func listParam(raw string) []string {
+	var values []string
+	for _, value := range strings.Split(raw, ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+func encodeCursor(webName string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(webName))
+}
+
+func decodeCursor(cursor string) (string, error) {
+	decoded, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return "", err
+	}
+	return string(decoded), nil
+}
+
+func resourceOf(c castle.Model, language castle.Language) (castleResource, error) {
+	webName, err := c.WebName()
+	if err != nil {
+		return castleResource{}, err
+	}
+	resource := castleResource{
+		ID:                     c.ID,
+		WebName:                webName,
+		Name:                   c.Name,
+		Sources:                c.Sources,
+		Country:                c.Country,
+		DisplayName:            c.DisplayName(language),
+		LocalizedNames:         c.LocalizedNames,
+		AlternateNames:         c.AlternateNames,
+		Address:                c.Address,
+		State:                  c.State,
+		StateCode:              c.StateCode,
+		StateLabel:             c.StateLabel,
+		City:                   c.City,
+		District:               c.District,
+		FoundationPeriod:       c.FoundationPeriod,
+		PropertyCondition:      c.PropertyCondition,
+		PropertyConditionLabel: c.PropertyConditionLabel,
+		Coordinates:            c.Coordinates,
+		Derived:                c.Derived,
+		PictureURL:             c.PictureURL,
+		Contact:                c.Contact,
+		VisitingInfo:           c.VisitingInfo,
+		Status:                 c.Status,
+		WebNameAliases:         c.WebNameAliases,
+	}
+	if c.VisitingInfo != nil && c.VisitingInfo.OpeningHours != nil {
+		resource.OSMOpeningHours = c.VisitingInfo.OpeningHours.String()
+	}
+	if foundation, ok := c.Foundation(); ok {
+		resource.Foundation = &foundation
+	}
+	return resource, nil
+}
+
+func languageFrom(r *http.Request) castle.Language {
+	return castle.Language(strings.ToLower(r.URL.Query().Get("lang")))
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		log.Printf("failed to write response: %v", err)
+	}
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"log"
@@ -8,12 +9,19 @@ import (
 	"os"
 	"runtime"
 
+	"github.com/buarki/find-castles/castle"
+	"github.com/buarki/find-castles/db"
 	"github.com/buarki/find-castles/enricher"
 	"github.com/buarki/find-castles/executor"
 	"github.com/buarki/find-castles/htmlfetcher"
 	"github.com/buarki/find-castles/httpclient"
 )
 
+const (
+	databaseName   = "find-castles"
+	collectionName = "castles"
+)
+
 func main() {
 	port := os.Getenv("PORT")
 	if port == "" {
@@ -29,6 +37,20 @@ func main() {
 	cpus := runtime.NumCPU()
 	castlesEnricher := executor.New(int(float64(cpus)*0.3), int(float64(cpus)*0.7), httpClient, enrichers)
 
+	// without a DB the API serves the castles of the last enrichment done by this server
+	lastEnrichment := newMemoryStore()
+	var store castlesStore = lastEnrichment
+	if mongoURI := os.Getenv("DB_URI"); mongoURI != "" {
+		dbClient, err := db.NewClient(context.Background(), mongoURI)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer dbClient.Disconnect(context.Background())
+		store = &dbStore{collection: dbClient.Database(databaseName).Collection(collectionName)}
+	}
+	api := &castlesAPI{store: store}
+	api.register(http.DefaultServeMux)
+
 	fs := http.FileServer(http.Dir("./cmd/standalone/public"))
 	http.Handle("/", fs)
 	http.HandleFunc("/sse", func(w http.ResponseWriter, r *http.Request) {
@@ -37,6 +59,7 @@ func main() {
 		w.Header().Set("Connection", "keep-alive")
 
 		enrichedCastles, enrichmentErrs := castlesEnricher.Enrich(r.Context())
+		var collectedCastles []castle.Model
 
 		for {
 			select {
@@ -49,6 +72,7 @@ func main() {
 				}
 			case castle, ok := <-enrichedCastles:
 				if ok {
+					collectedCastles = append(collectedCastles, castle)
 					cb, err := json.Marshal(castle)
 					if err != nil {
 						log.Printf("failed to marshal castle [%s]: %v", castle.Name, err)
@@ -71,6 +95,7 @@ func main() {
 						log.Println("response writer does not support flushing")
 					}
 					log.Println("finished processing castles")
+					lastEnrichment.Replace(collectedCastles)
 					return
 				}
 			}
@@ -0,0 +1,232 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/buarki/find-castles/castle"
+	"github.com/buarki/find-castles/db"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+var (
+	errCastleNotFound = errors.New("castle not found")
+)
+
+// castlesStore is where the API reads castles from.
+type castlesStore interface {
+	// FindCastles returns up to limit castles on the given order, starting after the one with
+	// the given web name. A limit of zero returns all of them.
+	FindCastles(ctx context.Context, query castle.Query, order castle.SortOrder, afterWebName string, limit int) ([]castle.Model, error)
+	GetCastle(ctx context.Context, webName string) (castle.Model, error)
+	// GetCastleByAlias returns the castle that had the given web name before
+	GetCastleByAlias(ctx context.Context, webName string) (castle.Model, error)
+	CountByCountry(ctx context.Context) (map[castle.Country]int64, error)
+	// SearchCastles returns the castles matching any term of the query ranked by
+	// relevance, or sorted by name when it is empty, counting them by facet
+	SearchCastles(ctx context.Context, query string, filters db.SearchFilters, page db.Page) (db.SearchResult, error)
+}
+
+// memoryStore keeps the castles of the last enrichment executed by this server.
+type memoryStore struct {
+	mutex     sync.RWMutex
+	castles   []castle.Model
+	byWebName map[string]castle.Model
+}
+
+func newMemoryStore() *memoryStore {
+	return &memoryStore{
+		byWebName: make(map[string]castle.Model),
+	}
+}
+
+func (s *memoryStore) Replace(castles []castle.Model) {
+	byWebName := make(map[string]castle.Model, len(castles))
+	for _, c := range castles {
+		webName, err := c.WebName()
+		if err != nil {
+			continue
+		}
+		byWebName[webName] = c
+	}
+	webNames := make([]string, 0, len(byWebName))
+	for webName := range byWebName {
+		webNames = append(webNames, webName)
+	}
+	sort.Strings(webNames)
+	sorted := make([]castle.Model, len(webNames))
+	for i, webName := range webNames {
+		sorted[i] = byWebName[webName]
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.castles = sorted
+	s.byWebName = byWebName
+}
+
+func (s *memoryStore) FindCastles(ctx context.Context, query castle.Query, order castle.SortOrder, afterWebName string, limit int) ([]castle.Model, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	castles := s.castles
+	if order != castle.SortByWebName {
+		castles = slices.Clone(castles)
+		slices.SortStableFunc(castles, order.Compare)
+	}
+	after, afterFound := s.byWebName[afterWebName]
+
+	var found []castle.Model
+	for _, c := range castles {
+		if limit > 0 && len(found) == limit {
+			break
+		}
+		if !query.Matches(c) || !order.Lists(c) {
+			continue
+		}
+		if afterWebName != "" {
+			webName, _ := c.WebName()
+			if afterFound && order.Compare(c, after) <= 0 || !afterFound && webName <= afterWebName {
+				continue
+			}
+		}
+		found = append(found, c)
+	}
+	return found, nil
+}
+
+func (s *memoryStore) GetCastle(ctx context.Context, webName string) (castle.Model, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	c, found := s.byWebName[webName]
+	if !found {
+		return castle.Model{}, errCastleNotFound
+	}
+	return c, nil
+}
+
+// GetCastleByAlias finds nothing, as castles of a single enrichment were never renamed.
+func (s *memoryStore) GetCastleByAlias(ctx context.Context, webName string) (castle.Model, error) {
+	return castle.Model{}, errCastleNotFound
+}
+
+func (s *memoryStore) CountByCountry(ctx context.Context) (map[castle.Country]int64, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	counts := make(map[castle.Country]int64)
+	for _, c := range s.castles {
+		counts[c.Country]++
+	}
+	return counts, nil
+}
+
+func (s *memoryStore) SearchCastles(ctx context.Context, query string, filters db.SearchFilters, page db.Page) (db.SearchResult, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	page = page.Normalized()
+	terms := castle.SearchTerms(query)
+	type scoredCastle struct {
+		castle.Model
+		score int
+	}
+	var scored []scoredCastle
+	for _, c := range s.castles {
+		if c.Status == castle.Removed {
+			continue
+		}
+		score := c.SearchScore(terms)
+		if len(terms) > 0 && score == 0 {
+			continue
+		}
+		scored = append(scored, scoredCastle{Model: c, score: score})
+	}
+	slices.SortStableFunc(scored, func(a, b scoredCastle) int {
+		if a.score != b.score {
+			return b.score - a.score
+		}
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	inCountries := func(c castle.Model) bool {
+		return len(filters.Countries) == 0 || slices.Contains(filters.Countries, c.Country)
+	}
+	inConditions := func(c castle.Model) bool {
+		return len(filters.Conditions) == 0 || slices.Contains(castle.CoveredConditions(filters.Conditions), c.PropertyCondition)
+	}
+	result := db.SearchResult{Page: page, Castles: []castle.Model{}}
+	countries := make(map[string]int64)
+	conditions := make(map[string]int64)
+	for _, sc := range scored {
+		c := sc.Model
+		if inConditions(c) {
+			countries[c.Country.String()]++
+		}
+		if inCountries(c) {
+			conditions[c.PropertyCondition.String()]++
+		}
+		if !inCountries(c) || !inConditions(c) {
+			continue
+		}
+		if result.Total >= (page.Number-1)*page.Size && result.Total < page.Number*page.Size {
+			result.Castles = append(result.Castles, c)
+		}
+		result.Total++
+	}
+	result.Facets = db.SearchFacets{Countries: facetCountsOf(countries), Conditions: facetCountsOf(conditions)}
+	return result, nil
+}
+
+// facetCountsOf sorts the counts from the biggest, as $sortByCount does.
+func facetCountsOf(counts map[string]int64) []db.FacetCount {
+	facets := make([]db.FacetCount, 0, len(counts))
+	for value, count := range counts {
+		facets = append(facets, db.FacetCount{Value: value, Count: count})
+	}
+	slices.SortFunc(facets, func(a, b db.FacetCount) int {
+		if a.Count != b.Count {
+			return int(b.Count - a.Count)
+		}
+		return strings.Compare(a.Value, b.Value)
+	})
+	return facets
+}
+
+// dbStore reads the castles saved by the enricher.
+type dbStore struct {
+	collection *mongo.Collection
+}
+
+func (s *dbStore) FindCastles(ctx context.Context, query castle.Query, order castle.SortOrder, afterWebName string, limit int) ([]castle.Model, error) {
+	return db.FindCastles(ctx, s.collection, query, order, afterWebName, int64(limit))
+}
+
+func (s *dbStore) GetCastle(ctx context.Context, webName string) (castle.Model, error) {
+	c, err := db.GetCastleByWebName(ctx, s.collection, webName)
+	if errors.Is(err, db.ErrCastleNotFound) {
+		return castle.Model{}, errCastleNotFound
+	}
+	return c, err
+}
+
+func (s *dbStore) GetCastleByAlias(ctx context.Context, webName string) (castle.Model, error) {
+	c, err := db.GetCastleByAlias(ctx, s.collection, webName)
+	if errors.Is(err, db.ErrCastleNotFound) {
+		return castle.Model{}, errCastleNotFound
+	}
+	return c, err
+}
+
+func (s *dbStore) CountByCountry(ctx context.Context) (map[castle.Country]int64, error) {
+	return db.CountCastlesByCountry(ctx, s.collection)
+}
+
+func (s *dbStore) SearchCastles(ctx context.Context, query string, filters db.SearchFilters, page db.Page) (db.SearchResult, error) {
+	return db.SearchCastles(ctx, s.collection, query, filters, page)
+}
@@ -0,0 +1,124 @@
+package dataset
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/buarki/find-castles/castle"
+)
+
+var (
+	ErrMissingCSVHeader = errors.New("missing CSV header")
+)
+
+// CSVWriter writes castles as CSV rows, with nested fields flattened into
+// columns named by their JSON path, ex: contact.phone.
+type CSVWriter struct {
+	writer        *csv.Writer
+	headerWritten bool
+}
+
+func NewCSVWriter(w io.Writer) *CSVWriter {
+	return &CSVWriter{writer: csv.NewWriter(w)}
+}
+
+func (w *CSVWriter) Write(c castle.Model) error {
+	if !w.headerWritten {
+		if err := w.writeHeader(); err != nil {
+			return err
+		}
+	}
+	record := make([]string, len(castle.FieldNames))
+	for i, name := range castle.FieldNames {
+		value, err := c.Field(name)
+		if err != nil {
+			return err
+		}
+		record[i] = value
+	}
+	if err := w.writer.Write(record); err != nil {
+		return fmt.Errorf("failed to write castle [%s] as CSV, got %v", c.Name, err)
+	}
+	return nil
+}
+
+// Flush writes any buffered row, including the header when no castle was written.
+func (w *CSVWriter) Flush() error {
+	if !w.headerWritten {
+		if err := w.writeHeader(); err != nil {
+			return err
+		}
+	}
+	w.writer.Flush()
+	if err := w.writer.Error(); err != nil {
+		return fmt.Errorf("failed to flush CSV, got %v", err)
+	}
+	return nil
+}
+
+func (w *CSVWriter) writeHeader() error {
+	if err := w.writer.Write(castle.FieldNames); err != nil {
+		return fmt.Errorf("failed to write CSV header, got %v", err)
+	}
+	w.headerWritten = true
+	return nil
+}
+
+// CSVReader reads castles from CSV rows. The first row must be the header,
+// with columns in any order; missing columns are left empty.
+type CSVReader struct {
+	reader  *csv.Reader
+	columns []string
+	row     int
+}
+
+func NewCSVReader(r io.Reader) *CSVReader {
+	return &CSVReader{reader: csv.NewReader(r)}
+}
+
+// Read returns the next castle or io.EOF when there are no more.
+func (r *CSVReader) Read() (castle.Model, error) {
+	if r.columns == nil {
+		if err := r.readHeader(); err != nil {
+			return castle.Model{}, err
+		}
+	}
+	record, err := r.reader.Read()
+	if err == io.EOF {
+		return castle.Model{}, io.EOF
+	}
+	r.row++
+	if err != nil {
+		return castle.Model{}, fmt.Errorf("failed to read CSV row %d, got %v", r.row, err)
+	}
+	var c castle.Model
+	for i, name := range r.columns {
+		if err := c.SetField(name, strings.TrimSpace(record[i])); err != nil {
+			return castle.Model{}, fmt.Errorf("failed to read CSV row %d, %v", r.row, err)
+		}
+	}
+	return c, nil
+}
+
+func (r *CSVReader) readHeader() error {
+	header, err := r.reader.Read()
+	if err == io.EOF {
+		return ErrMissingCSVHeader
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read CSV header, got %v", err)
+	}
+	r.columns = make([]string, len(header))
+	for i, name := range header {
+		name = strings.TrimSpace(name)
+		if !slices.Contains(castle.FieldNames, name) {
+			return fmt.Errorf("unknown CSV column [%s]", name)
+		}
+		r.columns[i] = name
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+package dataset
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/buarki/find-castles/castle"
+)
+
+type Format string
+
+const (
+	JSONL Format = "jsonl"
+	CSV   Format = "csv"
+)
+
+// Reader streams castles, returning io.EOF when there are no more.
+type Reader interface {
+	Read() (castle.Model, error)
+}
+
+// Writer streams castles. Flush must be called once all of them are written.
+type Writer interface {
+	Write(c castle.Model) error
+	Flush() error
+}
+
+func NewReader(r io.Reader, format Format) (Reader, error) {
+	switch format {
+	case JSONL:
+		return NewJSONLReader(r), nil
+	case CSV:
+		return NewCSVReader(r), nil
+	default:
+		return nil, fmt.Errorf("unsupported dataset format [%s]", format)
+	}
+}
+
+func NewWriter(w io.Writer, format Format) (Writer, error) {
+	switch format {
+	case JSONL:
+		return NewJSONLWriter(w), nil
+	case CSV:
+		return NewCSVWriter(w), nil
+	default:
+		return nil, fmt.Errorf("unsupported dataset format [%s]", format)
+	}
+}
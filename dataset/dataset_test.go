@@ -0,0 +1,149 @@
+package dataset
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/buarki/find-castles/castle"
+)
+
+var (
+	castlesToWrite = []castle.Model{
+		{
+			Name:              "trim",
+			Country:           castle.Ireland,
+			Sources:           []string{"https://heritageireland.ie/places-to-visit/trim-castle/", "https://www.ebidat.de/cgi-bin/ebidat.pl?id=1;"},
+			State:             "meath",
+			StateCode:         "IE-MH",
+			StateLabel:        "meath",
+			City:              "trim",
+			FoundationPeriod:  "1172",
+			PropertyCondition: castle.Intact,
+			Coordinates:       "53.5545,-6.79",
+			Status:            castle.Active,
+			Contact:           &castle.Contact{Phone: "+353 46 943 8619", Email: "trimcastle@opw.ie"},
+			VisitingInfo: &castle.VisitingInfo{
+				WorkingHours: "10:00, to 17:00",
+				Facilities: castle.Facilities{
+					castle.Parking:   {Availability: castle.Available},
+					castle.Restrooms: {Availability: castle.NotAvailable},
+				},
+			},
+		},
+		{
+			Name:    "castle with only mandatory fields",
+			Country: castle.Portugal,
+			Sources: []string{"https://www.castelosdeportugal.pt/"},
+		},
+	}
+)
+
+func TestRoundTrip(t *testing.T) {
+	testCases := []Format{JSONL, CSV}
+
+	for _, tt := range testCases {
+		currentTT := tt
+		t.Run(string(currentTT), func(t *testing.T) {
+			t.Helper()
+			var b bytes.Buffer
+			writer, err := NewWriter(&b, currentTT)
+			if err != nil {
+				t.Fatalf("expected err nil, got %v", err)
+			}
+			for _, c := range castlesToWrite {
+				if err := writer.Write(c); err != nil {
+					t.Fatalf("expected err nil, got %v", err)
+				}
+			}
+			if err := writer.Flush(); err != nil {
+				t.Fatalf("expected err nil, got %v", err)
+			}
+
+			reader, err := NewReader(&b, currentTT)
+			if err != nil {
+				t.Fatalf("expected err nil, got %v", err)
+			}
+			var read []castle.Model
+			for {
+				c, err := reader.Read()
+				if errors.Is(err, io.EOF) {
+					break
+				}
+				if err != nil {
+					t.Fatalf("expected err nil, got %v", err)
+				}
+				read = append(read, c)
+			}
+			if !reflect.DeepEqual(castlesToWrite, read) {
+				t.Errorf("expected %+v, got %+v", castlesToWrite, read)
+			}
+		})
+	}
+}
+
+func TestCSVReader(t *testing.T) {
+	testCases := []struct {
+		name          string
+		csv           string
+		expected      castle.Model
+		expectedError bool
+	}{
+		{
+			name: "columns_in_any_order_and_missing_ones",
+			csv:  "country,visitingInfo.facilities.cafe,name\nuk,true,conwy\n",
+			expected: castle.Model{
+				Name:         "conwy",
+				Country:      castle.UK,
+				VisitingInfo: &castle.VisitingInfo{Facilities: castle.Facilities{castle.Cafe: {Availability: castle.Available}}},
+			},
+		},
+		{
+			name: "working_hours_without_facilities",
+			csv:  "name,country,visitingInfo.workingHours\nconwy,uk,9:30 to 17:00\n",
+			expected: castle.Model{
+				Name:         "conwy",
+				Country:      castle.UK,
+				VisitingInfo: &castle.VisitingInfo{WorkingHours: "9:30 to 17:00"},
+			},
+		},
+		{
+			name:          "unknown_column",
+			csv:           "name,country,moat\nconwy,uk,true\n",
+			expectedError: true,
+		},
+		{
+			name:          "invalid_facility",
+			csv:           "name,country,visitingInfo.facilities.cafe\nconwy,uk,maybe\n",
+			expectedError: true,
+		},
+		{
+			name:          "empty_file",
+			csv:           "",
+			expectedError: true,
+		},
+	}
+
+	for _, tt := range testCases {
+		currentTT := tt
+		t.Run(currentTT.name, func(t *testing.T) {
+			t.Helper()
+			c, err := NewCSVReader(strings.NewReader(currentTT.csv)).Read()
+			if currentTT.expectedError {
+				if err == nil {
+					t.Errorf("expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("expected err nil, got %v", err)
+			}
+			if !reflect.DeepEqual(currentTT.expected, c) {
+				t.Errorf("expected %+v, got %+v", currentTT.expected, c)
+			}
+		})
+	}
+}
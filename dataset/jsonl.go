@@ -0,0 +1,64 @@
+package dataset
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/buarki/find-castles/castle"
+)
+
+// JSONLWriter writes one castle per line as JSON.
+type JSONLWriter struct {
+	encoder *json.Encoder
+}
+
+func NewJSONLWriter(w io.Writer) *JSONLWriter {
+	return &JSONLWriter{encoder: json.NewEncoder(w)}
+}
+
+func (w *JSONLWriter) Write(c castle.Model) error {
+	if err := w.encoder.Encode(c); err != nil {
+		return fmt.Errorf("failed to write castle [%s] as JSON line, got %v", c.Name, err)
+	}
+	return nil
+}
+
+// Flush does nothing, as every castle is written as soon as it is received.
+func (w *JSONLWriter) Flush() error {
+	return nil
+}
+
+// JSONLReader reads castles written one per line as JSON. Blank lines are skipped.
+type JSONLReader struct {
+	scanner *bufio.Scanner
+	line    int
+}
+
+func NewJSONLReader(r io.Reader) *JSONLReader {
+	scanner := bufio.NewScanner(r)
+	// castles with raw data can be way bigger than the default token size
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	return &JSONLReader{scanner: scanner}
+}
+
+// Read returns the next castle or io.EOF when there are no more.
+func (r *JSONLReader) Read() (castle.Model, error) {
+	for r.scanner.Scan() {
+		r.line++
+		line := r.scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+		var c castle.Model
+		if err := json.Unmarshal(line, &c); err != nil {
+			return castle.Model{}, fmt.Errorf("failed to read castle at line %d, got %v", r.line, err)
+		}
+		return c, nil
+	}
+	if err := r.scanner.Err(); err != nil {
+		return castle.Model{}, fmt.Errorf("failed to read JSON lines, got %v", err)
+	}
+	return castle.Model{}, io.EOF
+}
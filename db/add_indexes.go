@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"log/slog"
 
+	"github.com/buarki/find-castles/castle"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
@@ -14,13 +15,14 @@ func AddIndexes(ctx context.Context, collection *mongo.Collection) error {
 	isTrue := true
 	isFalse := false
 
-	nameAndCountry := mongo.IndexModel{
+	// sparse as castles saved before IDs existed only get one when migrated
+	id := mongo.IndexModel{
 		Keys: bson.D{
-			{Key: "country", Value: 1},
-			{Key: "name", Value: 1},
+			{Key: "id", Value: 1},
 		},
 		Options: &options.IndexOptions{
 			Unique: &isTrue,
+			Sparse: &isTrue,
 		},
 	}
 
@@ -33,6 +35,12 @@ func AddIndexes(ctx context.Context, collection *mongo.Collection) error {
 		},
 	}
 
+	blockingKeys := mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "blockingKeys", Value: 1},
+		},
+	}
+
 	countryIndex := mongo.IndexModel{
 		Keys: bson.D{
 			{Key: "country", Value: 1},
@@ -42,20 +50,102 @@ func AddIndexes(ctx context.Context, collection *mongo.Collection) error {
 		},
 	}
 
+	// web names are allocated so no two castles share one, see castle.Model.AllocateWebName
 	webNameIndex := mongo.IndexModel{
 		Keys: bson.D{
 			{Key: "webName", Value: 1},
 		},
 		Options: &options.IndexOptions{
-			Unique: &isFalse,
+			Unique: &isTrue,
+		},
+	}
+
+	webNameAliasesIndex := mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "webNameAliases", Value: 1},
+		},
+	}
+
+	sourcesIndex := mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "sources", Value: 1},
+		},
+	}
+
+	statusIndex := mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "status", Value: 1},
+			{Key: "missedRuns", Value: 1},
+		},
+	}
+
+	foundation := mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "foundation.earliest", Value: 1},
+			{Key: "webName", Value: 1},
+		},
+	}
+
+	stateCode := mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "stateCode", Value: 1},
+		},
+	}
+
+	location := mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "location", Value: "2dsphere"},
+		},
+	}
+
+	// a collection can have a single text index, changing it requires a
+	// migration dropping the old one
+	textName := textIndexName
+	noLanguage := "none" // castles have names in many languages, so no stemming nor stopwords
+	textKeys := bson.D{
+		{Key: "name", Value: "text"},
+		{Key: "alternateNames", Value: "text"},
+		{Key: "searchNames", Value: "text"},
+	}
+	textWeights := bson.D{
+		{Key: "name", Value: 10},
+		{Key: "alternateNames", Value: 8},
+		{Key: "searchNames", Value: 8},
+	}
+	for _, language := range castle.Languages {
+		field := "localizedNames." + language.String()
+		textKeys = append(textKeys, bson.E{Key: field, Value: "text"})
+		textWeights = append(textWeights, bson.E{Key: field, Value: 8})
+	}
+	for _, place := range []struct {
+		field  string
+		weight int
+	}{{"city", 5}, {"state", 3}, {"district", 3}} {
+		textKeys = append(textKeys, bson.E{Key: place.field, Value: "text"})
+		textWeights = append(textWeights, bson.E{Key: place.field, Value: place.weight})
+	}
+	text := mongo.IndexModel{
+		Keys: textKeys,
+		Options: &options.IndexOptions{
+			Name:            &textName,
+			DefaultLanguage: &noLanguage,
+			Weights:         textWeights,
 		},
 	}
 
 	indexes := []mongo.IndexModel{
-		nameAndCountry,
+		id,
 		matchingTags,
+		blockingKeys,
 		countryIndex,
 		webNameIndex,
+		webNameAliasesIndex,
+		sourcesIndex,
+		statusIndex,
+		foundation,
+		stateCode,
+		location,
+		text,
 	}
 
 	name, err := collection.Indexes().CreateMany(ctx, indexes)
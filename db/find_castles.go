@@ -0,0 +1,201 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/buarki/find-castles/castle"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// FindCastles returns up to limit castles matching the query, on the given
+// order and starting after the castle with the given web name, which allows
+// cursor pagination. A limit of zero returns all matching castles. Removed
+// castles are never returned.
+func FindCastles(ctx context.Context, collection *mongo.Collection, query castle.Query, order castle.SortOrder, afterWebName string, limit int64) ([]castle.Model, error) {
+	filter := filterOf(query)
+	filter["status"] = bson.M{"$ne": castle.Removed}
+	sort := bson.D{{Key: "webName", Value: 1}}
+	if order == castle.SortByFoundation {
+		filter["foundation"] = bson.M{"$exists": true}
+		sort = bson.D{{Key: "foundation.earliest", Value: 1}, {Key: "webName", Value: 1}}
+	}
+	if afterWebName != "" {
+		filter["webName"] = bson.M{"$gt": afterWebName}
+		if order == castle.SortByFoundation {
+			after, err := GetCastleByWebName(ctx, collection, afterWebName)
+			if err != nil && !errors.Is(err, ErrCastleNotFound) {
+				return nil, fmt.Errorf("failed to find castle [%s] to list castles after, got %v", afterWebName, err)
+			}
+			if foundation, ok := after.Foundation(); ok {
+				delete(filter, "webName")
+				// appended to the conditions of the query, which may have their own $or
+				conditions, _ := filter["$and"].(bson.A)
+				filter["$and"] = append(conditions, bson.M{"$or": bson.A{
+					bson.M{"foundation.earliest": bson.M{"$gt": foundation.Earliest}},
+					bson.M{"foundation.earliest": foundation.Earliest, "webName": bson.M{"$gt": afterWebName}},
+				}})
+			}
+		}
+	}
+
+	opts := options.Find().SetSort(sort).SetLimit(limit)
+	cursor, err := collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find castles, got %v", err)
+	}
+	defer cursor.Close(ctx)
+
+	var castles []castle.Model
+	if err := cursor.All(ctx, &castles); err != nil {
+		return nil, fmt.Errorf("failed to decode castles, got %v", err)
+	}
+	return castles, nil
+}
+
+func GetCastleByWebName(ctx context.Context, collection *mongo.Collection, webName string) (castle.Model, error) {
+	var result castle.Model
+	err := collection.FindOne(ctx, bson.M{"webName": webName}).Decode(&result)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return castle.Model{}, ErrCastleNotFound
+		}
+		return castle.Model{}, err
+	}
+	return result, nil
+}
+
+func GetCastleByID(ctx context.Context, collection *mongo.Collection, id string) (castle.Model, error) {
+	var result castle.Model
+	err := collection.FindOne(ctx, bson.M{"id": id}).Decode(&result)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return castle.Model{}, ErrCastleNotFound
+		}
+		return castle.Model{}, err
+	}
+	return result, nil
+}
+
+// GetCastleByAlias returns the castle that had the given web name before.
+func GetCastleByAlias(ctx context.Context, collection *mongo.Collection, webName string) (castle.Model, error) {
+	var result castle.Model
+	err := collection.FindOne(ctx, bson.M{"webNameAliases": webName}).Decode(&result)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return castle.Model{}, ErrCastleNotFound
+		}
+		return castle.Model{}, err
+	}
+	return result, nil
+}
+
+// GetCastleByWebNameOrAlias returns the castle with the given web name, now
+// or before.
+func GetCastleByWebNameOrAlias(ctx context.Context, collection *mongo.Collection, webName string) (castle.Model, error) {
+	c, err := GetCastleByWebName(ctx, collection, webName)
+	if errors.Is(err, ErrCastleNotFound) {
+		return GetCastleByAlias(ctx, collection, webName)
+	}
+	return c, err
+}
+
+// CountCastlesByCountry ignores removed castles.
+func CountCastlesByCountry(ctx context.Context, collection *mongo.Collection) (map[castle.Country]int64, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"status": bson.M{"$ne": castle.Removed}}}},
+		{{Key: "$sortByCount", Value: "$country"}},
+	}
+	cursor, err := collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count castles by country, got %v", err)
+	}
+	defer cursor.Close(ctx)
+
+	var counts []FacetCount
+	if err := cursor.All(ctx, &counts); err != nil {
+		return nil, fmt.Errorf("failed to decode castles count, got %v", err)
+	}
+	result := make(map[castle.Country]int64, len(counts))
+	for _, c := range counts {
+		result[castle.Country(c.Value)] = c.Count
+	}
+	return result, nil
+}
+
+func filterOf(query castle.Query) bson.M {
+	filter := bson.M{}
+	if len(query.Countries) > 0 {
+		filter["country"] = bson.M{"$in": query.Countries}
+	}
+	if len(query.States) > 0 {
+		filter["$and"] = bson.A{statesFilterOf(query.States)}
+	}
+	if len(query.Conditions) > 0 {
+		filter["propertyCondition"] = bson.M{"$in": castle.CoveredConditions(query.Conditions)}
+	}
+	for _, facility := range query.Facilities {
+		filter["visitingInfo.facilities."+string(facility)+".availability"] = castle.Available
+	}
+	// castles whose foundation period is unknown are never stored with one
+	if query.BuiltBefore != 0 {
+		filter["foundation.latest"] = bson.M{"$lt": query.BuiltBefore}
+	}
+	if query.BuiltAfter != 0 {
+		filter["foundation.earliest"] = bson.M{"$gte": query.BuiltAfter}
+	}
+	if b := query.BoundingBox; b != nil {
+		filter["location"] = bson.M{
+			"$geoWithin": bson.M{
+				"$geometry": bson.M{
+					"type": "Polygon",
+					"coordinates": bson.A{bson.A{
+						bson.A{b.MinLongitude, b.MinLatitude},
+						bson.A{b.MaxLongitude, b.MinLatitude},
+						bson.A{b.MaxLongitude, b.MaxLatitude},
+						bson.A{b.MinLongitude, b.MaxLatitude},
+						bson.A{b.MinLongitude, b.MinLatitude},
+					}},
+				},
+			},
+		}
+	}
+	return filter
+}
+
+// statesFilterOf matches the states by name, and the ones given as ISO
+// 3166-2 codes, like ie-ky, also by the code they were resolved into.
+func statesFilterOf(states []string) bson.M {
+	names := make([]string, 0, len(states))
+	var codes []string
+	for _, state := range states {
+		names = append(names, strings.ToLower(state))
+		if castle.IsSubdivisionCode(state) {
+			codes = append(codes, strings.ToUpper(state))
+		}
+	}
+	if len(codes) == 0 {
+		return bson.M{"state": bson.M{"$in": names}}
+	}
+	return bson.M{"$or": bson.A{
+		bson.M{"state": bson.M{"$in": names}},
+		bson.M{"stateCode": bson.M{"$in": codes}},
+	}}
+}
+
+// locationOf returns the castle location as a GeoJSON point, which is what
+// geospatial queries work with.
+func locationOf(c castle.Model) (bson.M, bool) {
+	location, ok := c.Location()
+	if !ok {
+		return nil, false
+	}
+	return bson.M{
+		"type":        "Point",
+		"coordinates": bson.A{location.Longitude, location.Latitude},
+	}, true
+}
@@ -0,0 +1,85 @@
+package db
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/buarki/find-castles/castle"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func AddIdentitiesIndexes(ctx context.Context, collection *mongo.Collection) error {
+	_, err := collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "source", Value: 1},
+			{Key: "castleId", Value: 1},
+		},
+		Options: options.Index().SetUnique(true),
+	})
+	return err
+}
+
+// SaveIdentityAssertion keeps a single assertion per source and castle. As a
+// source lists a single castle, asserting it is the same as a castle drops
+// the ones saying it is the same as others.
+func SaveIdentityAssertion(ctx context.Context, collection *mongo.Collection, assertion castle.IdentityAssertion) error {
+	if err := assertion.Validate(); err != nil {
+		return err
+	}
+	if assertion.Kind == castle.SameAs {
+		_, err := collection.DeleteMany(ctx, bson.M{
+			"source":   assertion.Source,
+			"kind":     castle.SameAs,
+			"castleId": bson.M{"$ne": assertion.CastleID},
+		})
+		if err != nil {
+			return fmt.Errorf("failed to drop previous identity of source [%s], got %v", assertion.Source, err)
+		}
+	}
+	filter := bson.M{"source": assertion.Source, "castleId": assertion.CastleID}
+	_, err := collection.ReplaceOne(ctx, filter, assertion, options.Replace().SetUpsert(true))
+	if err != nil {
+		return fmt.Errorf("failed to save identity assertion of source [%s], got %v", assertion.Source, err)
+	}
+	return nil
+}
+
+// LoadIdentities returns what was asserted about the sources of the given castles.
+func LoadIdentities(ctx context.Context, collection *mongo.Collection, castles []castle.Model) (castle.Identities, error) {
+	sourceSet := make(map[string]bool)
+	for _, c := range castles {
+		for _, source := range c.Sources {
+			sourceSet[source] = true
+		}
+	}
+	if len(sourceSet) == 0 {
+		return castle.NewIdentities(nil), nil
+	}
+
+	cursor, err := collection.Find(ctx, bson.M{"source": bson.M{"$in": setToSlice(sourceSet)}})
+	if err != nil {
+		return castle.Identities{}, fmt.Errorf("failed to find identity assertions, got %v", err)
+	}
+	defer cursor.Close(ctx)
+
+	var assertions []castle.IdentityAssertion
+	if err := cursor.All(ctx, &assertions); err != nil {
+		return castle.Identities{}, fmt.Errorf("failed to decode identity assertions, got %v", err)
+	}
+	return castle.NewIdentities(assertions), nil
+}
+
+// DeleteCastle is meant for castles merged into others, castles gone from
+// their sources are tombstoned instead, see TrackUnseenCastles.
+func DeleteCastle(ctx context.Context, collection *mongo.Collection, id string) error {
+	result, err := collection.DeleteOne(ctx, bson.M{"id": id})
+	if err != nil {
+		return fmt.Errorf("failed to delete castle [%s], got %v", id, err)
+	}
+	if result.DeletedCount == 0 {
+		return ErrCastleNotFound
+	}
+	return nil
+}
@@ -0,0 +1,24 @@
+package db
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/buarki/find-castles/castle"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func ListCastles(ctx context.Context, collection *mongo.Collection) ([]castle.Model, error) {
+	cursor, err := collection.Find(ctx, bson.M{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list castles, got %v", err)
+	}
+	defer cursor.Close(ctx)
+
+	var castles []castle.Model
+	if err := cursor.All(ctx, &castles); err != nil {
+		return nil, fmt.Errorf("failed to decode castles, got %v", err)
+	}
+	return castles, nil
+}
@@ -0,0 +1,567 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"time"
+
+	"github.com/buarki/find-castles/castle"
+	"github.com/buarki/find-castles/geocoding"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// CurrentSchemaVersion is the version of the castles documents written by
+// SaveCastles. Changing the shape of stored castles requires bumping it and
+// adding a migration that brings older documents to it.
+const CurrentSchemaVersion = 14
+
+var (
+	// facilities were booleans before version 9 and can't be decoded into
+	// castles anymore, so migrations needing them must read them on their own
+	withoutBooleanFacilities = bson.M{"visitingInfo.facilities": 0}
+)
+
+const (
+	// countryAndNameIndex was the unique key of castles before they had IDs.
+	countryAndNameIndex = "country_1_name_1"
+	// webNameIndex wasn't unique before web names were allocated.
+	webNameIndexName = "webName_1"
+	// textIndexName is the single text index castles are searched with.
+	textIndexName = "castles_text"
+)
+
+type Migration struct {
+	Version     int
+	Description string
+	// Up must be idempotent, as a migration that failed midway runs again on
+	// the next attempt. With dryRun it must only count the affected documents.
+	Up func(ctx context.Context, castles *mongo.Collection, dryRun bool) (int64, error)
+	// Recompute is set on migrations deriving fields with the rules of the
+	// current code, like the name normalization or the bundled gazetteer,
+	// instead of a fixed transformation. Replaying them on a lagging database
+	// applies the rules of today, as saving its castles again would, and
+	// Recompute runs them again on every castle whenever the rules change.
+	Recompute func(c castle.Model) bson.M
+}
+
+// recomputeMigration brings castles older than the version to it with the
+// fields recomputed with the current rules.
+func recomputeMigration(version int, description string, recompute func(c castle.Model) bson.M) Migration {
+	return Migration{
+		Version:     version,
+		Description: description,
+		Up: func(ctx context.Context, castles *mongo.Collection, dryRun bool) (int64, error) {
+			return updateEachCastle(ctx, castles, dryRun,
+				bson.M{"schemaVersion": bson.M{"$lt": version}},
+				func(c castle.Model) bson.M {
+					fields := recompute(c)
+					fields["schemaVersion"] = version
+					return fields
+				},
+			)
+		},
+		Recompute: recompute,
+	}
+}
+
+type MigrationResult struct {
+	Version     int
+	Description string
+	Affected    int64
+	DryRun      bool
+}
+
+type appliedMigration struct {
+	Version     int       `bson:"_id"`
+	Description string    `bson:"description"`
+	Affected    int64     `bson:"affected"`
+	AppliedAt   time.Time `bson:"appliedAt"`
+}
+
+// migrations must be kept ordered by version, and once released a migration
+// must never be changed, only followed by new ones. Recompute migrations are
+// the exception by design: they follow the rules they call, and the rules
+// are changed instead of them.
+var migrations = []Migration{
+	{
+		Version:     1,
+		Description: "set schemaVersion on castles saved before it existed",
+		Up: func(ctx context.Context, castles *mongo.Collection, dryRun bool) (int64, error) {
+			return updateOrCount(ctx, castles, dryRun,
+				bson.M{"schemaVersion": bson.M{"$exists": false}},
+				bson.M{"$set": bson.M{"schemaVersion": 1}},
+			)
+		},
+	},
+	recomputeMigration(2, "add blocking keys used to find candidates of the same castle",
+		func(c castle.Model) bson.M {
+			return bson.M{"blockingKeys": c.BlockingKeys()}
+		},
+	),
+	recomputeMigration(3, "add GeoJSON location parsed from coordinates",
+		func(c castle.Model) bson.M {
+			fields := bson.M{}
+			if location, ok := locationOf(c); ok {
+				fields["location"] = location
+			}
+			return fields
+		},
+	),
+	{
+		Version:     4,
+		Description: "give castles stable IDs and stop keying them by country and name",
+		Up: func(ctx context.Context, castles *mongo.Collection, dryRun bool) (int64, error) {
+			if !dryRun {
+				if err := dropIndexIfExists(ctx, castles, countryAndNameIndex); err != nil {
+					return 0, err
+				}
+			}
+			// the stored ObjectID is already unique, so it becomes the castle ID
+			return updateOrCount(ctx, castles, dryRun,
+				bson.M{"schemaVersion": bson.M{"$lt": 4}},
+				mongo.Pipeline{
+					{{Key: "$set", Value: bson.M{
+						"id":            bson.M{"$ifNull": bson.A{"$id", bson.M{"$toString": "$_id"}}},
+						"schemaVersion": 4,
+					}}},
+				},
+			)
+		},
+	},
+	recomputeMigration(5, "recompute names and keys with the name normalization rules",
+		func(c castle.Model) bson.M {
+			c.Name = c.FilteredName()
+			fields := renamedCastleFields(c)
+			fields["name"] = c.Name
+			fields["matchingTags"] = c.GetMatchingTags()
+			fields["blockingKeys"] = c.BlockingKeys()
+			return fields
+		},
+	),
+	recomputeMigration(6, "recompute web names transliterating every latin, cyrillic and greek letter",
+		renamedCastleFields,
+	),
+	{
+		Version:     7,
+		Description: "give castles sharing a web name their own one, so it can be unique",
+		Up: func(ctx context.Context, castles *mongo.Collection, dryRun bool) (int64, error) {
+			if !dryRun {
+				// recreated as unique by AddIndexes
+				if err := dropIndexIfExists(ctx, castles, webNameIndexName); err != nil {
+					return 0, err
+				}
+			}
+			reallocated, err := reallocateDuplicatedWebNames(ctx, castles, dryRun)
+			if err != nil {
+				return 0, err
+			}
+			if _, err := updateOrCount(ctx, castles, dryRun,
+				bson.M{"schemaVersion": bson.M{"$lt": 7}},
+				bson.M{"$set": bson.M{"schemaVersion": 7}},
+			); err != nil {
+				return 0, err
+			}
+			return reallocated, nil
+		},
+	},
+	recomputeMigration(8, "add foundation years parsed from the foundation period",
+		func(c castle.Model) bson.M {
+			fields := bson.M{}
+			if foundation, ok := c.Foundation(); ok {
+				fields["foundation"] = foundation
+			}
+			return fields
+		},
+	),
+	{
+		Version:     9,
+		Description: "turn facilities into yes, no or unknown, as a false one could mean both",
+		Up: func(ctx context.Context, castles *mongo.Collection, dryRun bool) (int64, error) {
+			converted, err := updateOrCount(ctx, castles, dryRun,
+				bson.M{"schemaVersion": bson.M{"$lt": 9}, "visitingInfo.facilities": bson.M{"$type": "object"}},
+				mongo.Pipeline{
+					{{Key: "$set", Value: bson.M{
+						"visitingInfo.facilities": facilitiesFromBooleans(),
+						"schemaVersion":           9,
+					}}},
+				},
+			)
+			if err != nil {
+				return 0, err
+			}
+			if _, err := updateOrCount(ctx, castles, dryRun,
+				bson.M{"schemaVersion": bson.M{"$lt": 9}},
+				bson.M{"$set": bson.M{"schemaVersion": 9}},
+			); err != nil {
+				return 0, err
+			}
+			return converted, nil
+		},
+	},
+	recomputeMigration(10, "resolve states into ISO 3166-2 subdivisions, keeping the text of the source as label",
+		func(c castle.Model) bson.M {
+			fields := bson.M{}
+			if c.State != "" && c.ResolveState() {
+				fields["state"] = c.State
+				fields["stateCode"] = c.StateCode
+				fields["stateLabel"] = c.StateLabel
+			}
+			return fields
+		},
+	),
+	recomputeMigration(11, "fill missing states, cities and coordinates by offline geocoding, marking them as derived",
+		func(c castle.Model) bson.M {
+			fields := bson.M{}
+			filled, ok := geocoding.Fill(c)
+			if !ok {
+				return fields
+			}
+			fields["derived"] = filled.Derived
+			if filled.IsDerived(castle.DerivedState) {
+				fields["state"] = filled.State
+				fields["stateCode"] = filled.StateCode
+			}
+			if filled.IsDerived(castle.DerivedCity) {
+				fields["city"] = filled.City
+			}
+			if filled.IsDerived(castle.DerivedCoordinates) {
+				fields["coordinates"] = filled.Coordinates
+				if location, ok := locationOf(filled); ok {
+					fields["location"] = location
+				}
+			}
+			return fields
+		},
+	),
+	recomputeMigration(12, "correct coordinates swapped or with a sign flipped, checking them against the boundaries of the country",
+		func(c castle.Model) bson.M {
+			fields := bson.M{}
+			if c.ValidateCoordinates() == castle.CoordinatesCorrected {
+				fields["coordinates"] = c.Coordinates
+				if location, ok := locationOf(c); ok {
+					fields["location"] = location
+				}
+			}
+			return fields
+		},
+	),
+	{
+		Version:     13,
+		Description: "search localized and alternate names, and stop indexing the description castles never had",
+		Up: func(ctx context.Context, castles *mongo.Collection, dryRun bool) (int64, error) {
+			if !dryRun {
+				// recreated with the new fields by AddIndexes
+				if err := dropIndexIfExists(ctx, castles, textIndexName); err != nil {
+					return 0, err
+				}
+			}
+			return updateOrCount(ctx, castles, dryRun,
+				bson.M{"schemaVersion": bson.M{"$lt": 13}},
+				bson.M{"$set": bson.M{"schemaVersion": 13}},
+			)
+		},
+	},
+	recomputeMigration(14, "add names transliterated with the rules of their languages, searched along with the others",
+		func(c castle.Model) bson.M {
+			return bson.M{"searchNames": c.SearchNames()}
+		},
+	),
+}
+
+// Recompute runs every recompute migration again on all castles, in order,
+// for when the rules they follow changed. It returns the documents each one
+// changed, or would change with dryRun.
+func Recompute(ctx context.Context, castles *mongo.Collection, dryRun bool) ([]MigrationResult, error) {
+	var results []MigrationResult
+	for _, m := range migrations {
+		if m.Recompute == nil {
+			continue
+		}
+		affected, err := updateEachCastle(ctx, castles, dryRun, bson.M{}, m.Recompute)
+		if err != nil {
+			return results, fmt.Errorf("failed to recompute migration [%d] (%s), got %v", m.Version, m.Description, err)
+		}
+		results = append(results, MigrationResult{
+			Version:     m.Version,
+			Description: m.Description,
+			Affected:    affected,
+			DryRun:      dryRun,
+		})
+	}
+	return results, nil
+}
+
+// PendingMigrations returns, in order, the migrations not applied yet.
+func PendingMigrations(ctx context.Context, migrationsCollection *mongo.Collection) ([]Migration, error) {
+	cursor, err := migrationsCollection.Find(ctx, bson.M{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list applied migrations, got %v", err)
+	}
+	defer cursor.Close(ctx)
+
+	var applied []appliedMigration
+	if err := cursor.All(ctx, &applied); err != nil {
+		return nil, fmt.Errorf("failed to decode applied migrations, got %v", err)
+	}
+	appliedVersions := make(map[int]bool, len(applied))
+	for _, a := range applied {
+		appliedVersions[a.Version] = true
+	}
+
+	var pending []Migration
+	for _, m := range migrations {
+		if !appliedVersions[m.Version] {
+			pending = append(pending, m)
+		}
+	}
+	return pending, nil
+}
+
+// RecordMigrationsOfEmptyCastles records every pending migration as applied
+// when there are no castles yet, as castles saved from now on are already on
+// CurrentSchemaVersion. It tells whether the collection was empty.
+func RecordMigrationsOfEmptyCastles(ctx context.Context, castles, migrationsCollection *mongo.Collection) (bool, error) {
+	count, err := castles.CountDocuments(ctx, bson.M{}, options.Count().SetLimit(1))
+	if err != nil {
+		return false, fmt.Errorf("failed to count castles, got %v", err)
+	}
+	if count > 0 {
+		return false, nil
+	}
+	pending, err := PendingMigrations(ctx, migrationsCollection)
+	if err != nil {
+		return true, err
+	}
+	for _, m := range pending {
+		if err := recordMigration(ctx, migrationsCollection, m, 0); err != nil {
+			return true, err
+		}
+	}
+	return true, nil
+}
+
+// Migrate applies the pending migrations in order, recording each one as
+// soon as it succeeds. With dryRun nothing is written.
+func Migrate(ctx context.Context, castles, migrationsCollection *mongo.Collection, dryRun bool) ([]MigrationResult, error) {
+	if !dryRun {
+		if _, err := RecordMigrationsOfEmptyCastles(ctx, castles, migrationsCollection); err != nil {
+			return nil, err
+		}
+	}
+	pending, err := PendingMigrations(ctx, migrationsCollection)
+	if err != nil {
+		return nil, err
+	}
+
+	var results []MigrationResult
+	for _, m := range pending {
+		affected, err := m.Up(ctx, castles, dryRun)
+		if err != nil {
+			return results, fmt.Errorf("failed to apply migration [%d] (%s), got %v", m.Version, m.Description, err)
+		}
+		results = append(results, MigrationResult{
+			Version:     m.Version,
+			Description: m.Description,
+			Affected:    affected,
+			DryRun:      dryRun,
+		})
+		if dryRun {
+			continue
+		}
+		if err := recordMigration(ctx, migrationsCollection, m, affected); err != nil {
+			return results, err
+		}
+		slog.Info("migration applied", "version", m.Version, "description", m.Description, "affected", affected)
+	}
+	return results, nil
+}
+
+func recordMigration(ctx context.Context, migrationsCollection *mongo.Collection, m Migration, affected int64) error {
+	_, err := migrationsCollection.InsertOne(ctx, appliedMigration{
+		Version:     m.Version,
+		Description: m.Description,
+		Affected:    affected,
+		AppliedAt:   time.Now().UTC(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to record migration [%d], got %v", m.Version, err)
+	}
+	return nil
+}
+
+// renamedCastleFields returns the web name derived from the name of the
+// castle, keeping the stored one as an alias when it changed, so links to it
+// still find the castle.
+func renamedCastleFields(c castle.Model) bson.M {
+	webName, err := c.BaseWebName()
+	if err != nil {
+		// names that can't be turned into web names are left as they are
+		return bson.M{}
+	}
+	aliases := c.WebNameAliases
+	if c.AllocatedWebName != "" && c.AllocatedWebName != webName && !slices.Contains(aliases, c.AllocatedWebName) {
+		aliases = append(aliases, c.AllocatedWebName)
+	}
+	if aliases == nil {
+		aliases = []string{}
+	}
+	webNameFor, err := c.NameSlug()
+	if err != nil {
+		return bson.M{}
+	}
+	return bson.M{
+		"webName":        webName,
+		"webNameFor":     webNameFor,
+		"webNameAliases": aliases,
+	}
+}
+
+// updateEachCastle sets on every castle matching the filter the fields
+// computed from it, for when the new value can't be expressed as an update
+// operator.
+func updateEachCastle(ctx context.Context, castles *mongo.Collection, dryRun bool, filter bson.M, fieldsToSet func(c castle.Model) bson.M) (int64, error) {
+	if dryRun {
+		return castles.CountDocuments(ctx, filter)
+	}
+	cursor, err := castles.Find(ctx, filter, options.Find().SetProjection(withoutBooleanFacilities))
+	if err != nil {
+		return 0, err
+	}
+	defer cursor.Close(ctx)
+
+	var operations []mongo.WriteModel
+	for cursor.Next(ctx) {
+		var doc struct {
+			ID           any `bson:"_id"`
+			castle.Model `bson:",inline"`
+		}
+		if err := cursor.Decode(&doc); err != nil {
+			return 0, fmt.Errorf("failed to decode castle, got %v", err)
+		}
+		operations = append(operations, mongo.NewUpdateOneModel().
+			SetFilter(bson.M{"_id": doc.ID}).
+			SetUpdate(bson.M{"$set": fieldsToSet(doc.Model)}))
+	}
+	if err := cursor.Err(); err != nil {
+		return 0, err
+	}
+	if len(operations) == 0 {
+		return 0, nil
+	}
+	result, err := castles.BulkWrite(ctx, operations)
+	if err != nil {
+		return 0, err
+	}
+	return result.ModifiedCount, nil
+}
+
+// facilitiesFromBooleans turns the facilities of a castle saved before
+// version 9 into the ones of today, leaving converted ones as they are. Only
+// available facilities are kept, and their source is only known for castles
+// having a single one.
+func facilitiesFromBooleans() bson.M {
+	source := bson.M{"$cond": bson.A{
+		bson.M{"$eq": bson.A{bson.M{"$size": bson.M{"$ifNull": bson.A{"$sources", bson.A{}}}}, 1}},
+		bson.M{"$arrayElemAt": bson.A{"$sources", 0}},
+		"",
+	}}
+	return bson.M{"$arrayToObject": bson.M{"$map": bson.M{
+		"input": bson.M{"$filter": bson.M{
+			"input": bson.M{"$objectToArray": "$visitingInfo.facilities"},
+			"cond": bson.M{"$or": bson.A{
+				bson.M{"$eq": bson.A{"$$this.v", true}},
+				bson.M{"$eq": bson.A{bson.M{"$type": "$$this.v"}, "object"}},
+			}},
+		}},
+		"in": bson.M{
+			"k": "$$this.k",
+			"v": bson.M{"$cond": bson.A{
+				bson.M{"$eq": bson.A{"$$this.v", true}},
+				bson.M{"availability": castle.Available, "source": source},
+				"$$this.v",
+			}},
+		},
+	}}}
+}
+
+// reallocateDuplicatedWebNames keeps each web name on the oldest castle
+// having it and allocates new ones to the others.
+func reallocateDuplicatedWebNames(ctx context.Context, castles *mongo.Collection, dryRun bool) (int64, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "_id", Value: 1}}).SetProjection(withoutBooleanFacilities)
+	cursor, err := castles.Find(ctx, bson.M{}, opts)
+	if err != nil {
+		return 0, err
+	}
+	defer cursor.Close(ctx)
+
+	var all []castle.Model
+	if err := cursor.All(ctx, &all); err != nil {
+		return 0, fmt.Errorf("failed to decode castles, got %v", err)
+	}
+
+	taken := make(map[string]bool)
+	for _, c := range all {
+		taken[c.AllocatedWebName] = true
+		for _, alias := range c.WebNameAliases {
+			taken[alias] = true
+		}
+	}
+
+	kept := make(map[string]bool)
+	var operations []mongo.WriteModel
+	for _, c := range all {
+		if !kept[c.AllocatedWebName] {
+			kept[c.AllocatedWebName] = true
+			continue
+		}
+		c.AllocatedWebName = ""
+		webName, err := c.AllocateWebName(func(webName string) bool { return taken[webName] })
+		if err != nil {
+			return 0, fmt.Errorf("failed to allocate web name of castle [%s], got %v", c.ID, err)
+		}
+		taken[webName] = true
+		operations = append(operations, mongo.NewUpdateOneModel().
+			SetFilter(bson.M{"id": c.ID}).
+			SetUpdate(bson.M{"$set": bson.M{"webName": webName}}))
+	}
+	if dryRun || len(operations) == 0 {
+		return int64(len(operations)), nil
+	}
+	result, err := castles.BulkWrite(ctx, operations)
+	if err != nil {
+		return 0, err
+	}
+	return result.ModifiedCount, nil
+}
+
+// updateOrCount accepts as update both operators and aggregation pipelines.
+func updateOrCount(ctx context.Context, collection *mongo.Collection, dryRun bool, filter bson.M, update any) (int64, error) {
+	if dryRun {
+		return collection.CountDocuments(ctx, filter)
+	}
+	result, err := collection.UpdateMany(ctx, filter, update)
+	if err != nil {
+		return 0, err
+	}
+	return result.ModifiedCount, nil
+}
+
+func dropIndexIfExists(ctx context.Context, collection *mongo.Collection, name string) error {
+	specs, err := collection.Indexes().ListSpecifications(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list indexes, got %v", err)
+	}
+	for _, spec := range specs {
+		if spec.Name == name {
+			if _, err := collection.Indexes().DropOne(ctx, name); err != nil {
+				return fmt.Errorf("failed to drop index [%s], got %v", name, err)
+			}
+			return nil
+		}
+	}
+	return nil
+}
@@ -0,0 +1,199 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/buarki/find-castles/castle"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var (
+	ErrOverrideNotFound      = errors.New("override not found")
+	ErrMissingOverrideCastle = errors.New("override must have the ID of its castle")
+)
+
+const (
+	// overridesWebNameIndex was the unique key of overrides before they were
+	// kept by castle ID, which broke them whenever a castle got another web name.
+	overridesWebNameIndex = "webName_1_field_1"
+)
+
+// AddOverridesIndexes keeps a single override per castle ID and field. The
+// overrides saved by web name that couldn't be assigned to a castle yet are
+// left out of it, see AssignOverridesToCastles.
+func AddOverridesIndexes(ctx context.Context, collection *mongo.Collection) error {
+	_, err := collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "castleId", Value: 1},
+			{Key: "field", Value: 1},
+		},
+		Options: options.Index().
+			SetUnique(true).
+			SetPartialFilterExpression(bson.M{"castleId": bson.M{"$exists": true}}),
+	})
+	if err != nil {
+		return err
+	}
+	return dropIndexIfExists(ctx, collection, overridesWebNameIndex)
+}
+
+// AssignOverridesToCastles gives the overrides saved by web name the ID of
+// their castle, found by its current web name or by the ones it had before.
+// Overrides of castles not found are left as they are and logged. It returns
+// the number of assigned overrides.
+func AssignOverridesToCastles(ctx context.Context, castles, overrides *mongo.Collection) (int64, error) {
+	unassigned := bson.M{"castleId": bson.M{"$exists": false}}
+	cursor, err := overrides.Find(ctx, unassigned)
+	if err != nil {
+		return 0, fmt.Errorf("failed to find overrides without castle, got %v", err)
+	}
+	var found []castle.Override
+	if err := cursor.All(ctx, &found); err != nil {
+		return 0, fmt.Errorf("failed to decode overrides, got %v", err)
+	}
+
+	var assigned int64
+	for _, o := range found {
+		c, err := GetCastleByWebNameOrAlias(ctx, castles, o.WebName)
+		if errors.Is(err, ErrCastleNotFound) {
+			slog.Warn("override of castle not found left unassigned", "castle", o.WebName, "field", o.Field)
+			continue
+		}
+		if err != nil {
+			return assigned, err
+		}
+		filter := bson.M{"castleId": bson.M{"$exists": false}, "webName": o.WebName, "field": o.Field}
+		_, err = overrides.UpdateOne(ctx, filter, bson.M{"$set": bson.M{"castleId": c.ID}})
+		if mongo.IsDuplicateKeyError(err) {
+			slog.Warn("override of castle already overridden under another web name left unassigned", "castle", o.WebName, "field", o.Field)
+			continue
+		}
+		if err != nil {
+			return assigned, fmt.Errorf("failed to assign override of field [%s] of castle [%s], got %v", o.Field, o.WebName, err)
+		}
+		assigned++
+	}
+	return assigned, nil
+}
+
+// SaveOverride keeps a single override per castle field, replacing any previous one.
+func SaveOverride(ctx context.Context, collection *mongo.Collection, override castle.Override) error {
+	if override.CastleID == "" {
+		return ErrMissingOverrideCastle
+	}
+	if err := override.Validate(); err != nil {
+		return err
+	}
+	filter := bson.M{"castleId": override.CastleID, "field": override.Field}
+	_, err := collection.ReplaceOne(ctx, filter, override, options.Replace().SetUpsert(true))
+	if err != nil {
+		return fmt.Errorf("failed to save override of field [%s] of castle [%s], got %v", override.Field, override.WebName, err)
+	}
+	return nil
+}
+
+// ListOverrides returns the overrides of the castle with the given ID,
+// or all of them when it is empty.
+func ListOverrides(ctx context.Context, collection *mongo.Collection, castleID string) ([]castle.Override, error) {
+	filter := bson.M{}
+	if castleID != "" {
+		filter["castleId"] = castleID
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "webName", Value: 1}, {Key: "field", Value: 1}})
+	cursor, err := collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list overrides, got %v", err)
+	}
+	defer cursor.Close(ctx)
+
+	var overrides []castle.Override
+	if err := cursor.All(ctx, &overrides); err != nil {
+		return nil, fmt.Errorf("failed to decode overrides, got %v", err)
+	}
+	return overrides, nil
+}
+
+// FindOverrides returns the overrides of the given castles by castle ID.
+func FindOverrides(ctx context.Context, collection *mongo.Collection, castleIDs []string) (map[string][]castle.Override, error) {
+	cursor, err := collection.Find(ctx, bson.M{"castleId": bson.M{"$in": castleIDs}})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find overrides, got %v", err)
+	}
+	defer cursor.Close(ctx)
+
+	var overrides []castle.Override
+	if err := cursor.All(ctx, &overrides); err != nil {
+		return nil, fmt.Errorf("failed to decode overrides, got %v", err)
+	}
+	byCastleID := make(map[string][]castle.Override)
+	for _, o := range overrides {
+		byCastleID[o.CastleID] = append(byCastleID[o.CastleID], o)
+	}
+	return byCastleID, nil
+}
+
+// MoveOverrides gives the overrides of a castle to another one, dropping the
+// ones of fields the other castle already overrides. Moving them again moves
+// nothing.
+func MoveOverrides(ctx context.Context, collection *mongo.Collection, fromID, intoID string) error {
+	kept, err := ListOverrides(ctx, collection, intoID)
+	if err != nil {
+		return err
+	}
+	fields := make([]string, 0, len(kept))
+	for _, o := range kept {
+		fields = append(fields, o.Field)
+	}
+	if _, err := collection.DeleteMany(ctx, bson.M{"castleId": fromID, "field": bson.M{"$in": fields}}); err != nil {
+		return fmt.Errorf("failed to drop overrides of castle [%s] already on castle [%s], got %v", fromID, intoID, err)
+	}
+	if _, err := collection.UpdateMany(ctx, bson.M{"castleId": fromID}, bson.M{"$set": bson.M{"castleId": intoID}}); err != nil {
+		return fmt.Errorf("failed to move overrides of castle [%s] to castle [%s], got %v", fromID, intoID, err)
+	}
+	return nil
+}
+
+func RemoveOverride(ctx context.Context, collection *mongo.Collection, castleID, field string) error {
+	result, err := collection.DeleteOne(ctx, bson.M{"castleId": castleID, "field": field})
+	if err != nil {
+		return fmt.Errorf("failed to remove override of field [%s] of castle [%s], got %v", field, castleID, err)
+	}
+	if result.DeletedCount == 0 {
+		return ErrOverrideNotFound
+	}
+	return nil
+}
+
+// ApplySavedOverrides applies the overrides of a saved castle right away,
+// instead of waiting for the next enrichment to save it again.
+func ApplySavedOverrides(ctx context.Context, castles, overrides *mongo.Collection, castleID string) error {
+	c, err := GetCastleByID(ctx, castles, castleID)
+	if err != nil {
+		return err
+	}
+	found, err := FindOverrides(ctx, overrides, []string{castleID})
+	if err != nil {
+		return err
+	}
+	overridden, err := c.ApplyOverrides(found[castleID])
+	if err != nil {
+		return err
+	}
+	object, err := prepareObjectToSave(overridden)
+	if err != nil {
+		return err
+	}
+	update := bson.M{"$set": object}
+	if unset := clearedFields(object); len(unset) > 0 {
+		update["$unset"] = unset
+	}
+	if _, err := castles.UpdateOne(ctx, bson.M{"id": castleID}, update); err != nil {
+		return fmt.Errorf("failed to apply overrides to castle [%s], got %v", castleID, err)
+	}
+	return nil
+}
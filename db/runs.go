@@ -0,0 +1,62 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/buarki/find-castles/run"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var (
+	ErrRunNotFound = errors.New("run not found")
+)
+
+func AddRunsIndexes(ctx context.Context, collection *mongo.Collection) error {
+	_, err := collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "startedAt", Value: -1},
+		},
+	})
+	return err
+}
+
+func SaveRun(ctx context.Context, collection *mongo.Collection, record run.Record) error {
+	filter := bson.M{"_id": record.ID}
+	_, err := collection.ReplaceOne(ctx, filter, record, options.Replace().SetUpsert(true))
+	if err != nil {
+		return fmt.Errorf("failed to save run [%s], got %v", record.ID, err)
+	}
+	return nil
+}
+
+// ListRuns returns the most recent runs first.
+func ListRuns(ctx context.Context, collection *mongo.Collection, limit int64) ([]run.Record, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "startedAt", Value: -1}}).SetLimit(limit)
+	cursor, err := collection.Find(ctx, bson.M{}, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list runs, got %v", err)
+	}
+	defer cursor.Close(ctx)
+
+	var records []run.Record
+	if err := cursor.All(ctx, &records); err != nil {
+		return nil, fmt.Errorf("failed to decode runs, got %v", err)
+	}
+	return records, nil
+}
+
+func GetRun(ctx context.Context, collection *mongo.Collection, id string) (run.Record, error) {
+	var record run.Record
+	err := collection.FindOne(ctx, bson.M{"_id": id}).Decode(&record)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return run.Record{}, ErrRunNotFound
+		}
+		return run.Record{}, err
+	}
+	return record, nil
+}
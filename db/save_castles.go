@@ -5,54 +5,122 @@ import (
 	"fmt"
 	"log"
 	"strings"
+	"time"
 
 	"github.com/buarki/find-castles/castle"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 )
 
-func SaveCastles(ctx context.Context, collection *mongo.Collection, castles []castle.Model) error {
+var (
+	// fields prepareObjectToSave leaves out when empty, which curators can
+	// clear by overriding them
+	clearableFields = []string{
+		"alternateNames",
+		"localizedNames",
+		"state",
+		"city",
+		"district",
+		"foundationPeriod",
+		"foundation",
+		"propertyCondition",
+		"propertyConditionLabel",
+		"coordinates",
+		"location",
+	}
+)
+
+type SaveResult struct {
+	Inserted  int64
+	Updated   int64
+	Unchanged int64
+}
+
+func SaveCastles(ctx context.Context, collection *mongo.Collection, castles []castle.Model) (SaveResult, error) {
 	var operations []mongo.WriteModel
 
+	now := time.Now().UTC()
+	// web names allocated on this batch, not saved yet
+	allocated := make(map[string]bool)
 	for _, c := range castles {
+		// castles without an ID were never saved before
+		if c.ID == "" {
+			id, err := castle.NewID()
+			if err != nil {
+				return SaveResult{}, err
+			}
+			c.ID = id
+		}
+		if err := allocateWebName(ctx, collection, &c, allocated); err != nil {
+			return SaveResult{}, err
+		}
 		filter := bson.M{
-			"country": strings.ToLower(c.Country.String()),
-			"name":    strings.ToLower(c.FilteredName()),
+			"id": c.ID,
 		}
 		obj, err := prepareObjectToSave(c)
 		if err != nil {
-			return err
+			return SaveResult{}, err
 		}
 		update := bson.M{
 			"$set": obj,
 		}
+		if unset := clearedFields(obj); len(unset) > 0 {
+			update["$unset"] = unset
+		}
+		// only castles a source just listed are seen again, the ones saved
+		// by curators, like on merges, keep their status
+		if c.CurrentEnrichmentLink != "" {
+			obj["status"] = castle.Active
+			obj["missedRuns"] = 0
+			obj["lastSeenAt"] = now
+		} else {
+			update["$setOnInsert"] = bson.M{"status": castle.Active}
+		}
 		operation := mongo.NewUpdateOneModel().SetFilter(filter).SetUpdate(update).SetUpsert(true)
 		operations = append(operations, operation)
 	}
 
+	var result SaveResult
 	if len(operations) > 0 {
-		_, err := collection.BulkWrite(ctx, operations)
+		bulkResult, err := collection.BulkWrite(ctx, operations)
 		if err != nil {
 			log.Printf("failed to upsert castles: %v", err)
-			return fmt.Errorf("failed to upsert [%d] castles, got %v", len(operations), err)
+			return SaveResult{}, fmt.Errorf("failed to upsert [%d] castles, got %v", len(operations), err)
 		} else {
 			log.Printf("successfully upserted [%d] castles", len(castles))
 		}
+		result.Inserted = bulkResult.UpsertedCount
+		result.Updated = bulkResult.ModifiedCount
+		result.Unchanged = bulkResult.MatchedCount - bulkResult.ModifiedCount
 	}
-	return nil
+	return result, nil
 }
 
 func prepareObjectToSave(c castle.Model) (bson.M, error) {
 	object := bson.M{
-		"name":         strings.ToLower(c.FilteredName()),
-		"sources":      c.Sources,
-		"country":      strings.ToLower(c.Country.String()),
-		"matchingTags": c.GetMatchingTags(),
-		"pictureURL":   c.PictureURL,
+		"id":            c.ID,
+		"name":          strings.ToLower(c.FilteredName()),
+		"sources":       c.Sources,
+		"country":       strings.ToLower(c.Country.String()),
+		"matchingTags":  c.GetMatchingTags(),
+		"blockingKeys":  c.BlockingKeys(),
+		"searchNames":   c.SearchNames(),
+		"pictureURL":    c.PictureURL,
+		"schemaVersion": CurrentSchemaVersion,
+	}
+	if len(c.LocalizedNames) > 0 {
+		object["localizedNames"] = c.LocalizedNames
+	}
+	if len(c.AlternateNames) > 0 {
+		object["alternateNames"] = c.AlternateNames
 	}
 	if c.State != "" {
 		object["state"] = c.State
 	}
+	// always set, so a state overridden with one not resolved loses the
+	// code and label of the state before
+	object["stateCode"] = c.StateCode
+	object["stateLabel"] = c.StateLabel
 	if c.City != "" {
 		object["city"] = c.City
 	}
@@ -62,12 +130,29 @@ func prepareObjectToSave(c castle.Model) (bson.M, error) {
 	if c.FoundationPeriod != "" {
 		object["foundationPeriod"] = c.FoundationPeriod
 	}
+	if foundation, ok := c.Foundation(); ok {
+		object["foundation"] = foundation
+	}
 	if c.PropertyCondition != "" {
 		object["propertyCondition"] = c.PropertyCondition
 	}
+	if c.PropertyConditionLabel != "" {
+		object["propertyConditionLabel"] = c.PropertyConditionLabel
+	}
 	if c.Coordinates != "" {
 		object["coordinates"] = c.Coordinates
 	}
+	if location, ok := locationOf(c); ok {
+		object["location"] = location
+	}
+	// always set, as values told by a source since are derived no more
+	object["derived"] = c.Derived
+	if c.Derived == nil {
+		object["derived"] = []string{}
+	}
+	if c.Address != nil {
+		object["address"] = c.Address
+	}
 	if c.Contact != nil {
 		object["contact"] = bson.M{
 			"phone": c.Contact.Phone,
@@ -75,24 +160,54 @@ func prepareObjectToSave(c castle.Model) (bson.M, error) {
 		}
 	}
 	if c.VisitingInfo != nil {
-		object["visitingInfo"] = bson.M{
+		visitingInfo := bson.M{
 			"workingHours": c.VisitingInfo.WorkingHours,
-			"facilities": bson.M{
-				"assistanceDogsAllowed": c.VisitingInfo.Facilities.AssistanceDogsAllowed,
-				"cafe":                  c.VisitingInfo.Facilities.Cafe,
-				"restrooms":             c.VisitingInfo.Facilities.Restrooms,
-				"giftshops":             c.VisitingInfo.Facilities.Giftshops,
-				"pinicArea":             c.VisitingInfo.Facilities.PinicArea,
-				"parking":               c.VisitingInfo.Facilities.Parking,
-				"exhibitions":           c.VisitingInfo.Facilities.Exhibitions,
-				"wheelchairSupport":     c.VisitingInfo.Facilities.WheelchairSupport,
-			},
+			"facilities":   facilitiesOf(*c.VisitingInfo),
+		}
+		if c.VisitingInfo.OpeningHours != nil {
+			visitingInfo["openingHours"] = c.VisitingInfo.OpeningHours
+		}
+		if c.VisitingInfo.Admission != nil {
+			visitingInfo["admission"] = c.VisitingInfo.Admission
 		}
+		object["visitingInfo"] = visitingInfo
 	}
 	webName, err := c.WebName()
 	if err != nil {
 		return nil, err
 	}
 	object["webName"] = webName
+	// web names are always the one of the current name, see Model.WebName
+	webNameFor, err := c.NameSlug()
+	if err != nil {
+		return nil, err
+	}
+	object["webNameFor"] = webNameFor
+	object["webNameAliases"] = c.WebNameAliases
+	if c.WebNameAliases == nil {
+		object["webNameAliases"] = []string{}
+	}
 	return object, nil
 }
+
+// clearedFields are the fields left out of the object to save for being
+// empty, which must be unset for castles saved with them before, like a
+// city a curator overrode with an empty value.
+func clearedFields(object bson.M) bson.M {
+	unset := bson.M{}
+	for _, field := range clearableFields {
+		if _, found := object[field]; !found {
+			unset[field] = ""
+		}
+	}
+	return unset
+}
+
+// facilitiesOf only has the facilities something is known about, an empty
+// document when nothing is.
+func facilitiesOf(vi castle.VisitingInfo) castle.Facilities {
+	if vi.Facilities == nil {
+		return castle.Facilities{}
+	}
+	return vi.Facilities
+}
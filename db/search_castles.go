@@ -0,0 +1,152 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/buarki/find-castles/castle"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+)
+
+type SearchFilters struct {
+	Countries  []castle.Country
+	Conditions []castle.PropertyCondition
+}
+
+// Page is 1-based.
+type Page struct {
+	Number int64 `json:"number"`
+	Size   int64 `json:"size"`
+}
+
+// Normalized starts on the first page and keeps the size between 1 and the
+// max one, using the default one when it is not given.
+func (p Page) Normalized() Page {
+	if p.Number < 1 {
+		p.Number = 1
+	}
+	if p.Size < 1 {
+		p.Size = defaultPageSize
+	}
+	if p.Size > maxPageSize {
+		p.Size = maxPageSize
+	}
+	return p
+}
+
+type FacetCount struct {
+	Value string `bson:"_id" json:"value"`
+	Count int64  `bson:"count" json:"count"`
+}
+
+type SearchFacets struct {
+	Countries  []FacetCount `json:"countries"`
+	Conditions []FacetCount `json:"conditions"`
+}
+
+type SearchResult struct {
+	Castles []castle.Model `json:"castles"`
+	Total   int64          `json:"total"`
+	Page    Page           `json:"page"`
+	Facets  SearchFacets   `json:"facets"`
+}
+
+// SearchCastles returns the castles matching the query ranked by relevance,
+// or sorted by name when the query is empty. Removed castles are never
+// returned. The count of each facet ignores the filter of its own dimension,
+// so it tells how many results selecting another value would bring.
+func SearchCastles(ctx context.Context, collection *mongo.Collection, query string, filters SearchFilters, page Page) (SearchResult, error) {
+	page = page.Normalized()
+	query = strings.TrimSpace(query)
+
+	match := bson.M{
+		"status": bson.M{"$ne": castle.Removed},
+	}
+	sort := bson.D{{Key: "name", Value: 1}}
+	if query != "" {
+		match["$text"] = bson.M{"$search": query}
+		sort = bson.D{{Key: "score", Value: -1}, {Key: "name", Value: 1}}
+	}
+
+	countriesFilter := bson.M{}
+	if len(filters.Countries) > 0 {
+		countriesFilter["country"] = bson.M{"$in": filters.Countries}
+	}
+	conditionsFilter := bson.M{}
+	if len(filters.Conditions) > 0 {
+		conditionsFilter["propertyCondition"] = bson.M{"$in": castle.CoveredConditions(filters.Conditions)}
+	}
+	allFilters := bson.M{}
+	for k, v := range countriesFilter {
+		allFilters[k] = v
+	}
+	for k, v := range conditionsFilter {
+		allFilters[k] = v
+	}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: match}},
+	}
+	if query != "" {
+		pipeline = append(pipeline, bson.D{{Key: "$addFields", Value: bson.M{"score": bson.M{"$meta": "textScore"}}}})
+	}
+	pipeline = append(pipeline, bson.D{{Key: "$facet", Value: bson.M{
+		"results": bson.A{
+			bson.M{"$match": allFilters},
+			bson.M{"$sort": sort},
+			bson.M{"$skip": (page.Number - 1) * page.Size},
+			bson.M{"$limit": page.Size},
+		},
+		"total": bson.A{
+			bson.M{"$match": allFilters},
+			bson.M{"$count": "count"},
+		},
+		"countries": bson.A{
+			bson.M{"$match": conditionsFilter},
+			bson.M{"$sortByCount": "$country"},
+		},
+		"conditions": bson.A{
+			bson.M{"$match": countriesFilter},
+			bson.M{"$sortByCount": "$propertyCondition"},
+		},
+	}}})
+
+	cursor, err := collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return SearchResult{}, fmt.Errorf("failed to search castles with query [%s], got %v", query, err)
+	}
+	defer cursor.Close(ctx)
+
+	var facets []struct {
+		Results []castle.Model `bson:"results"`
+		Total   []struct {
+			Count int64 `bson:"count"`
+		} `bson:"total"`
+		Countries  []FacetCount `bson:"countries"`
+		Conditions []FacetCount `bson:"conditions"`
+	}
+	if err := cursor.All(ctx, &facets); err != nil {
+		return SearchResult{}, fmt.Errorf("failed to decode search results, got %v", err)
+	}
+
+	result := SearchResult{Page: page}
+	if len(facets) == 0 {
+		return result, nil
+	}
+	result.Castles = facets[0].Results
+	if len(facets[0].Total) > 0 {
+		result.Total = facets[0].Total[0].Count
+	}
+	result.Facets = SearchFacets{
+		Countries:  facets[0].Countries,
+		Conditions: facets[0].Conditions,
+	}
+	return result, nil
+}
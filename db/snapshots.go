@@ -0,0 +1,91 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/buarki/find-castles/castle"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	snapshotsRetention = 90 * 24 * time.Hour
+)
+
+var (
+	ErrSnapshotNotFound = errors.New("snapshot not found")
+)
+
+func AddSnapshotsIndexes(ctx context.Context, collection *mongo.Collection) error {
+	retentionInSeconds := int32(snapshotsRetention.Seconds())
+	_, err := collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				{Key: "runId", Value: 1},
+			},
+		},
+		{
+			Keys: bson.D{
+				{Key: "createdAt", Value: 1},
+			},
+			Options: &options.IndexOptions{
+				ExpireAfterSeconds: &retentionInSeconds,
+			},
+		},
+	})
+	return err
+}
+
+// SaveSnapshot copies every castle into the snapshots collection tagging them
+// with the given run ID. The copy happens inside the DB.
+func SaveSnapshot(ctx context.Context, castles, snapshots *mongo.Collection, runID string) error {
+	pipeline := mongo.Pipeline{
+		{{Key: "$project", Value: bson.M{
+			"_id":       0,
+			"runId":     runID,
+			"createdAt": time.Now().UTC(),
+			"castle":    "$$ROOT",
+		}}},
+		{{Key: "$merge", Value: bson.M{
+			"into": snapshots.Name(),
+		}}},
+	}
+	cursor, err := castles.Aggregate(ctx, pipeline)
+	if err != nil {
+		return fmt.Errorf("failed to save snapshot of run [%s], got %v", runID, err)
+	}
+	return cursor.Close(ctx)
+}
+
+// LoadSnapshot returns the castles of the snapshot of the given run. Snapshots
+// taken before facilities told what sources say about them get their
+// facilities converted as migrations did with the castles.
+func LoadSnapshot(ctx context.Context, snapshots *mongo.Collection, runID string) ([]castle.Model, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"runId": runID}}},
+		{{Key: "$replaceRoot", Value: bson.M{"newRoot": "$castle"}}},
+		{{Key: "$set", Value: bson.M{"visitingInfo": bson.M{"$cond": bson.A{
+			bson.M{"$eq": bson.A{bson.M{"$type": "$visitingInfo.facilities"}, "object"}},
+			bson.M{"$mergeObjects": bson.A{"$visitingInfo", bson.M{"facilities": facilitiesFromBooleans()}}},
+			"$visitingInfo",
+		}}}}},
+	}
+	cursor, err := snapshots.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load snapshot of run [%s], got %v", runID, err)
+	}
+	defer cursor.Close(ctx)
+
+	var castles []castle.Model
+	if err := cursor.All(ctx, &castles); err != nil {
+		return nil, fmt.Errorf("failed to decode snapshot of run [%s], got %v", runID, err)
+	}
+	if len(castles) == 0 {
+		return nil, ErrSnapshotNotFound
+	}
+	return castles, nil
+}
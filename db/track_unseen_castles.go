@@ -0,0 +1,124 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/buarki/find-castles/castle"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+var (
+	ErrNoSourcesSeen = errors.New("no sources were seen on this run")
+)
+
+// TombstonePolicy tells after how many consecutive successful runs without
+// seeing a castle on any of its sources it must be considered stale and,
+// later, removed.
+type TombstonePolicy struct {
+	StaleAfterMissedRuns  int
+	RemoveAfterMissedRuns int
+}
+
+var (
+	DefaultTombstonePolicy = TombstonePolicy{
+		StaleAfterMissedRuns:  3,
+		RemoveAfterMissedRuns: 7,
+	}
+)
+
+// TrackUnseenCastles must be called only after a successful run, meaning a run
+// in which every source listed its castles, otherwise castles of a failing
+// source would be counted as missing. Only castles with a source on the sites
+// seen on the run can miss it, so the ones imported from elsewhere or of
+// sources not enriched on the run are left as they are.
+func TrackUnseenCastles(ctx context.Context, collection *mongo.Collection, seenSources []string, policy TombstonePolicy) error {
+	if len(seenSources) == 0 {
+		return ErrNoSourcesSeen
+	}
+	now := time.Now().UTC()
+	onSeenSites := bson.M{"$regex": sitesMatcher(seenSources)}
+
+	seen, err := collection.UpdateMany(ctx,
+		bson.M{"sources": bson.M{"$in": seenSources}},
+		bson.M{"$set": bson.M{
+			"status":     castle.Active,
+			"missedRuns": 0,
+			"lastSeenAt": now,
+		}},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to mark seen castles as active, got %v", err)
+	}
+
+	missed, err := collection.UpdateMany(ctx,
+		bson.M{"$and": bson.A{
+			bson.M{"sources": bson.M{"$nin": seenSources}},
+			bson.M{"sources": onSeenSites},
+		}},
+		bson.M{"$inc": bson.M{"missedRuns": 1}},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to increment missed runs of unseen castles, got %v", err)
+	}
+
+	removed, err := collection.UpdateMany(ctx,
+		bson.M{
+			"missedRuns": bson.M{"$gte": policy.RemoveAfterMissedRuns},
+			"status":     bson.M{"$ne": castle.Removed},
+		},
+		bson.M{"$set": bson.M{
+			"status":    castle.Removed,
+			"removedAt": now,
+		}},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to mark castles as removed, got %v", err)
+	}
+
+	stale, err := collection.UpdateMany(ctx,
+		bson.M{
+			"missedRuns": bson.M{"$gte": policy.StaleAfterMissedRuns, "$lt": policy.RemoveAfterMissedRuns},
+			"status":     bson.M{"$nin": []castle.Status{castle.Stale, castle.Removed}},
+		},
+		bson.M{"$set": bson.M{"status": castle.Stale}},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to mark castles as stale, got %v", err)
+	}
+
+	log.Printf("tracked unseen castles: [%d] seen, [%d] missed, [%d] became stale, [%d] removed",
+		seen.MatchedCount, missed.MatchedCount, stale.ModifiedCount, removed.ModifiedCount)
+	return nil
+}
+
+// sitesMatcher matches the links of the sites of the given ones, like
+// https://heritageireland.ie/ of https://heritageireland.ie/places-to-visit/trim-castle/.
+func sitesMatcher(links []string) string {
+	sites := make(map[string]struct{})
+	for _, link := range links {
+		if site := siteOf(link); site != "" {
+			sites[regexp.QuoteMeta(site)] = struct{}{}
+		}
+	}
+	alternatives := make([]string, 0, len(sites))
+	for site := range sites {
+		alternatives = append(alternatives, site)
+	}
+	slices.Sort(alternatives)
+	return fmt.Sprintf("^(https?://)?(%s)(/|$)", strings.Join(alternatives, "|"))
+}
+
+// siteOf returns the host of the link, which may lack the scheme.
+func siteOf(link string) string {
+	link = strings.TrimPrefix(strings.TrimPrefix(link, "https://"), "http://")
+	site, _, _ := strings.Cut(link, "/")
+	return site
+}
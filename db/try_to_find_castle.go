@@ -4,8 +4,6 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"regexp"
-	"strings"
 
 	"github.com/buarki/find-castles/castle"
 	"go.mongodb.org/mongo-driver/bson"
@@ -17,49 +15,58 @@ var (
 )
 
 func TryToFindCastle(ctx context.Context, collection *mongo.Collection, c castle.Model) (castle.Model, error) {
-	var result castle.Model
-
-	matchingTags := c.GetMatchingTags()
-	c.GetMatchingTags()
-
-	regexPattern := fmt.Sprintf(".*%s.*", regexp.QuoteMeta(c.FilteredName()))
-	filter := bson.M{
-		"country":      c.Country.String(),
-		"name":         bson.M{"$regex": regexPattern, "$options": "i"},
-		"matchingTags": bson.M{"$in": matchingTags},
-	}
-	err := collection.FindOne(ctx, filter).Decode(&result)
+	candidates, err := TryToFindCastles(ctx, collection, []castle.Model{c}, castle.Identities{})
 	if err != nil {
-		if errors.Is(err, mongo.ErrNoDocuments) {
-			return castle.Model{}, ErrCastleNotFound
-		}
 		return castle.Model{}, err
 	}
-
-	return result, nil
+	match, found := castle.NewCandidateIndex(candidates).BestMatch(c)
+	if !found {
+		return castle.Model{}, ErrCastleNotFound
+	}
+	return match, nil
 }
 
-func TryToFindCastles(ctx context.Context, collection *mongo.Collection, castles []castle.Model) ([]castle.Model, error) {
+// TryToFindCastles returns the stored castles that may be the given ones:
+// the ones sharing at least one blocking key, source link or ID with them, and
+// the ones their sources were asserted to be. It is an indexed lookup, the
+// candidates must still be resolved in memory, see castle.CandidateIndex.
+func TryToFindCastles(ctx context.Context, collection *mongo.Collection, castles []castle.Model, identities castle.Identities) ([]castle.Model, error) {
 	var results []castle.Model
-	var filters []bson.M
 
+	keySet := make(map[string]bool)
+	sourceSet := make(map[string]bool)
+	idSet := make(map[string]bool)
 	for _, c := range castles {
-		matchingTags := c.GetMatchingTags()
-
-		regexPattern := fmt.Sprintf(".*%s.*", regexp.QuoteMeta(c.FilteredName()))
-		filter := bson.M{
-			"country": c.Country.String(),
-			"$or": []bson.M{
-				{"name": bson.M{"$regex": regexPattern, "$options": "i"}},
-				{"name": bson.M{"$in": matchingTags}},
-				{"name": bson.M{"$in": strings.Split(strings.ToLower(c.Name), " ")}},
-			},
+		for _, key := range c.BlockingKeys() {
+			keySet[key] = true
+		}
+		for _, source := range c.Sources {
+			sourceSet[source] = true
+		}
+		if c.ID != "" {
+			idSet[c.ID] = true
+		}
+		if castleID, found := identities.SameAs(c); found {
+			idSet[castleID] = true
 		}
-		filters = append(filters, filter)
+	}
+
+	var conditions bson.A
+	if len(keySet) > 0 {
+		conditions = append(conditions, bson.M{"blockingKeys": bson.M{"$in": setToSlice(keySet)}})
+	}
+	if len(sourceSet) > 0 {
+		conditions = append(conditions, bson.M{"sources": bson.M{"$in": setToSlice(sourceSet)}})
+	}
+	if len(idSet) > 0 {
+		conditions = append(conditions, bson.M{"id": bson.M{"$in": setToSlice(idSet)}})
+	}
+	if len(conditions) == 0 {
+		return nil, nil
 	}
 
 	query := bson.M{
-		"$or": filters,
+		"$or": conditions,
 	}
 
 	cursor, err := collection.Find(ctx, query)
@@ -82,3 +89,11 @@ func TryToFindCastles(ctx context.Context, collection *mongo.Collection, castles
 
 	return results, nil
 }
+
+func setToSlice(set map[string]bool) []string {
+	values := make([]string, 0, len(set))
+	for value := range set {
+		values = append(values, value)
+	}
+	return values
+}
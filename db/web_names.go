@@ -0,0 +1,89 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+
+	"github.com/buarki/find-castles/castle"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// allocateWebName gives the castle a web name no other castle has, nor had,
+// keeping its previous one as an alias when it changes.
+func allocateWebName(ctx context.Context, collection *mongo.Collection, c *castle.Model, allocated map[string]bool) error {
+	var takenByOthers map[string]bool
+	var findErr error
+	taken := func(webName string) bool {
+		if allocated[webName] {
+			return true
+		}
+		// the unique index keeps the allocated web name of a castle its own
+		if webName == c.AllocatedWebName {
+			return false
+		}
+		// looked up only when needed, as most castles keep their web name
+		if takenByOthers == nil && findErr == nil {
+			takenByOthers, findErr = webNamesTakenByOthers(ctx, collection, *c)
+		}
+		return takenByOthers[webName]
+	}
+
+	webName, err := c.AllocateWebName(taken)
+	// nothing is taken when the lookup failed, so the web name can't be used
+	if findErr != nil {
+		return findErr
+	}
+	if err != nil {
+		return fmt.Errorf("failed to allocate web name of castle [%s], got %v", c.Name, err)
+	}
+
+	if c.AllocatedWebName != "" && c.AllocatedWebName != webName && !slices.Contains(c.WebNameAliases, c.AllocatedWebName) {
+		c.WebNameAliases = append(slices.Clone(c.WebNameAliases), c.AllocatedWebName)
+	}
+	c.AllocatedWebName = webName
+	allocated[webName] = true
+	return nil
+}
+
+// webNamesTakenByOthers returns the web names, current or previous, of the
+// other castles that could collide with the ones the castle may get.
+func webNamesTakenByOthers(ctx context.Context, collection *mongo.Collection, c castle.Model) (map[string]bool, error) {
+	prefix, err := c.WebNamePrefix()
+	if err != nil {
+		return nil, err
+	}
+	pattern := bson.M{"$regex": "^" + regexp.QuoteMeta(prefix)}
+	filter := bson.M{
+		"id": bson.M{"$ne": c.ID},
+		"$or": bson.A{
+			bson.M{"webName": pattern},
+			bson.M{"webNameAliases": pattern},
+		},
+	}
+	opts := options.Find().SetProjection(bson.M{"webName": 1, "webNameAliases": 1})
+	cursor, err := collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find web names taken, got %v", err)
+	}
+	defer cursor.Close(ctx)
+
+	var docs []struct {
+		WebName        string   `bson:"webName"`
+		WebNameAliases []string `bson:"webNameAliases"`
+	}
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, fmt.Errorf("failed to decode web names taken, got %v", err)
+	}
+	taken := make(map[string]bool)
+	for _, doc := range docs {
+		taken[doc.WebName] = true
+		for _, alias := range doc.WebNameAliases {
+			taken[alias] = true
+		}
+	}
+	return taken, nil
+}
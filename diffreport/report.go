@@ -0,0 +1,162 @@
+package diffreport
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+
+	"github.com/buarki/find-castles/castle"
+)
+
+var (
+	ErrDuplicatedCastle = errors.New("castle found twice in the snapshot")
+)
+
+type CastleChange struct {
+	WebName string               `json:"webName"`
+	Name    string               `json:"name"`
+	Changes []castle.FieldChange `json:"changes,omitempty"`
+}
+
+// Group holds the changes of castles from the same country and source.
+type Group struct {
+	Country castle.Country `json:"country"`
+	Source  string         `json:"source"`
+	Added   []CastleChange `json:"added"`
+	Removed []CastleChange `json:"removed"`
+	Changed []CastleChange `json:"changed"`
+}
+
+type Summary struct {
+	Added   int `json:"added"`
+	Removed int `json:"removed"`
+	Changed int `json:"changed"`
+}
+
+type Report struct {
+	Old     string  `json:"old"`
+	New     string  `json:"new"`
+	Summary Summary `json:"summary"`
+	Groups  []Group `json:"groups"`
+}
+
+func (r Report) IsEmpty() bool {
+	return r.Summary.Added == 0 && r.Summary.Removed == 0 && r.Summary.Changed == 0
+}
+
+type groupKey struct {
+	country castle.Country
+	source  string
+}
+
+// Compare matches castles of both snapshots by their ID, or by their web
+// name when they have none, like castles saved before IDs were given. Labels
+// are only used to tell where each snapshot came from.
+func Compare(oldLabel string, oldCastles []castle.Model, newLabel string, newCastles []castle.Model) (Report, error) {
+	oldByKey, err := indexByKey(oldCastles)
+	if err != nil {
+		return Report{}, err
+	}
+	newByKey, err := indexByKey(newCastles)
+	if err != nil {
+		return Report{}, err
+	}
+
+	groups := make(map[groupKey]*Group)
+	groupOf := func(c castle.Model) *Group {
+		key := groupKey{country: c.Country, source: sourceOf(c)}
+		g, found := groups[key]
+		if !found {
+			g = &Group{Country: key.country, Source: key.source}
+			groups[key] = g
+		}
+		return g
+	}
+
+	report := Report{Old: oldLabel, New: newLabel}
+
+	for key, newCastle := range newByKey {
+		oldCastle, found := oldByKey[key]
+		if !found {
+			g := groupOf(newCastle.Model)
+			g.Added = append(g.Added, CastleChange{WebName: newCastle.webName, Name: newCastle.Name})
+			report.Summary.Added++
+			continue
+		}
+		changes := castle.Diff(oldCastle.Model, newCastle.Model)
+		if len(changes) > 0 {
+			g := groupOf(newCastle.Model)
+			g.Changed = append(g.Changed, CastleChange{WebName: newCastle.webName, Name: newCastle.Name, Changes: changes})
+			report.Summary.Changed++
+		}
+	}
+
+	for key, oldCastle := range oldByKey {
+		if _, found := newByKey[key]; !found {
+			g := groupOf(oldCastle.Model)
+			g.Removed = append(g.Removed, CastleChange{WebName: oldCastle.webName, Name: oldCastle.Name})
+			report.Summary.Removed++
+		}
+	}
+
+	for _, g := range groups {
+		sortByWebName(g.Added)
+		sortByWebName(g.Removed)
+		sortByWebName(g.Changed)
+		report.Groups = append(report.Groups, *g)
+	}
+	sort.Slice(report.Groups, func(i, j int) bool {
+		if report.Groups[i].Country != report.Groups[j].Country {
+			return report.Groups[i].Country < report.Groups[j].Country
+		}
+		return report.Groups[i].Source < report.Groups[j].Source
+	})
+
+	return report, nil
+}
+
+type indexedCastle struct {
+	castle.Model
+	webName string
+}
+
+// indexByKey indexes castles by their ID, falling back to their web name,
+// so castles sharing a name in different places aren't taken for one another.
+func indexByKey(castles []castle.Model) (map[string]indexedCastle, error) {
+	indexed := make(map[string]indexedCastle, len(castles))
+	for _, c := range castles {
+		webName, err := c.WebName()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get web name of castle [%s], got %v", c.Name, err)
+		}
+		key := c.ID
+		if key == "" {
+			key = webName
+		}
+		if _, found := indexed[key]; found {
+			return nil, fmt.Errorf("%w: [%s]", ErrDuplicatedCastle, key)
+		}
+		indexed[key] = indexedCastle{Model: c, webName: webName}
+	}
+	return indexed, nil
+}
+
+// sourceOf returns the host of the first source of the castle, which is the
+// one that first listed it.
+func sourceOf(c castle.Model) string {
+	if len(c.Sources) == 0 {
+		return "unknown"
+	}
+	parsed, err := url.Parse(c.Sources[0])
+	if err != nil || parsed.Host == "" {
+		return "unknown"
+	}
+	return parsed.Host
+}
+
+func sortByWebName(changes []CastleChange) {
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].WebName < changes[j].WebName
+	})
+}
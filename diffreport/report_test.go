@@ -0,0 +1,127 @@
+package diffreport
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/buarki/find-castles/castle"
+)
+
+func TestCompare(t *testing.T) {
+	oldCastles := []castle.Model{
+		{
+			Name:    "trim",
+			Country: castle.Ireland,
+			City:    "trim",
+			Sources: []string{"https://heritageireland.ie/places-to-visit/trim-castle/"},
+		},
+		{
+			Name:    "roscommon",
+			Country: castle.Ireland,
+			Sources: []string{"https://heritageireland.ie/places-to-visit/roscommon-castle/"},
+		},
+	}
+	newCastles := []castle.Model{
+		{
+			Name:    "trim",
+			Country: castle.Ireland,
+			City:    "co meath",
+			Sources: []string{"https://heritageireland.ie/places-to-visit/trim-castle/"},
+		},
+		{
+			Name:    "guimaraes",
+			Country: castle.Portugal,
+			Sources: []string{"https://www.castelosdeportugal.pt/castelos/guimaraes.html"},
+		},
+	}
+
+	report, err := Compare("old", oldCastles, "new", newCastles)
+	if err != nil {
+		t.Fatalf("expected err nil, got %v", err)
+	}
+
+	if report.Summary != (Summary{Added: 1, Removed: 1, Changed: 1}) {
+		t.Errorf("unexpected summary %+v", report.Summary)
+	}
+	if len(report.Groups) != 2 {
+		t.Fatalf("expected 2 groups, got %d", len(report.Groups))
+	}
+
+	irishGroup := report.Groups[0]
+	if irishGroup.Country != castle.Ireland || irishGroup.Source != "heritageireland.ie" {
+		t.Errorf("expected first group to be of ireland from heritageireland.ie, got [%s] [%s]", irishGroup.Country, irishGroup.Source)
+	}
+	if len(irishGroup.Removed) != 1 || irishGroup.Removed[0].WebName != "roscommon-ie" {
+		t.Errorf("expected roscommon to be removed, got %+v", irishGroup.Removed)
+	}
+	if len(irishGroup.Changed) != 1 || irishGroup.Changed[0].Changes[0].Field != "city" {
+		t.Errorf("expected trim to have its city changed, got %+v", irishGroup.Changed)
+	}
+
+	portugueseGroup := report.Groups[1]
+	if len(portugueseGroup.Added) != 1 || portugueseGroup.Added[0].WebName != "guimaraes-pt" {
+		t.Errorf("expected guimaraes to be added, got %+v", portugueseGroup.Added)
+	}
+}
+
+func TestCompareCastlesWithTheSameNameByID(t *testing.T) {
+	oldCastles := []castle.Model{
+		{ID: "1", Name: "castelo velho", Country: castle.Portugal, City: "alcoutim", AllocatedWebName: "castelo-velho-pt"},
+		{ID: "2", Name: "castelo velho", Country: castle.Portugal, City: "mertola", AllocatedWebName: "castelo-velho-mertola-pt"},
+	}
+	newCastles := []castle.Model{
+		{ID: "1", Name: "castelo velho", Country: castle.Portugal, City: "alcoutim", AllocatedWebName: "castelo-velho-pt"},
+		{ID: "2", Name: "castelo velho", Country: castle.Portugal, City: "mertola", District: "beja", AllocatedWebName: "castelo-velho-mertola-pt"},
+	}
+
+	report, err := Compare("old", oldCastles, "new", newCastles)
+	if err != nil {
+		t.Fatalf("expected err nil, got %v", err)
+	}
+
+	if report.Summary != (Summary{Changed: 1}) {
+		t.Fatalf("expected only one changed castle, got %+v", report.Summary)
+	}
+	changed := report.Groups[0].Changed[0]
+	if changed.WebName != "castelo-velho-mertola-pt" || changed.Changes[0].Field != "district" {
+		t.Errorf("expected the district of castelo-velho-mertola-pt to change, got %+v", changed)
+	}
+}
+
+func TestCompareFailsOnDuplicatedCastles(t *testing.T) {
+	castles := []castle.Model{
+		{Name: "trim", Country: castle.Ireland},
+		{Name: "trim", Country: castle.Ireland},
+	}
+
+	_, err := Compare("old", castles, "new", nil)
+	if !errors.Is(err, ErrDuplicatedCastle) {
+		t.Errorf("expected err [%v], got [%v]", ErrDuplicatedCastle, err)
+	}
+}
+
+func TestWriteMarkdown(t *testing.T) {
+	report, err := Compare("old", nil, "new", []castle.Model{
+		{
+			Name:    "trim",
+			Country: castle.Ireland,
+		},
+	})
+	if err != nil {
+		t.Fatalf("expected err nil, got %v", err)
+	}
+
+	var b bytes.Buffer
+	if err := Write(&b, report, Markdown); err != nil {
+		t.Fatalf("expected err nil, got %v", err)
+	}
+
+	if !strings.Contains(b.String(), "## ie - unknown") {
+		t.Errorf("expected group header to be present, got\n%s", b.String())
+	}
+	if !strings.Contains(b.String(), "- trim (`trim-ie`)") {
+		t.Errorf("expected added castle to be present, got\n%s", b.String())
+	}
+}
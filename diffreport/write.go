@@ -0,0 +1,81 @@
+package diffreport
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+type Format string
+
+const (
+	Markdown Format = "markdown"
+	JSON     Format = "json"
+)
+
+func Write(w io.Writer, r Report, format Format) error {
+	switch format {
+	case Markdown:
+		return WriteMarkdown(w, r)
+	case JSON:
+		return WriteJSON(w, r)
+	default:
+		return fmt.Errorf("unsupported report format [%s]", format)
+	}
+}
+
+func WriteJSON(w io.Writer, r Report) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(r); err != nil {
+		return fmt.Errorf("failed to write report as JSON, got %v", err)
+	}
+	return nil
+}
+
+func WriteMarkdown(w io.Writer, r Report) error {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "# Castles diff\n\n")
+	fmt.Fprintf(&b, "Comparing `%s` with `%s`.\n\n", r.Old, r.New)
+	fmt.Fprintf(&b, "|Added|Removed|Changed|\n|--|--|--|\n|%d|%d|%d|\n", r.Summary.Added, r.Summary.Removed, r.Summary.Changed)
+
+	if r.IsEmpty() {
+		b.WriteString("\nNo changes found.\n")
+	}
+
+	for _, g := range r.Groups {
+		fmt.Fprintf(&b, "\n## %s - %s\n", g.Country, g.Source)
+		if len(g.Added) > 0 {
+			fmt.Fprintf(&b, "\n### Added\n\n")
+			for _, c := range g.Added {
+				fmt.Fprintf(&b, "- %s (`%s`)\n", c.Name, c.WebName)
+			}
+		}
+		if len(g.Removed) > 0 {
+			fmt.Fprintf(&b, "\n### Removed\n\n")
+			for _, c := range g.Removed {
+				fmt.Fprintf(&b, "- %s (`%s`)\n", c.Name, c.WebName)
+			}
+		}
+		if len(g.Changed) > 0 {
+			fmt.Fprintf(&b, "\n### Changed\n")
+			for _, c := range g.Changed {
+				fmt.Fprintf(&b, "\n%s (`%s`)\n\n|Field|Old|New|\n|--|--|--|\n", c.Name, c.WebName)
+				for _, change := range c.Changes {
+					fmt.Fprintf(&b, "|%s|%s|%s|\n", change.Field, escapeCell(change.Old), escapeCell(change.New))
+				}
+			}
+		}
+	}
+
+	if _, err := io.WriteString(w, b.String()); err != nil {
+		return fmt.Errorf("failed to write report as markdown, got %v", err)
+	}
+	return nil
+}
+
+func escapeCell(value string) string {
+	return strings.NewReplacer("|", `\|`, "\n", " ").Replace(value)
+}
@@ -0,0 +1,57 @@
+package enricher
+
+import (
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/buarki/find-castles/castle"
+)
+
+// collectAdmissionLine reads a line of an admission section, like "Adult:
+// €5.00", "Free admission" or "Heritage Card holders go free".
+func collectAdmissionLine(admission *castle.Admission, line string) {
+	line = strings.Join(strings.Fields(line), " ")
+	lowerLine := strings.ToLower(line)
+	if strings.Contains(lowerLine, "heritage card") || strings.Contains(lowerLine, "opw") {
+		admission.MembershipNotes = strings.TrimSpace(admission.MembershipNotes + " " + line)
+		return
+	}
+	if label, rawAmount, found := strings.Cut(line, ":"); found {
+		if err := admission.AddPrices(label, rawAmount); err == nil {
+			return
+		}
+	}
+	if strings.Contains(lowerLine, "free admission") || strings.Contains(lowerLine, "admission free") ||
+		strings.Contains(lowerLine, "free entry") || lowerLine == "free" {
+		admission.Free = true
+	}
+}
+
+// collectBookingURL finds the first link of the selection about booking or tickets.
+func collectBookingURL(s *goquery.Selection) string {
+	var bookingURL string
+	s.Find("a").EachWithBreak(func(i int, a *goquery.Selection) bool {
+		text := strings.ToLower(a.Text())
+		if strings.Contains(text, "book") || strings.Contains(text, "ticket") {
+			bookingURL, _ = a.Attr("href")
+			return false
+		}
+		return true
+	})
+	return bookingURL
+}
+
+// admissionOf leaves out admissions that tell nothing, and tells through the
+// facilities whether visitors pay to get in.
+func admissionOf(admission castle.Admission, facilities *castle.Facilities, source string) *castle.Admission {
+	if !admission.IsKnown() && admission.MembershipNotes == "" && admission.BookingURL == "" {
+		return nil
+	}
+	switch {
+	case admission.Free:
+		facilities.Set(castle.AdmissionFee, castle.NotAvailable, source)
+	case len(admission.Prices) > 0:
+		facilities.Set(castle.AdmissionFee, castle.Available, source)
+	}
+	return &admission
+}
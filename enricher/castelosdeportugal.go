@@ -16,6 +16,23 @@ const (
 	castelosdeportugalCastlesSource = castelosdeportugalHost + "/castelos/SiteMap.html"
 )
 
+var (
+	// "Conservação" labels, lower cased
+	castelosDePortugalConditions = map[string]castle.PropertyCondition{
+		"boa":          castle.Intact,
+		"restaurado":   castle.Restored,
+		"reconstruído": castle.Rebuilt,
+		"adaptado":     castle.Converted,
+		"razoável":     castle.Damaged,
+		"mau":          castle.Ruins,
+		"ruína":        castle.Ruins,
+		"vestígios":    castle.FoundationsOnly,
+		"arqueológico": castle.ArchaeologicalSite,
+		"desaparecido": castle.Destroyed,
+		"submerso":     castle.Submerged,
+	}
+)
+
 type castelosDePortugalEnricher struct {
 	httpClient *http.Client
 	fetchHTML  func(ctx context.Context, link string, httpClient *http.Client) ([]byte, error)
@@ -95,6 +112,8 @@ func (p *castelosDePortugalEnricher) EnrichCastle(ctx context.Context, c castle.
 	if err != nil {
 		return castle.Model{}, err
 	}
+	// the name as the source gives it, before being cleaned
+	enrichedCastled.SetLocalizedName(castle.Portuguese, enrichedCastled.Name)
 	enrichedCastled.CleanFields()
 	return enrichedCastled, nil
 }
@@ -140,18 +159,24 @@ func (p *castelosDePortugalEnricher) extractCastleInfo(c castle.Model, rawHTMLPa
 		district = tableData[tableData["Concelho"]]
 	}
 
-	return castle.Model{
-		Name:                  c.Name,
-		Country:               c.Country,
-		CurrentEnrichmentLink: c.CurrentEnrichmentLink,
-		City:                  tableData["Concelho"],
-		State:                 tableData["Distrito"],
-		District:              district,
-		FoundationPeriod:      tableData["Construção"],
-		PropertyCondition:     p.parseCondition(tableData["Conservação"]),
-		PictureURL:            p.collectImage(doc),
-		Sources:               c.Sources,
-	}, nil
+	enrichedCastle := castle.Model{
+		Name:                   c.Name,
+		Country:                c.Country,
+		CurrentEnrichmentLink:  c.CurrentEnrichmentLink,
+		FoundationPeriod:       tableData["Construção"],
+		PropertyCondition:      p.parseCondition(tableData["Conservação"]),
+		PropertyConditionLabel: strings.TrimSpace(tableData["Conservação"]),
+		PictureURL:             p.collectImage(doc),
+		Sources:                c.Sources,
+	}
+	// the source has no postal address, but its administrative divisions
+	enrichedCastle.SetAddress(castle.Address{
+		Locality: district,
+		Town:     tableData["Concelho"],
+		Region:   tableData["Distrito"],
+		Country:  c.Country,
+	})
+	return enrichedCastle, nil
 }
 
 func (p castelosDePortugalEnricher) collectImage(doc *goquery.Document) string {
@@ -163,19 +188,10 @@ func (p castelosDePortugalEnricher) collectImage(doc *goquery.Document) string {
 }
 
 func (p castelosDePortugalEnricher) parseCondition(rawCondition string) castle.PropertyCondition {
-	filtered := strings.ToLower(rawCondition)
-	switch filtered {
-	case "boa":
-		return castle.Intact
-	case "razoável":
-		return castle.Damaged
-	case "mau":
-		return castle.Ruins
-	case "submerso":
-		return castle.Ruins
-	default:
-		return castle.Unknown
+	if condition, found := castelosDePortugalConditions[strings.ToLower(strings.TrimSpace(rawCondition))]; found {
+		return condition
 	}
+	return castle.Unknown
 }
 
 func (p *castelosDePortugalEnricher) contains(arr []string, str string) bool {
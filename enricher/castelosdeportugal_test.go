@@ -115,8 +115,9 @@ func TestExtractPortugueseCastleInfo(t *testing.T) {
 		District:         "oliveira do castelo",
 		FoundationPeriod: "(ant. a 958)",
 		// Link:              "https://somelink.pt",
-		PropertyCondition: castle.Intact,
-		PictureURL:        "https://www.castelosdeportugal.pt/castelos/assets/img/Castelos(pre)SECXII/guimaraes/guimaraes1_small.jpg",
+		PropertyCondition:      castle.Intact,
+		PropertyConditionLabel: "Boa",
+		PictureURL:             "https://www.castelosdeportugal.pt/castelos/assets/img/Castelos(pre)SECXII/guimaraes/guimaraes1_small.jpg",
 	}
 
 	castelosDePortugalEnricher := NewCastelosDePortugalEnricher(httpclient.New(), htmlFetcher)
@@ -144,6 +145,9 @@ func TestExtractPortugueseCastleInfo(t *testing.T) {
 	if receivedCastle.PropertyCondition != expectedCastle.PropertyCondition {
 		t.Errorf("expected PropertyCondition to be [%s], got [%s]", expectedCastle.PropertyCondition, receivedCastle.PropertyCondition)
 	}
+	if receivedCastle.PropertyConditionLabel != expectedCastle.PropertyConditionLabel {
+		t.Errorf("expected PropertyConditionLabel to be [%s], got [%s]", expectedCastle.PropertyConditionLabel, receivedCastle.PropertyConditionLabel)
+	}
 	if receivedCastle.PictureURL != expectedCastle.PictureURL {
 		t.Errorf("expected PictureURL to be [%s], got [%s]", expectedCastle.PictureURL, receivedCastle.PictureURL)
 	}
@@ -157,7 +161,8 @@ func TestParseCondition(t *testing.T) {
 		{rawCondition: "Boa", expectedCondition: castle.Intact},
 		{rawCondition: "", expectedCondition: castle.Unknown},
 		{rawCondition: "()", expectedCondition: castle.Unknown},
-		{rawCondition: "Submerso", expectedCondition: castle.Ruins},
+		{rawCondition: "Submerso", expectedCondition: castle.Submerged},
+		{rawCondition: "Vestígios", expectedCondition: castle.FoundationsOnly},
 		{rawCondition: "Mau", expectedCondition: castle.Ruins},
 		{rawCondition: "Razoável", expectedCondition: castle.Damaged},
 	}
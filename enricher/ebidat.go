@@ -5,7 +5,7 @@ import (
 	"context"
 	"fmt"
 	"net/http"
-	"regexp"
+	"net/url"
 	"slices"
 	"strconv"
 	"strings"
@@ -41,6 +41,25 @@ var (
 		},
 	}
 
+	// "Erhaltung - Heutiger Zustand" labels, lower cased
+	ebidatConditions = map[string]castle.PropertyCondition{
+		"erhalten":                       castle.Intact,
+		"weitgehend erhalten":            castle.Intact, // largely preserved
+		"restauriert":                    castle.Restored,
+		"wiederaufgebaut":                castle.Rebuilt,
+		"stark historisierend überformt": castle.Rebuilt,      // heavily historicized
+		"überbaut":                       castle.Converted,    // built over
+		"umgebaut":                       castle.Converted,    // converted
+		"bedeutende reste":               castle.PartialRuins, // significant remains
+		"ruine":                          castle.Ruins,
+		"geringe reste":                  castle.Ruins, // small remains
+		"fundamente":                     castle.FoundationsOnly,
+		"wall- und grabenreste":          castle.ArchaeologicalSite, // remains of ramparts and ditches
+		"burgstall":                      castle.ArchaeologicalSite, // site of a vanished castle
+		"keine reste":                    castle.Destroyed,
+		"nicht erhalten":                 castle.Destroyed,
+	}
+
 	stateCollector = map[castle.Country]func(doc *goquery.Document) string{
 		castle.Slovakia: func(doc *goquery.Document) string {
 			var state string
@@ -293,7 +312,7 @@ func (se *ebidatEnricher) EnrichCastle(ctx context.Context, c castle.Model) (cas
 	}
 
 	c1 := &c
-	c1.PropertyCondition = se.getPropertyConditions(doc)
+	c1.PropertyCondition, c1.PropertyConditionLabel = se.getPropertyConditions(doc)
 	c1.PictureURL = se.collectImage(doc)
 	c1.Coordinates = se.collectCoordinates(doc)
 	c1.FoundationPeriod = se.collectPeriod(doc)
@@ -301,7 +320,10 @@ func (se *ebidatEnricher) EnrichCastle(ctx context.Context, c castle.Model) (cas
 	c1.State = se.collectState(doc, c.Country)
 	c1.City = se.collectCity(doc)
 	c1.District = se.collectDistrict(doc)
+	c1.AlternateNames = se.collectOtherNames(doc)
 
+	// EBIDAT names castles in German, whatever their country is
+	c1.SetLocalizedName(castle.German, c1.Name)
 	c1.CleanFields()
 	return *c1, nil
 }
@@ -319,6 +341,23 @@ func (se ebidatEnricher) collectCity(doc *goquery.Document) string {
 	return city
 }
 
+func (se ebidatEnricher) collectOtherNames(doc *goquery.Document) []string {
+	var otherNames string
+	doc.Find("li.daten").Each(func(i int, s *goquery.Selection) {
+		label := s.Find(".gruppe").Text()
+		if strings.Contains(label, "weitere Namen:") {
+			otherNames = s.Find(".gruppenergebnis").Text()
+		}
+	})
+	var names []string
+	for _, name := range strings.Split(html.UnescapeString(otherNames), ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
 func (se ebidatEnricher) collectDistrict(doc *goquery.Document) string {
 	var city string
 	doc.Find("li.daten").Each(func(i int, s *goquery.Selection) {
@@ -345,43 +384,32 @@ func (se ebidatEnricher) collectPeriod(doc *goquery.Document) string {
 			periodText = s.Find(".gruppenergebnis").Text()
 		}
 	})
+	// kept as written, as "2.H.13.Jh." tells more than the century
 	periodText = strings.TrimSpace(periodText)
-	re := regexp.MustCompile(`(\d{1,2})\.Jh\.`)
-	matches := re.FindStringSubmatch(periodText)
-	if len(matches) > 1 {
-		return matches[1] + "th"
+	if _, err := castle.ParsePeriod(periodText); err != nil {
+		return ""
 	}
-	return ""
+	return periodText
 }
 
-func (se ebidatEnricher) getPropertyConditions(doc *goquery.Document) castle.PropertyCondition {
+// getPropertyConditions returns the condition the "Erhaltung - Heutiger
+// Zustand" label maps into, and the label itself.
+func (se ebidatEnricher) getPropertyConditions(doc *goquery.Document) (castle.PropertyCondition, string) {
 	propertyCondition := castle.Unknown
+	var label string
 
 	doc.Find("div.mainContent section article.beschreibung ul li.daten").Each(func(i int, s *goquery.Selection) {
 		gruppe := s.Find("div.gruppe").Text()
 		if strings.Contains(gruppe, "Erhaltung - Heutiger Zustand:") {
-			collectedCondition := strings.ToLower(s.Find("div.gruppenergebnis").Text())
-			switch collectedCondition {
-			case "weitgehend erhalten": //largely preserved
-				propertyCondition = castle.Intact
-			case "stark historisierend überformt": //  heavily historicized
-				propertyCondition = castle.Intact
-			case "überbaut": //built over
-				propertyCondition = castle.Intact
-			case "geringe reste": // sall residues
-				propertyCondition = castle.Ruins
-			case "bedeutende reste": //significant remains
-				propertyCondition = castle.Ruins
-			case "fundamente": //foundations
-				propertyCondition = castle.Ruins
-			default:
-				propertyCondition = castle.Unknown
+			label = strings.TrimSpace(s.Find("div.gruppenergebnis").Text())
+			if condition, found := ebidatConditions[strings.ToLower(label)]; found {
+				propertyCondition = condition
 			}
 			return
 		}
 	})
 
-	return propertyCondition
+	return propertyCondition, label
 }
 
 func (se ebidatEnricher) collectImage(doc *goquery.Document) string {
@@ -397,17 +425,21 @@ func (se ebidatEnricher) collectImage(doc *goquery.Document) string {
 	return collectedImageLink
 }
 
+// collectCoordinates reads them from the query of the Google Maps link, ex:
+// http://maps.google.com/maps/?q=48.780049,18.577476. Castles without the
+// link, or with one without query, have no coordinates.
 func (se ebidatEnricher) collectCoordinates(doc *goquery.Document) string {
-	var coordinates string
-	doc.Find("#verlinkungen .informationen_link a").Each(func(i int, s *goquery.Selection) {
-		if s.Text() == "Google Maps" {
-			coordinates, _ = s.Attr("href")
-			return
+	var mapsLink string
+	doc.Find("#verlinkungen .informationen_link a").EachWithBreak(func(i int, s *goquery.Selection) bool {
+		if strings.TrimSpace(s.Text()) == "Google Maps" {
+			mapsLink, _ = s.Attr("href")
+			return false
 		}
+		return true
 	})
-	parts := strings.Split(coordinates, "q=")
-	if len(parts) > 0 {
-		return parts[1]
+	mapsURL, err := url.Parse(strings.TrimSpace(mapsLink))
+	if err != nil {
+		return ""
 	}
-	return ""
+	return strings.TrimSpace(mapsURL.Query().Get("q"))
 }
@@ -2,6 +2,7 @@ package enricher
 
 import (
 	"bytes"
+	"reflect"
 	"testing"
 
 	"github.com/PuerkitoBio/goquery"
@@ -549,12 +550,16 @@ func TestExtractPropertyConditions(t *testing.T) {
 
 	se := ebidatEnricher{}
 	expectedCondition := castle.Ruins
+	expectedLabel := "Geringe Reste"
 
-	receivedCondition := se.getPropertyConditions(doc)
+	receivedCondition, receivedLabel := se.getPropertyConditions(doc)
 
 	if receivedCondition != expectedCondition {
 		t.Errorf("expected conditions [%s], got [%s]", expectedCondition.String(), receivedCondition.String())
 	}
+	if receivedLabel != expectedLabel {
+		t.Errorf("expected label [%s], got [%s]", expectedLabel, receivedLabel)
+	}
 
 }
 
@@ -652,6 +657,33 @@ func TestCollectingLocalizationCoordinates(t *testing.T) {
 	}
 }
 
+func TestCollectingCoordinatesOfLinksWithoutThem(t *testing.T) {
+	testCases := []struct {
+		name     string
+		htmlLink string
+	}{
+		{name: "without_query", htmlLink: `<a href="http://maps.google.com/maps/" target="_blank">Google Maps</a>`},
+		{name: "without_q", htmlLink: `<a href="http://maps.google.com/maps/?ll=48.780049,18.577476" target="_blank">Google Maps</a>`},
+		{name: "without_link", htmlLink: `<a href="/cgi-bin/ebidat.pl?id=2029">Home</a>`},
+	}
+
+	for _, tt := range testCases {
+		currentTT := tt
+		t.Run(currentTT.name, func(t *testing.T) {
+			t.Helper()
+			content := []byte(`<ul id="verlinkungen"><li class="informationen_link">` + currentTT.htmlLink + `</li></ul>`)
+			doc, err := goquery.NewDocumentFromReader(bytes.NewReader(content))
+			if err != nil {
+				t.Errorf("expected to have err nil, got [%v]", err)
+			}
+
+			if coordinates := (ebidatEnricher{}).collectCoordinates(doc); coordinates != "" {
+				t.Errorf("expected no coordinates, got [%s]", coordinates)
+			}
+		})
+	}
+}
+
 func TestCollectPeriod(t *testing.T) {
 	testCases := []struct {
 		htmlChunk      []byte
@@ -664,7 +696,7 @@ func TestCollectPeriod(t *testing.T) {
 				<div class="gruppenergebnis">10.Jh.</div>
 			</li>
 			`),
-			expectedPeriod: "10th",
+			expectedPeriod: "10.Jh.",
 		},
 		{
 			htmlChunk: []byte(`
@@ -673,7 +705,7 @@ func TestCollectPeriod(t *testing.T) {
 				<div class="gruppenergebnis">11.Jh.</div>
 			</li>
 			`),
-			expectedPeriod: "11th",
+			expectedPeriod: "11.Jh.",
 		},
 		{
 			htmlChunk: []byte(`
@@ -682,7 +714,16 @@ func TestCollectPeriod(t *testing.T) {
 			<div class="gruppenergebnis">2.H.13.Jh.</div>
 		</li>
 			`),
-			expectedPeriod: "13th",
+			expectedPeriod: "2.H.13.Jh.",
+		},
+		{
+			htmlChunk: []byte(`
+			<li class="daten">
+			<div class="gruppe">Datierung-Beginn:</div>
+			<div class="gruppenergebnis">unbekannt</div>
+		</li>
+			`),
+			expectedPeriod: "",
 		},
 	}
 
@@ -878,3 +919,61 @@ func TestEBIDATCollectDistrict(t *testing.T) {
 	}
 
 }
+
+func TestEBIDATCollectOtherNames(t *testing.T) {
+	testCases := []struct {
+		name      string
+		htmlChunk []byte
+		expected  []string
+	}{
+		{
+			name: "single other name",
+			htmlChunk: []byte(`
+			<li class="daten">
+				<div class="gruppe">weitere Namen:</div>
+				<div class="gruppenergebnis">Bíny</div>
+			</li>
+			`),
+			expected: []string{"Bíny"},
+		},
+		{
+			name: "many other names",
+			htmlChunk: []byte(`
+			<li class="daten">
+				<div class="gruppe">weitere Namen:</div>
+				<div class="gruppenergebnis">Trenčiansky hrad, Burg Trentschin</div>
+			</li>
+			`),
+			expected: []string{"Trenčiansky hrad", "Burg Trentschin"},
+		},
+		{
+			name: "no other names",
+			htmlChunk: []byte(`
+			<li class="daten">
+				<div class="gruppe">Staat:</div>
+				<div class="gruppenergebnis">Slowakei</div>
+			</li>
+			`),
+			expected: nil,
+		},
+	}
+
+	e := &ebidatEnricher{}
+	for _, tt := range testCases {
+		currentTT := tt
+		t.Run(currentTT.name, func(t *testing.T) {
+			t.Helper()
+
+			doc, err := goquery.NewDocumentFromReader(bytes.NewReader(currentTT.htmlChunk))
+			if err != nil {
+				t.Errorf("expected to have err nil, got [%v]", err)
+			}
+
+			received := e.collectOtherNames(doc)
+
+			if !reflect.DeepEqual(received, currentTT.expected) {
+				t.Errorf("expected to have other names %v, got %v", currentTT.expected, received)
+			}
+		})
+	}
+}
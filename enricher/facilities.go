@@ -0,0 +1,32 @@
+package enricher
+
+import (
+	"github.com/PuerkitoBio/goquery"
+	"github.com/buarki/find-castles/castle"
+)
+
+// collectFacilities finds the facilities a page shows through the selector
+// of each one. Pages showing some facilities tell the others of the
+// selectors are not available, while pages showing none tell nothing.
+func collectFacilities(doc *goquery.Document, selectors map[castle.Facility]string, source string) castle.Facilities {
+	found := make(map[castle.Facility]bool, len(selectors))
+	for facility, selector := range selectors {
+		found[facility] = doc.Find(selector).Length() > 0
+	}
+
+	var facilities castle.Facilities
+	for facility, available := range found {
+		if available {
+			facilities.Set(facility, castle.Available, source)
+		}
+	}
+	if len(facilities) == 0 {
+		return nil
+	}
+	for facility, available := range found {
+		if !available {
+			facilities.Set(facility, castle.NotAvailable, source)
+		}
+	}
+	return facilities
+}
@@ -9,6 +9,7 @@ import (
 
 	"github.com/PuerkitoBio/goquery"
 	"github.com/buarki/find-castles/castle"
+	"github.com/buarki/find-castles/openinghours"
 )
 
 const (
@@ -16,6 +17,17 @@ const (
 	herirageIrelandURL  = heritageIrelandHost + "/visit/castles/"
 )
 
+var (
+	// classes of the facilities and restrictions lists of each place
+	heritageIrelandFacilities = map[castle.Facility]string{
+		castle.Cafe:                  "#facilities li.cafe",
+		castle.Parking:               "#facilities li.car-park",
+		castle.Restrooms:             "#facilities li.toilets",
+		castle.AccessibleToilets:     "#facilities li.wheelchair-accessible-toilet",
+		castle.AssistanceDogsAllowed: "#restrictions li.dogs-on-lead",
+	}
+)
+
 type heritageirelandEnricher struct {
 	httpClient *http.Client
 	fetchHTML  func(ctx context.Context, link string, httpClient *http.Client) ([]byte, error)
@@ -95,6 +107,8 @@ func (ie *heritageirelandEnricher) EnrichCastle(ctx context.Context, c castle.Mo
 	if err != nil {
 		return castle.Model{}, err
 	}
+	// the name as the source gives it, before being cleaned
+	enrichedCastled.SetLocalizedName(castle.English, enrichedCastled.Name)
 	enrichedCastled.CleanFields()
 	return enrichedCastled, nil
 }
@@ -104,54 +118,31 @@ func (ie *heritageirelandEnricher) extractCastleInfo(c castle.Model, castlePage
 	if err != nil {
 		return castle.Model{}, fmt.Errorf("error loading HTML: %v", err)
 	}
-	rawAddress, err := ie.extractContact(doc)
-	if err != nil {
-		return castle.Model{}, err
-	}
 
-	district, city, state := ie.get(rawAddress)
-
-	return castle.Model{
+	enrichedCastle := castle.Model{
 		Name:                  c.Name,
 		Country:               castle.Ireland,
 		CurrentEnrichmentLink: c.CurrentEnrichmentLink,
-		City:                  city,
-		State:                 state,
-		District:              district,
 		PictureURL:            ie.collectImage(doc),
 		Contact:               ie.collectContactInfo(doc),
 		Sources:               []string{c.CurrentEnrichmentLink},
-		VisitingInfo:          ie.collectVisitingInfo(doc),
+		VisitingInfo:          ie.collectVisitingInfo(doc, c.CurrentEnrichmentLink),
 		PropertyCondition:     castle.Unknown,
-	}, nil
-}
-
-func (ie *heritageirelandEnricher) extractContact(doc *goquery.Document) (string, error) {
-	address, err := doc.Find("#place--contact div p.address").First().Html()
-	if err != nil {
-		return "", err
 	}
-
-	return address, nil
+	enrichedCastle.SetAddress(castle.ParseAddress(castle.Ireland, ie.collectAddressLines(doc)))
+	return enrichedCastle, nil
 }
 
-func (ie *heritageirelandEnricher) get(raw string) (string, string, string) {
-	parts := strings.Split(raw, "<br/>")
-	for i := range parts {
-		parts[i] = strings.TrimSpace(strings.ReplaceAll(strings.ReplaceAll(parts[i], ",", ""), "\n", ""))
-	}
-	// ex: [Ross Castle, Ross Road, Killarney, Co. Kerry, V93 V304]
-	placeInsideDistrictInformed := len(parts) == 5
-	if placeInsideDistrictInformed {
-		return parts[1], parts[2], parts[len(parts)-2]
-	}
-	// ex: [Trim, Co Meath, C15 HN90]
-	districtAndCityAreEqual := len(parts) < 4
-	if districtAndCityAreEqual {
-		return parts[0], parts[0], parts[len(parts)-2]
-	}
-	// ex: [Adare Heritage Centre, Adare, Co. Limerick, V94 DWV7]
-	return parts[0], parts[1], parts[len(parts)-2]
+// collectAddressLines gives the lines of the address, which are broken by <br>,
+// ex: [Ross Castle, Ross Road, Killarney, Co. Kerry, V93 V304]
+func (ie *heritageirelandEnricher) collectAddressLines(doc *goquery.Document) []string {
+	var lines []string
+	doc.Find("#place--contact div p.address").First().Contents().Each(func(i int, s *goquery.Selection) {
+		if goquery.NodeName(s) == "#text" {
+			lines = append(lines, s.Text())
+		}
+	})
+	return lines
 }
 
 func (ie *heritageirelandEnricher) collectImage(doc *goquery.Document) string {
@@ -179,22 +170,45 @@ func (ie *heritageirelandEnricher) collectContactInfo(doc *goquery.Document) *ca
 	return nil
 }
 
-func (ie *heritageirelandEnricher) collectVisitingInfo(doc *goquery.Document) *castle.VisitingInfo {
+func (ie *heritageirelandEnricher) collectVisitingInfo(doc *goquery.Document, link string) *castle.VisitingInfo {
+	workingHours := ie.collectHorkingHours(doc)
+	facilities := collectFacilities(doc, heritageIrelandFacilities, link)
+	admission := admissionOf(ie.collectAdmission(doc), &facilities, link)
 	return &castle.VisitingInfo{
-		WorkingHours: ie.collectHorkingHours(doc),
-		Facilities: &castle.Facilities{
-			AssistanceDogsAllowed: doc.Find(".fa-dog").Length() > 0,
-			Giftshops:             doc.Find(".fa-shopping-bag").Length() > 0,
-			WheelchairSupport:     doc.Find(".fa-wheelchair").Length() > 0,
-			Restrooms:             doc.Find(".fa-toilet").Length() > 0,
-			PinicArea:             doc.Find(".fa-tree").Length() > 0,
-			Exhibitions:           doc.Find(".fa-vector-square").Length() > 0,
-			Cafe:                  doc.Find(".fa-coffee").Length() > 0,
-			Parking:               doc.Find(".fa-car-alt").Length() > 0,
-		},
+		WorkingHours: workingHours,
+		OpeningHours: ie.collectOpeningHours(doc, workingHours),
+		Facilities:   facilities,
+		Admission:    admission,
 	}
 }
 
+// collectAdmission reads what follows the Admission heading, given as
+// paragraphs, lists or tables of fees like "Adult: €5.00".
+func (ie heritageirelandEnricher) collectAdmission(doc *goquery.Document) castle.Admission {
+	var admission castle.Admission
+	doc.Find("h3").Each(func(i int, h3 *goquery.Selection) {
+		if strings.TrimSpace(h3.Text()) != "Admission" {
+			return
+		}
+		h3.NextAll().Each(func(j int, s *goquery.Selection) {
+			rows := s.Find("li, tr")
+			if rows.Length() == 0 {
+				collectAdmissionLine(&admission, s.Text())
+				return
+			}
+			rows.Each(func(k int, row *goquery.Selection) {
+				if cells := row.Find("td, th"); cells.Length() > 1 {
+					collectAdmissionLine(&admission, cells.First().Text()+": "+cells.Last().Text())
+					return
+				}
+				collectAdmissionLine(&admission, row.Text())
+			})
+		})
+		admission.BookingURL = collectBookingURL(h3.Parent())
+	})
+	return admission
+}
+
 // we can use id place--opening
 func (ie heritageirelandEnricher) collectHorkingHours(doc *goquery.Document) string {
 	replacer := strings.NewReplacer(
@@ -218,3 +232,35 @@ func (ie heritageirelandEnricher) collectHorkingHours(doc *goquery.Document) str
 
 	return openingDates
 }
+
+// collectOpeningHours parses the opening dates along with the seasons listed
+// on the accordion, which override them. Any part that can't be parsed leaves
+// the castle with the working hours as text only.
+func (ie heritageirelandEnricher) collectOpeningHours(doc *goquery.Document, workingHours string) *openinghours.Schedule {
+	var schedule openinghours.Schedule
+	year := 0
+	if parsed, err := openinghours.ParseHeritageIreland(workingHours); err == nil {
+		schedule = parsed
+		year = parsed.Rules[0].Dates.To.Year
+	}
+
+	var seasonErr error
+	doc.Find("section#place--opening dl.accordion dt").Each(func(i int, dt *goquery.Selection) {
+		var lines []string
+		dt.Next().Find("p").Each(func(j int, p *goquery.Selection) {
+			if line := strings.TrimSpace(p.Text()); line != "" {
+				lines = append(lines, line)
+			}
+		})
+		rule, err := openinghours.ParseHeritageIrelandSeason(dt.Text(), lines, year)
+		if err != nil {
+			seasonErr = err
+			return
+		}
+		schedule.Rules = append(schedule.Rules, rule)
+	})
+	if seasonErr != nil || len(schedule.Rules) == 0 {
+		return nil
+	}
+	return &schedule
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/buarki/find-castles/castle"
 	"github.com/buarki/find-castles/fileloader"
 	"github.com/buarki/find-castles/httpclient"
+	"github.com/google/go-cmp/cmp"
 )
 
 const (
@@ -45,8 +46,9 @@ func TestCollectIrishCastlesToEnrich(t *testing.T) {
 
 func TestEnrich(t *testing.T) {
 	testCases := []struct {
-		html   string
-		castle castle.Model
+		html             string
+		castle           castle.Model
+		expectedPostcode string
 	}{
 		{
 			html: `
@@ -68,6 +70,7 @@ func TestEnrich(t *testing.T) {
 				City:     "adare",
 				State:    "co. limerick",
 			},
+			expectedPostcode: "V94 DWV7",
 		},
 		{
 			html: `
@@ -87,6 +90,7 @@ func TestEnrich(t *testing.T) {
 				City:     "trim",
 				State:    "co meath",
 			},
+			expectedPostcode: "C15 HN90",
 		},
 		{
 			html: `
@@ -108,6 +112,7 @@ func TestEnrich(t *testing.T) {
 				City:     "killarney",
 				State:    "co. kerry",
 			},
+			expectedPostcode: "V93 V304",
 		},
 	}
 
@@ -131,6 +136,9 @@ func TestEnrich(t *testing.T) {
 		if castle.District != tt.castle.District {
 			t.Errorf("expected District [%s], got [%s]", tt.castle.District, castle.District)
 		}
+		if castle.Address == nil || castle.Address.Postcode != tt.expectedPostcode {
+			t.Errorf("expected postcode [%s], got %v", tt.expectedPostcode, castle.Address)
+		}
 	}
 }
 
@@ -153,6 +161,154 @@ func TestExtractPictureOfHeritageIreland(t *testing.T) {
 	}
 }
 
+func TestCollectFacilitiesOfHeritageIreland(t *testing.T) {
+	content, err := fileloader.LoadHTMLFile(irishCastlePageHTMLPath)
+	if err != nil {
+		t.Errorf("expected to have err nil, got [%v]", err)
+	}
+	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(content))
+	if err != nil {
+		t.Errorf("expected to have err nil, got [%v]", err)
+	}
+	link := "https://heritageireland.ie/visit/places-to-visit/adare-castle/"
+	expected := castle.Facilities{
+		castle.Cafe:                  {Availability: castle.Available, Source: link},
+		castle.Parking:               {Availability: castle.Available, Source: link},
+		castle.Restrooms:             {Availability: castle.Available, Source: link},
+		castle.AccessibleToilets:     {Availability: castle.Available, Source: link},
+		castle.AssistanceDogsAllowed: {Availability: castle.Available, Source: link},
+	}
+	e := heritageirelandEnricher{}
+
+	received := e.collectVisitingInfo(doc, link).Facilities
+
+	if diff := cmp.Diff(expected, received); diff != "" {
+		t.Errorf("diff: %v", diff)
+	}
+}
+
+func TestCollectFacilitiesWithoutAnyShown(t *testing.T) {
+	doc, err := goquery.NewDocumentFromReader(bytes.NewReader([]byte(`<div id="facilities"><h2>Facilities</h2></div>`)))
+	if err != nil {
+		t.Errorf("expected to have err nil, got [%v]", err)
+	}
+
+	received := collectFacilities(doc, heritageIrelandFacilities, "https://heritageireland.ie/")
+
+	if received != nil {
+		t.Errorf("expected facilities to be unknown, got %v", received)
+	}
+}
+
+func TestCollectFacilitiesNotShown(t *testing.T) {
+	doc, err := goquery.NewDocumentFromReader(bytes.NewReader([]byte(`<div id="facilities"><ul><li class="cafe"><span>Café</span></li></ul></div>`)))
+	if err != nil {
+		t.Errorf("expected to have err nil, got [%v]", err)
+	}
+
+	received := collectFacilities(doc, heritageIrelandFacilities, "https://heritageireland.ie/")
+
+	if availability := received.Availability(castle.Cafe); availability != castle.Available {
+		t.Errorf("expected cafe to be available, got [%s]", availability)
+	}
+	if availability := received.Availability(castle.Parking); availability != castle.NotAvailable {
+		t.Errorf("expected parking not to be available, got [%s]", availability)
+	}
+	if availability := received.Availability(castle.AudioGuide); availability != castle.AvailabilityUnknown {
+		t.Errorf("expected audio guide to be unknown, got [%s]", availability)
+	}
+}
+
+func TestCollectAdmissionOfHeritageIreland(t *testing.T) {
+	link := "https://heritageireland.ie/visit/places-to-visit/trim-castle/"
+	testCases := []struct {
+		name              string
+		htmlChunk         []byte
+		expectedAdmission *castle.Admission
+		expectedFee       castle.Availability
+	}{
+		{
+			name: "fees and heritage card",
+			htmlChunk: []byte(`
+			<div>
+				<h3>Admission</h3>
+				<ul>
+					<li>Adult: €5.00</li>
+					<li>Group/Senior: €4.00</li>
+					<li>Child/Student: €3.00</li>
+					<li>Family: €13.00</li>
+				</ul>
+				<p>Free admission with the Heritage Card, OPW annual membership.</p>
+				<p><a href="https://trim-castle.ticketsolve.com/">Book your tickets online</a></p>
+			</div>
+			`),
+			expectedAdmission: &castle.Admission{
+				Prices: []castle.Price{
+					{Category: castle.AdultTicket, Amount: 5, Currency: "EUR"},
+					{Category: castle.SeniorTicket, Amount: 4, Currency: "EUR"},
+					{Category: castle.ChildTicket, Amount: 3, Currency: "EUR"},
+					{Category: castle.StudentTicket, Amount: 3, Currency: "EUR"},
+					{Category: castle.FamilyTicket, Amount: 13, Currency: "EUR"},
+				},
+				MembershipNotes: "Free admission with the Heritage Card, OPW annual membership.",
+				BookingURL:      "https://trim-castle.ticketsolve.com/",
+			},
+			expectedFee: castle.Available,
+		},
+		{
+			name: "fees as table",
+			htmlChunk: []byte(`
+			<div>
+				<h3>Admission</h3>
+				<table>
+					<tr><td>Adult</td><td>€8.00</td></tr>
+					<tr><td>OAP</td><td>€6.00</td></tr>
+				</table>
+			</div>
+			`),
+			expectedAdmission: &castle.Admission{
+				Prices: []castle.Price{
+					{Category: castle.AdultTicket, Amount: 8, Currency: "EUR"},
+					{Category: castle.SeniorTicket, Amount: 6, Currency: "EUR"},
+				},
+			},
+			expectedFee: castle.Available,
+		},
+		{
+			name:              "free admission",
+			htmlChunk:         []byte(`<div><h3>Admission</h3><p>Free admission</p></div>`),
+			expectedAdmission: &castle.Admission{Free: true},
+			expectedFee:       castle.NotAvailable,
+		},
+		{
+			name:        "contact for details",
+			htmlChunk:   []byte(`<div><h3>Admission</h3><p>Please contact Adare Heritage Centre at 353(0)61 396666 / reception@adareheritagecentre.ie for details.</p></div>`),
+			expectedFee: castle.AvailabilityUnknown,
+		},
+	}
+
+	for _, tt := range testCases {
+		currentTT := tt
+		t.Run(currentTT.name, func(t *testing.T) {
+			t.Helper()
+			doc, err := goquery.NewDocumentFromReader(bytes.NewReader(currentTT.htmlChunk))
+			if err != nil {
+				t.Errorf("expected to have err nil, got [%v]", err)
+			}
+			e := heritageirelandEnricher{}
+
+			visitingInfo := e.collectVisitingInfo(doc, link)
+
+			if diff := cmp.Diff(currentTT.expectedAdmission, visitingInfo.Admission); diff != "" {
+				t.Errorf("diff: %v", diff)
+			}
+			if fee := visitingInfo.Facilities.Availability(castle.AdmissionFee); fee != currentTT.expectedFee {
+				t.Errorf("expected admission fee to be [%s], got [%s]", currentTT.expectedFee, fee)
+			}
+		})
+	}
+}
+
 func TestExtractContactOfHeritageIreland(t *testing.T) {
 	content, err := fileloader.LoadHTMLFile(irishCastlePageHTMLPath)
 	if err != nil {
@@ -253,3 +409,89 @@ func TestCollectWorkingHoursOfHeritageIreland(t *testing.T) {
 	}
 
 }
+
+func TestCollectOpeningHoursOfHeritageIreland(t *testing.T) {
+	testCases := []struct {
+		name                 string
+		htmlChunk            []byte
+		expectedOpeningHours string
+	}{
+		{
+			name: "dates_and_times",
+			htmlChunk: []byte(`
+			<section id="place--opening" class="section">
+				<h2>Opening Times</h2>
+				<div>
+					<p><strong>01 June &#8211; 29 September 2024</strong></p>
+					<p>09:30- 16:00</p>
+				</div>
+			</section>
+			`),
+			expectedOpeningHours: "2024 Jun 01-Sep 29 09:30-16:00",
+		},
+		{
+			name: "seasons",
+			htmlChunk: []byte(`
+			<section id="place--opening" class="section">
+				<h2>Opening Times</h2>
+				<div>
+					<p>15 March- 3 November 2024</p>
+				</div>
+				<div>
+					<h3>Seasonal Opening Times</h3>
+					<dl class="accordion">
+						<dt>15 March - 26 October<b></b></dt>
+						<dd>
+							<div>
+								<p>Daily 10:00 &#8211; 18:00</p>
+								<p>Last admission: 17:15</p>
+							</div>
+						</dd>
+						<dt>27 October - 03 November <b></b></dt>
+						<dd>
+							<div>
+								<p>Daily 10:00 &#8211; 17:00</p>
+								<p>Last admission: 16:15</p>
+							</div>
+						</dd>
+					</dl>
+				</div>
+			</section>
+			`),
+			expectedOpeningHours: `2024 Mar 15-Nov 03; 2024 Mar 15-Oct 26 10:00-18:00 "last admission 17:15"; 2024 Oct 27-Nov 03 10:00-17:00 "last admission 16:15"`,
+		},
+		{
+			name: "unrecognized",
+			htmlChunk: []byte(`
+			<section id="place--opening" class="section">
+				<h2>Opening Times</h2>
+				<div>
+					<p>Closed for conservation works</p>
+				</div>
+			</section>
+			`),
+		},
+	}
+	e := heritageirelandEnricher{}
+
+	for _, tt := range testCases {
+		currentTT := tt
+		t.Run(currentTT.name, func(t *testing.T) {
+			t.Helper()
+			doc, err := goquery.NewDocumentFromReader(bytes.NewReader(currentTT.htmlChunk))
+			if err != nil {
+				t.Errorf("expected to have err nil, got [%v]", err)
+			}
+
+			collected := e.collectOpeningHours(doc, e.collectHorkingHours(doc))
+
+			var received string
+			if collected != nil {
+				received = collected.String()
+			}
+			if received != currentTT.expectedOpeningHours {
+				t.Errorf("expected to have [%s], got [%s]", currentTT.expectedOpeningHours, received)
+			}
+		})
+	}
+}
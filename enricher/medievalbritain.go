@@ -10,6 +10,7 @@ import (
 
 	"github.com/PuerkitoBio/goquery"
 	"github.com/buarki/find-castles/castle"
+	"github.com/buarki/find-castles/openinghours"
 	"golang.org/x/sync/errgroup"
 )
 
@@ -24,6 +25,20 @@ const (
 	workersToExtractCastlesFromHTML = 3
 )
 
+var (
+	// icons shown for the facilities of each castle
+	medievalBritainFacilities = map[castle.Facility]string{
+		castle.AssistanceDogsAllowed: ".fa-dog",
+		castle.Giftshops:             ".fa-shopping-bag",
+		castle.WheelchairSupport:     ".fa-wheelchair",
+		castle.Restrooms:             ".fa-toilet",
+		castle.PinicArea:             ".fa-tree",
+		castle.Exhibitions:           ".fa-vector-square",
+		castle.Cafe:                  ".fa-coffee",
+		castle.Parking:               ".fa-car-alt",
+	}
+)
+
 type medievalbritainEnricher struct {
 	httpClient *http.Client
 	fetchHTML  func(ctx context.Context, link string, httpClient *http.Client) ([]byte, error)
@@ -166,6 +181,8 @@ func (be *medievalbritainEnricher) EnrichCastle(ctx context.Context, c castle.Mo
 	if err != nil {
 		return castle.Model{}, err
 	}
+	// the name as the source gives it, before being cleaned
+	enrichedCastled.SetLocalizedName(castle.English, enrichedCastled.Name)
 	enrichedCastled.CleanFields()
 	return enrichedCastled, nil
 }
@@ -209,10 +226,10 @@ func (be *medievalbritainEnricher) extractUkState(doc *goquery.Document, c castl
 }
 
 /*
-About the UK city
+About the UK address
 
-From inspection we could see that the query '.elementor-widget-container div.elementor-text-editor.elementor-clearfix p a”
-provides the state as its first item.
+The paragraph after <strong>Address</strong> has the address, its lines
+broken by commas or <br>, the post town sharing the last one with the postcode.
 
 # An example of chunk in which we parse is bellow one
 <div class="elementor-widget-container">
@@ -224,18 +241,19 @@ provides the state as its first item.
 		</div>
 	</div>
 */
-func (be *medievalbritainEnricher) extractUkCity(doc *goquery.Document, c castle.Model) (string, error) {
-	var city string
+func (be *medievalbritainEnricher) collectAddressLines(doc *goquery.Document) []string {
+	var lines []string
 	doc.Find(".elementor-text-editor.elementor-clearfix p").Each(func(i int, s *goquery.Selection) {
-		if s.Text() == "Address" {
-			city = s.Next().Text()
-			before, _, found := strings.Cut(city, ",")
-			if found {
-				city = before
-			}
+		if strings.TrimSpace(s.Text()) != "Address" {
+			return
 		}
+		s.Next().Contents().Each(func(j int, line *goquery.Selection) {
+			if goquery.NodeName(line) == "#text" {
+				lines = append(lines, strings.Split(line.Text(), ",")...)
+			}
+		})
 	})
-	return city, nil
+	return lines
 }
 
 func (be *medievalbritainEnricher) extractDataOfUKCastle(rawHTML []byte, c castle.Model) (castle.Model, error) {
@@ -247,23 +265,20 @@ func (be *medievalbritainEnricher) extractDataOfUKCastle(rawHTML []byte, c castl
 	if err != nil {
 		return castle.Model{}, err
 	}
-	city, err := be.extractUkCity(doc, c)
-	if err != nil {
-		return castle.Model{}, err
-	}
-	return castle.Model{
+	enrichedCastle := castle.Model{
 		Name:                  c.Name,
 		Country:               c.Country,
 		CurrentEnrichmentLink: c.CurrentEnrichmentLink,
 		State:                 state,
-		City:                  city,
 		PictureURL:            be.collectImage(doc),
 		Coordinates:           be.collectCoordinates(doc),
 		Contact:               be.collectContactInfo(doc),
 		Sources:               []string{c.CurrentEnrichmentLink},
-		VisitingInfo:          be.collectVisitingInfo(doc),
+		VisitingInfo:          be.collectVisitingInfo(doc, c.CurrentEnrichmentLink),
 		PropertyCondition:     castle.Unknown,
-	}, nil
+	}
+	enrichedCastle.SetAddress(castle.ParseAddress(castle.UK, be.collectAddressLines(doc)))
+	return enrichedCastle, nil
 }
 
 func (be *medievalbritainEnricher) collectImage(doc *goquery.Document) string {
@@ -331,22 +346,49 @@ func (be *medievalbritainEnricher) collectContactInfo(doc *goquery.Document) *ca
 	return nil
 }
 
-func (be *medievalbritainEnricher) collectVisitingInfo(doc *goquery.Document) *castle.VisitingInfo {
+func (be *medievalbritainEnricher) collectVisitingInfo(doc *goquery.Document, link string) *castle.VisitingInfo {
+	workingHours := be.collectHorkingHours(doc)
+	var openingHours *openinghours.Schedule
+	// hours that can't be parsed are only kept as text
+	if schedule, err := openinghours.ParseMedievalBritain(workingHours); err == nil {
+		openingHours = &schedule
+	}
+	facilities := collectFacilities(doc, medievalBritainFacilities, link)
+	admission := admissionOf(be.collectAdmission(doc), &facilities, link)
 	return &castle.VisitingInfo{
-		WorkingHours: be.collectHorkingHours(doc),
-		Facilities: &castle.Facilities{
-			AssistanceDogsAllowed: doc.Find(".fa-dog").Length() > 0,
-			Giftshops:             doc.Find(".fa-shopping-bag").Length() > 0,
-			WheelchairSupport:     doc.Find(".fa-wheelchair").Length() > 0,
-			Restrooms:             doc.Find(".fa-toilet").Length() > 0,
-			PinicArea:             doc.Find(".fa-tree").Length() > 0,
-			Exhibitions:           doc.Find(".fa-vector-square").Length() > 0,
-			Cafe:                  doc.Find(".fa-coffee").Length() > 0,
-			Parking:               doc.Find(".fa-car-alt").Length() > 0,
-		},
+		WorkingHours: workingHours,
+		OpeningHours: openingHours,
+		Facilities:   facilities,
+		Admission:    admission,
 	}
 }
 
+// the searching key for this one is <strong>Prices</strong>, followed by a
+// table of prices on the same or the next widget
+func (be *medievalbritainEnricher) collectAdmission(doc *goquery.Document) castle.Admission {
+	var admission castle.Admission
+	doc.Find(".elementor-text-editor p").Each(func(i int, s *goquery.Selection) {
+		if strings.TrimSpace(s.Find("strong").Text()) != "Prices" {
+			return
+		}
+		widget := s.Closest(".elementor-element")
+		prices := widget
+		if widget.Find("table").Length() == 0 {
+			prices = widget.Next()
+		}
+		prices.Find("tr").Each(func(j int, tr *goquery.Selection) {
+			if cells := tr.Find("td"); cells.Length() > 1 {
+				collectAdmissionLine(&admission, cells.First().Text()+": "+cells.Last().Text())
+			}
+		})
+		s.NextAll().Each(func(j int, p *goquery.Selection) {
+			collectAdmissionLine(&admission, p.Text())
+		})
+		admission.BookingURL = collectBookingURL(widget.AddSelection(prices))
+	})
+	return admission
+}
+
 // the searching key for this one is <strong>Hours</strong>
 func (be *medievalbritainEnricher) collectHorkingHours(doc *goquery.Document) string {
 	var hours []string
@@ -5,11 +5,13 @@ import (
 	"context"
 	"net/http"
 	"testing"
+	"time"
 
 	"github.com/PuerkitoBio/goquery"
 	"github.com/buarki/find-castles/castle"
 	"github.com/buarki/find-castles/fileloader"
 	"github.com/buarki/find-castles/httpclient"
+	"github.com/buarki/find-castles/openinghours"
 	"github.com/google/go-cmp/cmp"
 )
 
@@ -51,7 +53,7 @@ func TestExtractBritishCastleInfo(t *testing.T) {
 
 	expectedCastle := castle.Model{
 		Country:     castle.UK,
-		City:        "windsor sl4 1nj",
+		City:        "windsor",
 		State:       "berkshire, greaterlondon",
 		PictureURL:  "https://medievalbritain.com/wp-content/uploads/2021/05/medieval-castles-england_windsor.jpg",
 		Coordinates: "51°29'0\"N,00°36'15\"W",
@@ -67,6 +69,9 @@ func TestExtractBritishCastleInfo(t *testing.T) {
 	if receivedCastle.City != expectedCastle.City {
 		t.Errorf("expected city to be [%s], got [%s]", expectedCastle.City, receivedCastle.City)
 	}
+	if receivedCastle.Address == nil || receivedCastle.Address.Postcode != "SL4 1NJ" {
+		t.Errorf("expected postcode to be [SL4 1NJ], got %v", receivedCastle.Address)
+	}
 	if receivedCastle.State != expectedCastle.State {
 		t.Errorf("expected State to be [%s], got [%s]", expectedCastle.State, receivedCastle.State)
 	}
@@ -242,22 +247,41 @@ func TestExtracVisitingInfotOfMedievalBritain(t *testing.T) {
 	if err != nil {
 		t.Errorf("expected to have err nil, got [%v]", err)
 	}
+	link := "https://medievalbritain.com/type/medieval-castles/alnwick-castle/"
 	expectedVisitingInfo := &castle.VisitingInfo{
 		WorkingHours: "Summer: 10:00 - 16:00,Winter: 10:00 - 15:00",
-		Facilities: &castle.Facilities{
-			AssistanceDogsAllowed: true,
-			Giftshops:             true,
-			WheelchairSupport:     true,
-			Restrooms:             true,
-			PinicArea:             true,
-			Exhibitions:           true,
-			Cafe:                  true,
-			Parking:               true,
+		OpeningHours: &openinghours.Schedule{Rules: []openinghours.Rule{
+			{
+				Dates: &openinghours.DateRange{From: openinghours.Date{Month: time.April, Day: 1}, To: openinghours.Date{Month: time.September, Day: 30}},
+				Times: []openinghours.TimeRange{{From: 10 * 60, To: 16 * 60}},
+			},
+			{
+				Dates: &openinghours.DateRange{From: openinghours.Date{Month: time.October, Day: 1}, To: openinghours.Date{Month: time.March, Day: 31}},
+				Times: []openinghours.TimeRange{{From: 10 * 60, To: 15 * 60}},
+			},
+		}},
+		Facilities: castle.Facilities{
+			castle.AssistanceDogsAllowed: {Availability: castle.Available, Source: link},
+			castle.Giftshops:             {Availability: castle.Available, Source: link},
+			castle.WheelchairSupport:     {Availability: castle.Available, Source: link},
+			castle.Restrooms:             {Availability: castle.Available, Source: link},
+			castle.PinicArea:             {Availability: castle.Available, Source: link},
+			castle.Exhibitions:           {Availability: castle.Available, Source: link},
+			castle.Cafe:                  {Availability: castle.Available, Source: link},
+			castle.Parking:               {Availability: castle.Available, Source: link},
+			castle.AdmissionFee:          {Availability: castle.Available, Source: link},
 		},
+		Admission: &castle.Admission{Prices: []castle.Price{
+			{Category: castle.AdultTicket, Amount: 23.5, Currency: "GBP"},
+			{Category: castle.ChildTicket, Amount: 13.5, Currency: "GBP"},
+			{Category: castle.SeniorTicket, Amount: 21.2, Currency: "GBP"},
+			{Category: castle.StudentTicket, Amount: 21.2, Currency: "GBP"},
+			{Category: castle.FamilyTicket, Amount: 60.5, Currency: "GBP"},
+		}},
 	}
 	e := medievalbritainEnricher{}
 
-	collectedVisitingInfo := e.collectVisitingInfo(doc)
+	collectedVisitingInfo := e.collectVisitingInfo(doc, link)
 
 	if collectedVisitingInfo == nil {
 		t.Errorf("expected contact to not be null")
@@ -269,6 +293,75 @@ func TestExtracVisitingInfotOfMedievalBritain(t *testing.T) {
 	}
 }
 
+func TestCollectAdmissionOfMedievalBritain(t *testing.T) {
+	testCases := []struct {
+		name              string
+		htmlChunk         []byte
+		expectedAdmission castle.Admission
+	}{
+		{
+			name: "prices on the next widget",
+			htmlChunk: []byte(`
+			<div class="elementor-element elementor-widget-text-editor">
+				<div class="elementor-text-editor elementor-clearfix"><p><strong>Prices</strong></p></div>
+			</div>
+			<div class="elementor-element elementor-widget-text-editor">
+				<div class="elementor-text-editor elementor-clearfix">
+					<table>
+						<tr><td>Adult</td><td>£14.00</td></tr>
+						<tr><td>Child (5-17 years)</td><td>£8.40</td></tr>
+						<tr><td>Concession</td><td>£12.60</td></tr>
+					</table>
+					<p><a href="https://www.english-heritage.org.uk/visit/places/dover-castle/prices-and-opening-times/">Book tickets</a></p>
+				</div>
+			</div>
+			`),
+			expectedAdmission: castle.Admission{
+				Prices: []castle.Price{
+					{Category: castle.AdultTicket, Amount: 14, Currency: "GBP"},
+					{Category: castle.ChildTicket, Amount: 8.4, Currency: "GBP"},
+				},
+				BookingURL: "https://www.english-heritage.org.uk/visit/places/dover-castle/prices-and-opening-times/",
+			},
+		},
+		{
+			name: "free entry",
+			htmlChunk: []byte(`
+			<div class="elementor-element elementor-widget-text-editor">
+				<div class="elementor-text-editor elementor-clearfix">
+					<p><strong>Prices</strong></p>
+					<p>Free entry</p>
+				</div>
+			</div>
+			`),
+			expectedAdmission: castle.Admission{Free: true},
+		},
+		{
+			name:              "without prices",
+			htmlChunk:         []byte(`<div class="elementor-text-editor"><p><strong>Hours</strong></p></div>`),
+			expectedAdmission: castle.Admission{},
+		},
+	}
+
+	for _, tt := range testCases {
+		currentTT := tt
+		t.Run(currentTT.name, func(t *testing.T) {
+			t.Helper()
+			doc, err := goquery.NewDocumentFromReader(bytes.NewReader(currentTT.htmlChunk))
+			if err != nil {
+				t.Errorf("expected to have err nil, got [%v]", err)
+			}
+			e := medievalbritainEnricher{}
+
+			collectedAdmission := e.collectAdmission(doc)
+
+			if diff := cmp.Diff(currentTT.expectedAdmission, collectedAdmission); diff != "" {
+				t.Errorf("diff: %v", diff)
+			}
+		})
+	}
+}
+
 func TestCollectWorkingHoursOfMedievalBritain(t *testing.T) {
 	testCases := []struct {
 		name                 string
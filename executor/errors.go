@@ -0,0 +1,33 @@
+package executor
+
+import (
+	"fmt"
+
+	"github.com/buarki/find-castles/enricher"
+)
+
+// EnrichmentError tells which source failed and, when the failure happened
+// while enriching a single castle, which link was being enriched.
+type EnrichmentError struct {
+	Source enricher.Source
+	Link   string
+	Err    error
+}
+
+func (e *EnrichmentError) Error() string {
+	if e.IsCollectionFailure() {
+		return fmt.Sprintf("failed to collect castles of [%s], got %v", e.Source, e.Err)
+	}
+	return fmt.Sprintf("failed to enrich [%s] from [%s], got %v", e.Link, e.Source, e.Err)
+}
+
+func (e *EnrichmentError) Unwrap() error {
+	return e.Err
+}
+
+// IsCollectionFailure reports whether the error happened while collecting
+// the list of castles of a source, meaning that some castles of it may have
+// not been seen at all.
+func (e *EnrichmentError) IsCollectionFailure() bool {
+	return e.Link == ""
+}
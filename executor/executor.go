@@ -32,10 +32,10 @@ func (ex *EnchimentExecutor) Enrich(ctx context.Context) (<-chan castle.Model, <
 	enrichedCastles := make(chan castle.Model)
 	errChan := make(chan error)
 
-	enrichersChan := make(chan enricher.Enricher, len(ex.enrichers))
+	enrichersChan := make(chan enricher.Source, len(ex.enrichers))
 
-	for _, enricher := range ex.enrichers {
-		enrichersChan <- enricher
+	for source := range ex.enrichers {
+		enrichersChan <- source
 	}
 	close(enrichersChan)
 
@@ -51,11 +51,11 @@ func (ex *EnchimentExecutor) Enrich(ctx context.Context) (<-chan castle.Model, <
 				case <-ctx.Done():
 					fmt.Println("done getting castle channels")
 					return
-				case enricher, ok := <-enrichersChan:
+				case source, ok := <-enrichersChan:
 					if !ok {
 						return
 					}
-					ex.collectCastlesToEnrich(ctx, enricher, castlesToEnrichChan, errChan)
+					ex.collectCastlesToEnrich(ctx, source, castlesToEnrichChan, errChan)
 				}
 			}
 		}()
@@ -83,10 +83,17 @@ func (ex *EnchimentExecutor) Enrich(ctx context.Context) (<-chan castle.Model, <
 					if !ok {
 						return
 					}
-					enrichedCastle, err := ex.enrichers[enricher.Source(c.CurrentEnrichmentSource)].EnrichCastle(ctx, c)
+					source := enricher.Source(c.CurrentEnrichmentSource)
+					enrichedCastle, err := ex.enrichers[source].EnrichCastle(ctx, c)
 					if err != nil {
-						errChan <- err
+						errChan <- &EnrichmentError{
+							Source: source,
+							Link:   c.CurrentEnrichmentLink,
+							Err:    err,
+						}
 					} else {
+						// not every enricher keeps it, but it is needed to know where the castle came from
+						enrichedCastle.CurrentEnrichmentSource = c.CurrentEnrichmentSource
 						enrichedCastles <- enrichedCastle
 					}
 				}
@@ -106,11 +113,11 @@ func (ex *EnchimentExecutor) Enrich(ctx context.Context) (<-chan castle.Model, <
 
 func (ex *EnchimentExecutor) collectCastlesToEnrich(
 	ctx context.Context,
-	enricher enricher.Enricher,
+	source enricher.Source,
 	castlesToEnrichChan chan castle.Model,
 	errChan chan error,
 ) {
-	castlesChan, eChan := enricher.CollectCastlesToEnrich(ctx)
+	castlesChan, eChan := ex.enrichers[source].CollectCastlesToEnrich(ctx)
 	for {
 		select {
 		case <-ctx.Done():
@@ -124,7 +131,10 @@ func (ex *EnchimentExecutor) collectCastlesToEnrich(
 			if !ok {
 				return
 			}
-			errChan <- e
+			errChan <- &EnrichmentError{
+				Source: source,
+				Err:    e,
+			}
 		}
 	}
 }
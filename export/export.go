@@ -0,0 +1,93 @@
+package export
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/buarki/find-castles/castle"
+	"github.com/buarki/find-castles/dataset"
+)
+
+type Format string
+
+const (
+	GeoJSON Format = "geojson"
+	KML     Format = "kml"
+	GPX     Format = "gpx"
+	CSV     Format = Format(dataset.CSV)
+	JSONL   Format = Format(dataset.JSONL)
+)
+
+func (f Format) ContentType() string {
+	switch f {
+	case GeoJSON:
+		return "application/geo+json"
+	case KML:
+		return "application/vnd.google-earth.kml+xml"
+	case GPX:
+		return "application/gpx+xml"
+	case CSV:
+		return "text/csv"
+	case JSONL:
+		return "application/jsonl"
+	default:
+		return "application/octet-stream"
+	}
+}
+
+func Write(w io.Writer, castles []castle.Model, format Format) error {
+	switch format {
+	case GeoJSON:
+		return WriteGeoJSON(w, castles)
+	case KML:
+		return WriteKML(w, castles)
+	case GPX:
+		return WriteGPX(w, castles)
+	case CSV, JSONL:
+		return writeDataset(w, castles, dataset.Format(format))
+	default:
+		return fmt.Errorf("unsupported export format [%s]", format)
+	}
+}
+
+// writeDataset writes every castle, even the ones without coordinates.
+func writeDataset(w io.Writer, castles []castle.Model, format dataset.Format) error {
+	writer, err := dataset.NewWriter(w, format)
+	if err != nil {
+		return err
+	}
+	for _, c := range castles {
+		if err := writer.Write(c); err != nil {
+			return err
+		}
+	}
+	return writer.Flush()
+}
+
+// placemark is a castle with what every map format needs. Castles without
+// valid coordinates can't be placed on a map, so they are left out.
+type placemark struct {
+	castle   castle.Model
+	location castle.Coordinates
+	webName  string
+}
+
+func placemarksOf(castles []castle.Model) ([]placemark, error) {
+	placemarks := make([]placemark, 0, len(castles))
+	for _, c := range castles {
+		location, ok := c.Location()
+		if !ok {
+			continue
+		}
+		webName, err := c.WebName()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get web name of castle [%s], got %v", c.Name, err)
+		}
+		placemarks = append(placemarks, placemark{
+			castle:   c,
+			location: location,
+			webName:  webName,
+		})
+	}
+	return placemarks, nil
+}
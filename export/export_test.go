@@ -0,0 +1,94 @@
+package export
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/buarki/find-castles/castle"
+)
+
+var (
+	castlesToExport = []castle.Model{
+		{
+			Name:              "trim",
+			Country:           castle.Ireland,
+			Coordinates:       "53.5545°N,6.7900°W",
+			PropertyCondition: castle.Intact,
+			PictureURL:        "https://heritageireland.ie/trim.jpg",
+			Sources:           []string{"https://heritageireland.ie/places-to-visit/trim-castle/"},
+		},
+		{
+			Name:    "castle without coordinates",
+			Country: castle.Ireland,
+		},
+	}
+)
+
+func TestWriteGeoJSON(t *testing.T) {
+	var b bytes.Buffer
+	if err := Write(&b, castlesToExport, GeoJSON); err != nil {
+		t.Fatalf("expected err nil, got %v", err)
+	}
+
+	var collection geoJSONFeatureCollection
+	if err := json.Unmarshal(b.Bytes(), &collection); err != nil {
+		t.Fatalf("expected valid JSON, got %v", err)
+	}
+	if collection.Type != "FeatureCollection" {
+		t.Errorf("expected FeatureCollection, got [%s]", collection.Type)
+	}
+	if len(collection.Features) != 1 {
+		t.Fatalf("expected only the castle with coordinates, got %d features", len(collection.Features))
+	}
+	feature := collection.Features[0]
+	if feature.Geometry.Coordinates != [2]float64{-6.79, 53.5545} {
+		t.Errorf("expected longitude first, got %v", feature.Geometry.Coordinates)
+	}
+	if feature.Properties.WebName != "trim-ie" {
+		t.Errorf("expected webName [trim-ie], got [%s]", feature.Properties.WebName)
+	}
+}
+
+func TestWriteKMLAndGPX(t *testing.T) {
+	testCases := []struct {
+		format   Format
+		expected []string
+	}{
+		{
+			format: KML,
+			expected: []string{
+				`<kml xmlns="http://www.opengis.net/kml/2.2">`,
+				`<coordinates>-6.790000,53.554500</coordinates>`,
+				`<Data name="webName">`,
+			},
+		},
+		{
+			format: GPX,
+			expected: []string{
+				`<wpt lat="53.554500" lon="-6.790000">`,
+				`<link href="https://heritageireland.ie/places-to-visit/trim-castle/"></link>`,
+			},
+		},
+	}
+
+	for _, tt := range testCases {
+		currentTT := tt
+		t.Run(string(currentTT.format), func(t *testing.T) {
+			t.Helper()
+			var b bytes.Buffer
+			if err := Write(&b, castlesToExport, currentTT.format); err != nil {
+				t.Fatalf("expected err nil, got %v", err)
+			}
+			for _, expected := range currentTT.expected {
+				if !strings.Contains(b.String(), expected) {
+					t.Errorf("expected [%s] on\n%s", expected, b.String())
+				}
+			}
+			if strings.Contains(b.String(), "castle without coordinates") {
+				t.Errorf("expected castle without coordinates to be left out")
+			}
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/buarki/find-castles/castle"
+)
+
+type geoJSONFeatureCollection struct {
+	Type     string           `json:"type"`
+	Features []geoJSONFeature `json:"features"`
+}
+
+type geoJSONFeature struct {
+	Type       string            `json:"type"`
+	Geometry   geoJSONPoint      `json:"geometry"`
+	Properties geoJSONProperties `json:"properties"`
+}
+
+type geoJSONPoint struct {
+	Type string `json:"type"`
+	// longitude first, as GeoJSON requires
+	Coordinates [2]float64 `json:"coordinates"`
+}
+
+type geoJSONProperties struct {
+	Name              string                   `json:"name"`
+	WebName           string                   `json:"webName"`
+	Country           castle.Country           `json:"country"`
+	PropertyCondition castle.PropertyCondition `json:"propertyCondition"`
+	PictureURL        string                   `json:"pictureURL"`
+	Sources           []string                 `json:"sources"`
+}
+
+func WriteGeoJSON(w io.Writer, castles []castle.Model) error {
+	placemarks, err := placemarksOf(castles)
+	if err != nil {
+		return err
+	}
+	collection := geoJSONFeatureCollection{
+		Type:     "FeatureCollection",
+		Features: make([]geoJSONFeature, 0, len(placemarks)),
+	}
+	for _, p := range placemarks {
+		collection.Features = append(collection.Features, geoJSONFeature{
+			Type: "Feature",
+			Geometry: geoJSONPoint{
+				Type:        "Point",
+				Coordinates: [2]float64{p.location.Longitude, p.location.Latitude},
+			},
+			Properties: geoJSONProperties{
+				Name:              p.castle.Name,
+				WebName:           p.webName,
+				Country:           p.castle.Country,
+				PropertyCondition: p.castle.PropertyCondition,
+				PictureURL:        p.castle.PictureURL,
+				Sources:           p.castle.Sources,
+			},
+		})
+	}
+	if err := json.NewEncoder(w).Encode(collection); err != nil {
+		return fmt.Errorf("failed to write castles as GeoJSON, got %v", err)
+	}
+	return nil
+}
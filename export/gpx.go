@@ -0,0 +1,56 @@
+package export
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+
+	"github.com/buarki/find-castles/castle"
+)
+
+type gpxDocument struct {
+	XMLName   xml.Name      `xml:"gpx"`
+	Xmlns     string        `xml:"xmlns,attr"`
+	Version   string        `xml:"version,attr"`
+	Creator   string        `xml:"creator,attr"`
+	Waypoints []gpxWaypoint `xml:"wpt"`
+}
+
+type gpxWaypoint struct {
+	Latitude    string    `xml:"lat,attr"`
+	Longitude   string    `xml:"lon,attr"`
+	Name        string    `xml:"name"`
+	Description string    `xml:"desc,omitempty"`
+	Links       []gpxLink `xml:"link"`
+	Type        string    `xml:"type"`
+}
+
+type gpxLink struct {
+	Href string `xml:"href,attr"`
+}
+
+func WriteGPX(w io.Writer, castles []castle.Model) error {
+	placemarks, err := placemarksOf(castles)
+	if err != nil {
+		return err
+	}
+	doc := gpxDocument{
+		Xmlns:   "http://www.topografix.com/GPX/1/1",
+		Version: "1.1",
+		Creator: "find-castles",
+	}
+	for _, p := range placemarks {
+		waypoint := gpxWaypoint{
+			Latitude:    fmt.Sprintf("%f", p.location.Latitude),
+			Longitude:   fmt.Sprintf("%f", p.location.Longitude),
+			Name:        p.castle.Name,
+			Description: fmt.Sprintf("%s, %s (%s)", p.webName, p.castle.Country, p.castle.PropertyCondition),
+			Type:        "castle",
+		}
+		for _, source := range p.castle.Sources {
+			waypoint.Links = append(waypoint.Links, gpxLink{Href: source})
+		}
+		doc.Waypoints = append(doc.Waypoints, waypoint)
+	}
+	return writeXML(w, doc, "GPX")
+}
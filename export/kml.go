@@ -0,0 +1,71 @@
+package export
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+
+	"github.com/buarki/find-castles/castle"
+)
+
+type kmlDocument struct {
+	XMLName  xml.Name `xml:"kml"`
+	Xmlns    string   `xml:"xmlns,attr"`
+	Document struct {
+		Name       string         `xml:"name"`
+		Placemarks []kmlPlacemark `xml:"Placemark"`
+	} `xml:"Document"`
+}
+
+type kmlPlacemark struct {
+	Name         string    `xml:"name"`
+	Description  string    `xml:"description,omitempty"`
+	ExtendedData []kmlData `xml:"ExtendedData>Data"`
+	Point        struct {
+		Coordinates string `xml:"coordinates"`
+	} `xml:"Point"`
+}
+
+type kmlData struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:"value"`
+}
+
+func WriteKML(w io.Writer, castles []castle.Model) error {
+	placemarks, err := placemarksOf(castles)
+	if err != nil {
+		return err
+	}
+	doc := kmlDocument{Xmlns: "http://www.opengis.net/kml/2.2"}
+	doc.Document.Name = "Find Castles"
+	for _, p := range placemarks {
+		kmlPlacemark := kmlPlacemark{
+			Name:        p.castle.Name,
+			Description: p.castle.PictureURL,
+			ExtendedData: []kmlData{
+				{Name: "webName", Value: p.webName},
+				{Name: "country", Value: p.castle.Country.String()},
+				{Name: "propertyCondition", Value: p.castle.PropertyCondition.String()},
+				{Name: "pictureURL", Value: p.castle.PictureURL},
+			},
+		}
+		for _, source := range p.castle.Sources {
+			kmlPlacemark.ExtendedData = append(kmlPlacemark.ExtendedData, kmlData{Name: "source", Value: source})
+		}
+		kmlPlacemark.Point.Coordinates = fmt.Sprintf("%f,%f", p.location.Longitude, p.location.Latitude)
+		doc.Document.Placemarks = append(doc.Document.Placemarks, kmlPlacemark)
+	}
+	return writeXML(w, doc, "KML")
+}
+
+func writeXML(w io.Writer, doc any, format string) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return fmt.Errorf("failed to write castles as %s, got %v", format, err)
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(doc); err != nil {
+		return fmt.Errorf("failed to write castles as %s, got %v", format, err)
+	}
+	return nil
+}
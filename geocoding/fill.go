@@ -0,0 +1,74 @@
+package geocoding
+
+import (
+	"strings"
+
+	"github.com/buarki/find-castles/castle"
+)
+
+const (
+	// castles farther from any place than that aren't on it
+	maxLocalityDistanceInKm = 10.0
+	// subdivisions are larger than localities, but the nearest place of
+	// castles far from any may be across a border
+	maxSubdivisionDistanceInKm = 30.0
+)
+
+// Fill fills the state, the city or the coordinates the castle misses,
+// marking them as derived. Castles with coordinates get the state and city
+// of the place nearest to them, and castles without get the coordinates of
+// their town. It tells whether anything was filled.
+func (g *Geocoder) Fill(c castle.Model) (castle.Model, bool) {
+	if location, ok := c.Location(); ok {
+		// coordinates derived from the town would only give the town back
+		if c.IsDerived(castle.DerivedCoordinates) || (c.State != "" && c.City != "") {
+			return c, false
+		}
+		match, found := g.Reverse(c.Country, location)
+		if !found {
+			return c, false
+		}
+		filled, changed := c.Copy(), false
+		if c.State == "" && match.DistanceInKm <= maxSubdivisionDistanceInKm {
+			subdivision, _ := castle.ResolveSubdivision(c.Country, match.Place.Subdivision)
+			filled.State = strings.ToLower(subdivision.Name)
+			filled.StateCode = subdivision.Code
+			filled.MarkDerived(castle.DerivedState)
+			changed = true
+		}
+		if c.City == "" && match.DistanceInKm <= maxLocalityDistanceInKm {
+			filled.City = strings.ToLower(match.Place.Name)
+			filled.MarkDerived(castle.DerivedCity)
+			changed = true
+		}
+		if !changed {
+			return c, false
+		}
+		return filled, true
+	}
+
+	if c.Coordinates != "" {
+		return c, false
+	}
+	town := c.City
+	if town == "" && c.Address != nil {
+		town = c.Address.Town
+	}
+	region := c.StateCode
+	if region == "" {
+		region = c.State
+	}
+	coordinates, found := g.Forward(c.Country, town, region)
+	if !found {
+		return c, false
+	}
+	filled := c.Copy()
+	filled.Coordinates = coordinates.String()
+	filled.MarkDerived(castle.DerivedCoordinates)
+	return filled, true
+}
+
+// Fill fills the castle with the bundled places.
+func Fill(c castle.Model) (castle.Model, bool) {
+	return defaultGeocoder.Fill(c)
+}
@@ -0,0 +1,167 @@
+package geocoding
+
+import (
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/buarki/find-castles/castle"
+	"github.com/buarki/find-castles/toascii"
+)
+
+const (
+	earthRadiusInKm = 6371.0
+)
+
+var (
+	// hand-picked localities, the towns castles of the sources are in and
+	// the main ones of each subdivision, with their ISO 3166-2 subdivision
+	//go:embed places.json
+	placesData []byte
+
+	defaultGeocoder = mustParse(placesData)
+
+	ErrInvalidPlaces = errors.New("invalid places")
+)
+
+// Place is a locality, like a town or a village, along with the subdivision
+// it is in.
+type Place struct {
+	Name    string         `json:"name"`
+	Aliases []string       `json:"aliases"`
+	Country castle.Country `json:"country"`
+	// ISO 3166-2 code, like IE-MH
+	Subdivision string  `json:"subdivision"`
+	Latitude    float64 `json:"latitude"`
+	Longitude   float64 `json:"longitude"`
+}
+
+func (p Place) Coordinates() castle.Coordinates {
+	return castle.Coordinates{Latitude: p.Latitude, Longitude: p.Longitude}
+}
+
+// Match is the place nearest to some coordinates.
+type Match struct {
+	Place        Place
+	DistanceInKm float64
+}
+
+// Geocoder finds the places near coordinates, and the coordinates of
+// places, without reaching any service.
+type Geocoder struct {
+	places map[castle.Country][]Place
+	byName map[castle.Country]map[string][]Place
+}
+
+// Parse reads the places from JSON.
+func Parse(data []byte) (*Geocoder, error) {
+	var file struct {
+		Places []Place `json:"places"`
+	}
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse places, got %v", err)
+	}
+	return New(file.Places)
+}
+
+func mustParse(data []byte) *Geocoder {
+	geocoder, err := Parse(data)
+	if err != nil {
+		panic(err)
+	}
+	return geocoder
+}
+
+// New fails when a place is out of range or on a subdivision the gazetteer
+// of its country doesn't have, as states filled from it wouldn't resolve.
+func New(places []Place) (*Geocoder, error) {
+	geocoder := &Geocoder{
+		places: make(map[castle.Country][]Place),
+		byName: make(map[castle.Country]map[string][]Place),
+	}
+	for _, place := range places {
+		if place.Name == "" {
+			return nil, fmt.Errorf("%w: place of [%s] without name", ErrInvalidPlaces, place.Country)
+		}
+		if place.Latitude < -90 || place.Latitude > 90 || place.Longitude < -180 || place.Longitude > 180 {
+			return nil, fmt.Errorf("%w: [%s] of [%s] out of range", ErrInvalidPlaces, place.Name, place.Country)
+		}
+		subdivision, found := castle.ResolveSubdivision(place.Country, place.Subdivision)
+		if !found || subdivision.Code != place.Subdivision {
+			return nil, fmt.Errorf("%w: [%s] of [%s] on unknown subdivision [%s]", ErrInvalidPlaces, place.Name, place.Country, place.Subdivision)
+		}
+
+		geocoder.places[place.Country] = append(geocoder.places[place.Country], place)
+		if geocoder.byName[place.Country] == nil {
+			geocoder.byName[place.Country] = make(map[string][]Place)
+		}
+		for _, name := range append([]string{place.Name}, place.Aliases...) {
+			key := keyOf(name)
+			geocoder.byName[place.Country][key] = append(geocoder.byName[place.Country][key], place)
+		}
+	}
+	return geocoder, nil
+}
+
+// Default is the geocoder of the bundled places.
+func Default() *Geocoder {
+	return defaultGeocoder
+}
+
+// Reverse finds the place of the country nearest to the coordinates.
+func (g *Geocoder) Reverse(country castle.Country, c castle.Coordinates) (Match, bool) {
+	var nearest Match
+	found := false
+	for _, place := range g.places[country] {
+		distance := distanceInKm(c, place.Coordinates())
+		if !found || distance < nearest.DistanceInKm {
+			nearest = Match{Place: place, DistanceInKm: distance}
+			found = true
+		}
+	}
+	return nearest, found
+}
+
+// Forward finds the coordinates of the town. The region, a name or code of
+// a subdivision, tells apart towns named alike, like Newport on the Isle of
+// Wight and in Wales. Towns the region doesn't tell apart aren't guessed.
+func (g *Geocoder) Forward(country castle.Country, town, region string) (castle.Coordinates, bool) {
+	candidates := g.byName[country][keyOf(town)]
+	if subdivision, found := castle.ResolveSubdivision(country, region); found {
+		var inRegion []Place
+		for _, candidate := range candidates {
+			if candidate.Subdivision == subdivision.Code {
+				inRegion = append(inRegion, candidate)
+			}
+		}
+		candidates = inRegion
+	}
+	if len(candidates) != 1 {
+		return castle.Coordinates{}, false
+	}
+	return candidates[0].Coordinates(), true
+}
+
+// keyOf is the name without case, accents, punctuation and spaces, so
+// "Carrick-on-Shannon" and "carrick on shannon" are the same.
+func keyOf(name string) string {
+	ascii, err := toascii.From(strings.ToLower(name))
+	if err != nil {
+		ascii = strings.ToLower(name)
+	}
+	return strings.Join(strings.FieldsFunc(ascii, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9')
+	}), "")
+}
+
+// distanceInKm is the great-circle distance by the haversine formula.
+func distanceInKm(a, b castle.Coordinates) float64 {
+	latA, latB := a.Latitude*math.Pi/180, b.Latitude*math.Pi/180
+	deltaLat := latB - latA
+	deltaLon := (b.Longitude - a.Longitude) * math.Pi / 180
+	h := math.Sin(deltaLat/2)*math.Sin(deltaLat/2) + math.Cos(latA)*math.Cos(latB)*math.Sin(deltaLon/2)*math.Sin(deltaLon/2)
+	return 2 * earthRadiusInKm * math.Asin(math.Sqrt(h))
+}
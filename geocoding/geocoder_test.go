@@ -0,0 +1,191 @@
+package geocoding
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/buarki/find-castles/castle"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestReverse(t *testing.T) {
+	testCases := []struct {
+		name          string
+		country       castle.Country
+		coordinates   castle.Coordinates
+		expectedPlace string
+		expectedCode  string
+	}{
+		{name: "trim_castle", country: castle.Ireland, coordinates: castle.Coordinates{Latitude: 53.5545, Longitude: -6.7896}, expectedPlace: "Trim", expectedCode: "IE-MH"},
+		{name: "windsor_castle", country: castle.UK, coordinates: castle.Coordinates{Latitude: 51.4839, Longitude: -0.6070}, expectedPlace: "Windsor", expectedCode: "GB-WNM"},
+		{name: "bojnice_castle", country: castle.Slovakia, coordinates: castle.Coordinates{Latitude: 48.7800, Longitude: 18.5780}, expectedPlace: "Bojnice", expectedCode: "SK-TC"},
+		{name: "country_without_places", country: castle.Country("fr"), coordinates: castle.Coordinates{Latitude: 48.8, Longitude: 2.3}},
+	}
+
+	for _, tt := range testCases {
+		currentTT := tt
+		t.Run(currentTT.name, func(t *testing.T) {
+			t.Helper()
+			match, found := Default().Reverse(currentTT.country, currentTT.coordinates)
+
+			if found != (currentTT.expectedPlace != "") {
+				t.Errorf("expected to be found [%t], got [%t]", currentTT.expectedPlace != "", found)
+			}
+			if match.Place.Name != currentTT.expectedPlace {
+				t.Errorf("expected place [%s], got [%s]", currentTT.expectedPlace, match.Place.Name)
+			}
+			if match.Place.Subdivision != currentTT.expectedCode {
+				t.Errorf("expected subdivision [%s], got [%s]", currentTT.expectedCode, match.Place.Subdivision)
+			}
+		})
+	}
+}
+
+func TestForward(t *testing.T) {
+	testCases := []struct {
+		name          string
+		country       castle.Country
+		town          string
+		region        string
+		expected      castle.Coordinates
+		expectedFound bool
+	}{
+		{name: "town", country: castle.Ireland, town: "Trim", expected: castle.Coordinates{Latitude: 53.5550, Longitude: -6.7915}, expectedFound: true},
+		{name: "town_without_accents", country: castle.Portugal, town: "guimaraes", expected: castle.Coordinates{Latitude: 41.4425, Longitude: -8.2918}, expectedFound: true},
+		{name: "town_by_alias", country: castle.Denmark, town: "Elsinore", expected: castle.Coordinates{Latitude: 56.0361, Longitude: 12.6136}, expectedFound: true},
+		{name: "town_told_apart_by_region", country: castle.UK, town: "Newport", region: "Isle of Wight", expected: castle.Coordinates{Latitude: 50.7010, Longitude: -1.2883}, expectedFound: true},
+		{name: "town_told_apart_by_code", country: castle.UK, town: "Bangor", region: "GB-GWN", expected: castle.Coordinates{Latitude: 53.2270, Longitude: -4.1290}, expectedFound: true},
+		{name: "town_not_told_apart", country: castle.UK, town: "Bangor"},
+		{name: "town_on_other_region", country: castle.Ireland, town: "Trim", region: "Kerry"},
+		{name: "unknown_town", country: castle.Ireland, town: "Atlantis"},
+	}
+
+	for _, tt := range testCases {
+		currentTT := tt
+		t.Run(currentTT.name, func(t *testing.T) {
+			t.Helper()
+			coordinates, found := Default().Forward(currentTT.country, currentTT.town, currentTT.region)
+
+			if found != currentTT.expectedFound {
+				t.Errorf("expected to be found [%t], got [%t]", currentTT.expectedFound, found)
+			}
+			if coordinates != currentTT.expected {
+				t.Errorf("expected coordinates [%v], got [%v]", currentTT.expected, coordinates)
+			}
+		})
+	}
+}
+
+func TestFill(t *testing.T) {
+	testCases := []struct {
+		name           string
+		castle         castle.Model
+		expected       castle.Model
+		expectedFilled bool
+	}{
+		{
+			name:   "state_and_city_from_coordinates",
+			castle: castle.Model{Name: "trim", Country: castle.Ireland, Coordinates: "53.5545,-6.7896"},
+			expected: castle.Model{
+				Name: "trim", Country: castle.Ireland, Coordinates: "53.5545,-6.7896",
+				State: "meath", StateCode: "IE-MH", City: "trim",
+				Derived: []string{castle.DerivedCity, castle.DerivedState},
+			},
+			expectedFilled: true,
+		},
+		{
+			name:   "only_state_of_castles_far_from_places",
+			castle: castle.Model{Name: "dunsany", Country: castle.Ireland, Coordinates: "53.45,-6.95"},
+			expected: castle.Model{
+				Name: "dunsany", Country: castle.Ireland, Coordinates: "53.45,-6.95",
+				State: "meath", StateCode: "IE-MH",
+				Derived: []string{castle.DerivedState},
+			},
+			expectedFilled: true,
+		},
+		{
+			name:     "nothing_of_castles_too_far_from_places",
+			castle:   castle.Model{Name: "far", Country: castle.Ireland, Coordinates: "53.9,-10.2"},
+			expected: castle.Model{Name: "far", Country: castle.Ireland, Coordinates: "53.9,-10.2"},
+		},
+		{
+			name:     "told_state_and_city_are_kept",
+			castle:   castle.Model{Name: "trim", Country: castle.Ireland, Coordinates: "53.5545,-6.7896", State: "westmeath", City: "athlone"},
+			expected: castle.Model{Name: "trim", Country: castle.Ireland, Coordinates: "53.5545,-6.7896", State: "westmeath", City: "athlone"},
+		},
+		{
+			name:   "coordinates_from_town_and_region",
+			castle: castle.Model{Name: "carisbrooke", Country: castle.UK, City: "newport", State: "isle of wight"},
+			expected: castle.Model{
+				Name: "carisbrooke", Country: castle.UK, City: "newport", State: "isle of wight",
+				Coordinates: "50.701000,-1.288300",
+				Derived:     []string{castle.DerivedCoordinates},
+			},
+			expectedFilled: true,
+		},
+		{
+			name:   "coordinates_from_address",
+			castle: castle.Model{Name: "ross", Country: castle.Ireland, Address: &castle.Address{Town: "Killarney", Region: "Co. Kerry"}},
+			expected: castle.Model{
+				Name: "ross", Country: castle.Ireland, Address: &castle.Address{Town: "Killarney", Region: "Co. Kerry"},
+				Coordinates: "52.059900,-9.504400",
+				Derived:     []string{castle.DerivedCoordinates},
+			},
+			expectedFilled: true,
+		},
+		{
+			name:     "derived_coordinates_fill_nothing_else",
+			castle:   castle.Model{Name: "x", Country: castle.Ireland, Coordinates: "53.555000,-6.791500", Derived: []string{castle.DerivedCoordinates}},
+			expected: castle.Model{Name: "x", Country: castle.Ireland, Coordinates: "53.555000,-6.791500", Derived: []string{castle.DerivedCoordinates}},
+		},
+		{
+			name:     "invalid_coordinates_are_left_alone",
+			castle:   castle.Model{Name: "x", Country: castle.Ireland, Coordinates: "somewhere", City: "trim"},
+			expected: castle.Model{Name: "x", Country: castle.Ireland, Coordinates: "somewhere", City: "trim"},
+		},
+	}
+
+	for _, tt := range testCases {
+		currentTT := tt
+		t.Run(currentTT.name, func(t *testing.T) {
+			t.Helper()
+			filled, ok := Fill(currentTT.castle)
+
+			if ok != currentTT.expectedFilled {
+				t.Errorf("expected to be filled [%t], got [%t]", currentTT.expectedFilled, ok)
+			}
+			if diff := cmp.Diff(currentTT.expected, filled); diff != "" {
+				t.Errorf("diff: %v", diff)
+			}
+		})
+	}
+}
+
+func TestNewRejectsPlacesOnUnknownSubdivisions(t *testing.T) {
+	_, err := New([]Place{{Name: "Trim", Country: castle.Ireland, Subdivision: "IE-XX", Latitude: 53.55, Longitude: -6.79}})
+
+	if !errors.Is(err, ErrInvalidPlaces) {
+		t.Errorf("expected err [%v], got [%v]", ErrInvalidPlaces, err)
+	}
+}
+
+func TestDistanceInKm(t *testing.T) {
+	dublin := castle.Coordinates{Latitude: 53.3498, Longitude: -6.2603}
+	london := castle.Coordinates{Latitude: 51.5074, Longitude: -0.1278}
+
+	// about 464 km as the crow flies
+	if distance := distanceInKm(dublin, london); math.Abs(distance-464) > 5 {
+		t.Errorf("expected distance of about [464], got [%f]", distance)
+	}
+}
+
+func TestPlacesAreInTheirCountries(t *testing.T) {
+	for _, places := range Default().places {
+		for _, place := range places {
+			if _, check := castle.CheckCoordinates(place.Country, place.Coordinates()); check != castle.CoordinatesValid {
+				t.Errorf("expected [%s] of [%s] to be in its country, got [%s]", place.Name, place.Country, check)
+			}
+		}
+	}
+}
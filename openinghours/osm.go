@@ -0,0 +1,99 @@
+package openinghours
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+var (
+	osmWeekdays = map[time.Weekday]string{
+		time.Monday:    "Mo",
+		time.Tuesday:   "Tu",
+		time.Wednesday: "We",
+		time.Thursday:  "Th",
+		time.Friday:    "Fr",
+		time.Saturday:  "Sa",
+		time.Sunday:    "Su",
+	}
+
+	// OpenStreetMap weeks start on Monday
+	weekOrder = []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday, time.Saturday, time.Sunday}
+)
+
+// String writes the schedule on the OpenStreetMap opening_hours syntax, ex:
+// "Apr 01-Sep 30 10:00-16:00; Oct 01-Mar 31 10:00-15:00". Last admission
+// times are written as comments.
+func (s Schedule) String() string {
+	rules := make([]string, 0, len(s.Rules))
+	for _, r := range s.Rules {
+		rules = append(rules, r.String())
+	}
+	return strings.Join(rules, "; ")
+}
+
+func (r Rule) String() string {
+	var parts []string
+	if r.Dates != nil {
+		parts = append(parts, r.Dates.String())
+	}
+	if len(r.Weekdays) > 0 {
+		parts = append(parts, weekdaysString(r.Weekdays))
+	}
+	if r.Closed {
+		parts = append(parts, "off")
+	} else if len(r.Times) > 0 {
+		times := make([]string, 0, len(r.Times))
+		for _, t := range r.Times {
+			times = append(times, fmt.Sprintf("%s-%s", t.From, t.To))
+		}
+		parts = append(parts, strings.Join(times, ","))
+	}
+	if len(parts) == 0 {
+		parts = append(parts, "24/7")
+	}
+	if r.LastAdmission != nil {
+		parts = append(parts, fmt.Sprintf(`"last admission %s"`, r.LastAdmission))
+	}
+	return strings.Join(parts, " ")
+}
+
+func (r DateRange) String() string {
+	to := dateString(r.To, r.To.Year != r.From.Year)
+	return fmt.Sprintf("%s-%s", dateString(r.From, true), to)
+}
+
+func dateString(d Date, withYear bool) string {
+	date := fmt.Sprintf("%s %02d", d.Month.String()[:3], d.Day)
+	if withYear && d.Year != 0 {
+		return fmt.Sprintf("%d %s", d.Year, date)
+	}
+	return date
+}
+
+// weekdaysString writes the weekdays as ranges when consecutive, ex: "Tu-Su".
+func weekdaysString(weekdays []time.Weekday) string {
+	selected := make(map[time.Weekday]bool, len(weekdays))
+	for _, w := range weekdays {
+		selected[w] = true
+	}
+	var ranges []string
+	for i := 0; i < len(weekOrder); i++ {
+		if !selected[weekOrder[i]] {
+			continue
+		}
+		start := i
+		for i+1 < len(weekOrder) && selected[weekOrder[i+1]] {
+			i++
+		}
+		switch {
+		case i == start:
+			ranges = append(ranges, osmWeekdays[weekOrder[i]])
+		case i == start+1:
+			ranges = append(ranges, osmWeekdays[weekOrder[start]], osmWeekdays[weekOrder[i]])
+		default:
+			ranges = append(ranges, osmWeekdays[weekOrder[start]]+"-"+osmWeekdays[weekOrder[i]])
+		}
+	}
+	return strings.Join(ranges, ",")
+}
@@ -0,0 +1,191 @@
+package openinghours
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	clockPattern     = `\d{1,2}(?:[:.]\d{2})?\s*(?:am|pm)?`
+	dayMonthPattern  = `(\d{1,2})\s+([a-z]+)(?:\s+(\d{4}))?`
+	rangeSeparator   = `\s*(?:-|to)\s*`
+	dateRangeMatcher = dayMonthPattern + rangeSeparator + dayMonthPattern
+	timeRangeMatcher = `(` + clockPattern + `)` + rangeSeparator + `(` + clockPattern + `)`
+)
+
+var (
+	clockRegex     = regexp.MustCompile(`^(\d{1,2})(?:[:.](\d{2}))?\s*(am|pm)?$`)
+	timeRangeRegex = regexp.MustCompile(`^(?:daily\s+)?` + timeRangeMatcher + `$`)
+	dateRangeRegex = regexp.MustCompile(`^` + dateRangeMatcher + `$`)
+
+	// dashes and spaces sources use that mean the same as the plain ones
+	textNormalizer = strings.NewReplacer("–", "-", "—", "-", " ", " ")
+
+	months = map[string]time.Month{
+		"january": time.January, "jan": time.January,
+		"february": time.February, "feb": time.February,
+		"march": time.March, "mar": time.March,
+		"april": time.April, "apr": time.April,
+		"may":  time.May,
+		"june": time.June, "jun": time.June,
+		"july": time.July, "jul": time.July,
+		"august": time.August, "aug": time.August,
+		"september": time.September, "sep": time.September, "sept": time.September,
+		"october": time.October, "oct": time.October,
+		"november": time.November, "nov": time.November,
+		"december": time.December, "dec": time.December,
+	}
+
+	weekdays = map[string]time.Weekday{
+		"monday": time.Monday, "mon": time.Monday,
+		"tuesday": time.Tuesday, "tue": time.Tuesday, "tues": time.Tuesday,
+		"wednesday": time.Wednesday, "wed": time.Wednesday,
+		"thursday": time.Thursday, "thu": time.Thursday, "thurs": time.Thursday,
+		"friday": time.Friday, "fri": time.Friday,
+		"saturday": time.Saturday, "sat": time.Saturday,
+		"sunday": time.Sunday, "sun": time.Sunday,
+	}
+)
+
+// normalizeText lower cases the text and turns the dashes used on ranges
+// into plain ones.
+func normalizeText(text string) string {
+	return strings.TrimSpace(strings.ToLower(textNormalizer.Replace(text)))
+}
+
+// ParseClock reads times like "17:15", "9.30", "10am" and "4:30 pm".
+func ParseClock(text string) (Clock, error) {
+	matches := clockRegex.FindStringSubmatch(normalizeText(text))
+	if matches == nil {
+		return 0, fmt.Errorf("%w: time [%s]", ErrUnrecognizedOpeningHours, text)
+	}
+	hour, _ := strconv.Atoi(matches[1])
+	minute := 0
+	if matches[2] != "" {
+		minute, _ = strconv.Atoi(matches[2])
+	}
+	if matches[3] != "" {
+		if hour < 1 || hour > 12 {
+			return 0, fmt.Errorf("%w: time [%s]", ErrUnrecognizedOpeningHours, text)
+		}
+		// 12am is midnight and 12pm is noon
+		hour %= 12
+		if matches[3] == "pm" {
+			hour += 12
+		}
+	}
+	return NewClock(hour, minute)
+}
+
+// ParseTimeRange reads ranges like "10:00 - 16:00", "10am-4pm" and "Daily 10:00 – 18:00".
+func ParseTimeRange(text string) (TimeRange, error) {
+	matches := timeRangeRegex.FindStringSubmatch(normalizeText(text))
+	if matches == nil {
+		return TimeRange{}, fmt.Errorf("%w: time range [%s]", ErrUnrecognizedOpeningHours, text)
+	}
+	return timeRangeOf(matches[1], matches[2], text)
+}
+
+func timeRangeOf(rawFrom, rawTo, text string) (TimeRange, error) {
+	from, err := ParseClock(rawFrom)
+	if err != nil {
+		return TimeRange{}, err
+	}
+	to, err := ParseClock(rawTo)
+	if err != nil {
+		return TimeRange{}, err
+	}
+	if to <= from {
+		return TimeRange{}, fmt.Errorf("%w: time range [%s] ends before it starts", ErrUnrecognizedOpeningHours, text)
+	}
+	return TimeRange{From: from, To: to}, nil
+}
+
+// ParseDateRange reads ranges like "15 March - 26 October" and
+// "01 June - 29 September 2024". The year of one date is also the year of the
+// other, and defaultYear is used when neither has one, zero meaning every year.
+func ParseDateRange(text string, defaultYear int) (DateRange, error) {
+	matches := dateRangeRegex.FindStringSubmatch(normalizeText(text))
+	if matches == nil {
+		return DateRange{}, fmt.Errorf("%w: date range [%s]", ErrUnrecognizedOpeningHours, text)
+	}
+	return dateRangeOf(matches[1:7], defaultYear, text)
+}
+
+// dateRangeOf builds the range out of the day, month and year of each date.
+func dateRangeOf(parts []string, defaultYear int, text string) (DateRange, error) {
+	from, err := dateOf(parts[0], parts[1], parts[2])
+	if err != nil {
+		return DateRange{}, err
+	}
+	to, err := dateOf(parts[3], parts[4], parts[5])
+	if err != nil {
+		return DateRange{}, err
+	}
+
+	reversed := dateKey(0, to.Month, to.Day) < dateKey(0, from.Month, from.Day)
+	switch {
+	case from.Year == 0 && to.Year != 0:
+		from.Year = to.Year
+		if reversed {
+			from.Year--
+		}
+	case from.Year != 0 && to.Year == 0:
+		to.Year = from.Year
+		if reversed {
+			to.Year++
+		}
+	case from.Year == 0 && to.Year == 0 && defaultYear != 0:
+		from.Year, to.Year = defaultYear, defaultYear
+		if reversed {
+			to.Year++
+		}
+	}
+	if from.Year != 0 && dateKey(to.Year, to.Month, to.Day) < dateKey(from.Year, from.Month, from.Day) {
+		return DateRange{}, fmt.Errorf("%w: date range [%s] ends before it starts", ErrUnrecognizedOpeningHours, text)
+	}
+	return DateRange{From: from, To: to}, nil
+}
+
+func dateOf(rawDay, rawMonth, rawYear string) (Date, error) {
+	month, found := months[rawMonth]
+	if !found {
+		return Date{}, fmt.Errorf("%w: month [%s]", ErrUnrecognizedOpeningHours, rawMonth)
+	}
+	day, _ := strconv.Atoi(rawDay)
+	// any leap year, so February 29 is valid for ranges of every year
+	if day < 1 || day > time.Date(2024, month+1, 0, 0, 0, 0, 0, time.UTC).Day() {
+		return Date{}, fmt.Errorf("%w: day [%s] of %s", ErrUnrecognizedOpeningHours, rawDay, month)
+	}
+	year := 0
+	if rawYear != "" {
+		year, _ = strconv.Atoi(rawYear)
+	}
+	return Date{Year: year, Month: month, Day: day}, nil
+}
+
+// ParseWeekdays reads a weekday, like "Monday" or "Sat", or a range of them, like "Tuesday - Sunday".
+func ParseWeekdays(text string) ([]time.Weekday, error) {
+	normalized := normalizeText(text)
+	bounds := strings.SplitN(normalized, "-", 2)
+	from, found := weekdays[strings.TrimSpace(bounds[0])]
+	if !found {
+		return nil, fmt.Errorf("%w: weekday [%s]", ErrUnrecognizedOpeningHours, text)
+	}
+	if len(bounds) == 1 {
+		return []time.Weekday{from}, nil
+	}
+	to, found := weekdays[strings.TrimSpace(bounds[1])]
+	if !found {
+		return nil, fmt.Errorf("%w: weekday [%s]", ErrUnrecognizedOpeningHours, text)
+	}
+	selected := []time.Weekday{from}
+	for w := from; w != to; {
+		w = (w + 1) % 7
+		selected = append(selected, w)
+	}
+	return selected, nil
+}
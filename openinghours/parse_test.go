@@ -0,0 +1,118 @@
+package openinghours
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseClock(t *testing.T) {
+	testCases := []struct {
+		text     string
+		expected string
+		err      error
+	}{
+		{text: "17:15", expected: "17:15"},
+		{text: "9.30", expected: "09:30"},
+		{text: "10am", expected: "10:00"},
+		{text: "4:30 pm", expected: "16:30"},
+		{text: "12pm", expected: "12:00"},
+		{text: "12am", expected: "00:00"},
+		{text: "24:00", expected: "24:00"},
+		{text: "13pm", err: ErrUnrecognizedOpeningHours},
+		{text: "25:00", err: ErrUnrecognizedOpeningHours},
+		{text: "noon", err: ErrUnrecognizedOpeningHours},
+	}
+
+	for _, tt := range testCases {
+		currentTT := tt
+		t.Run(currentTT.text, func(t *testing.T) {
+			t.Helper()
+			received, err := ParseClock(currentTT.text)
+			if !errors.Is(err, currentTT.err) {
+				t.Errorf("expected err [%v], got [%v]", currentTT.err, err)
+			}
+			if err == nil && received.String() != currentTT.expected {
+				t.Errorf("expected [%s], got [%s]", currentTT.expected, received)
+			}
+		})
+	}
+}
+
+func TestParseMedievalBritain(t *testing.T) {
+	testCases := []struct {
+		text     string
+		expected string
+		err      error
+	}{
+		{text: "Summer: 10:00 - 16:00,Winter: 10:00 - 15:00", expected: "Apr 01-Sep 30 10:00-16:00; Oct 01-Mar 31 10:00-15:00"},
+		{text: "Summer: 10am-4pm, Winter: 10am-5pm", expected: "Apr 01-Sep 30 10:00-16:00; Oct 01-Mar 31 10:00-17:00"},
+		{
+			text:     "Tuesday: 11am-4pm, Wednesday: 11am-4pm, Thursday: 11am-4pm, Friday: 11am-4pm, Saturday: 11am-4pm, Sunday: 11am-4pm, Monday: Closed",
+			expected: "Tu 11:00-16:00; We 11:00-16:00; Th 11:00-16:00; Fr 11:00-16:00; Sa 11:00-16:00; Su 11:00-16:00; Mo off",
+		},
+		{text: "Monday - Friday: 9:30am – 5pm", expected: "Mo-Fr 09:30-17:00"},
+		{text: "Open 24 hours, year-round.", expected: "24/7"},
+		{text: "Castle Sween is open year-round.", expected: "24/7"},
+		{text: "Check the website before visiting", err: ErrUnrecognizedOpeningHours},
+		{text: "Summer: ask the keeper", err: ErrUnrecognizedOpeningHours},
+		{text: "", err: ErrUnrecognizedOpeningHours},
+	}
+
+	for _, tt := range testCases {
+		currentTT := tt
+		t.Run(currentTT.text, func(t *testing.T) {
+			t.Helper()
+			received, err := ParseMedievalBritain(currentTT.text)
+			if !errors.Is(err, currentTT.err) {
+				t.Errorf("expected err [%v], got [%v]", currentTT.err, err)
+			}
+			if err == nil && received.String() != currentTT.expected {
+				t.Errorf("expected [%s], got [%s]", currentTT.expected, received)
+			}
+		})
+	}
+}
+
+func TestParseHeritageIreland(t *testing.T) {
+	testCases := []struct {
+		text     string
+		expected string
+		err      error
+	}{
+		{text: "01 June - 29 September 2024 - 09:30- 16:00", expected: "2024 Jun 01-Sep 29 09:30-16:00"},
+		{text: "15 March- 3 November 2024", expected: "2024 Mar 15-Nov 03"},
+		{text: "1 November 2024 – 28 February 2025 - 10:00 - 16:00", expected: "2024 Nov 01-2025 Feb 28 10:00-16:00"},
+		{text: "20 December - 5 January 2025", expected: "2024 Dec 20-2025 Jan 05"},
+		{text: "31 February - 3 March 2024", err: ErrUnrecognizedOpeningHours},
+		{text: "Closed for conservation works", err: ErrUnrecognizedOpeningHours},
+	}
+
+	for _, tt := range testCases {
+		currentTT := tt
+		t.Run(currentTT.text, func(t *testing.T) {
+			t.Helper()
+			received, err := ParseHeritageIreland(currentTT.text)
+			if !errors.Is(err, currentTT.err) {
+				t.Errorf("expected err [%v], got [%v]", currentTT.err, err)
+			}
+			if err == nil && received.String() != currentTT.expected {
+				t.Errorf("expected [%s], got [%s]", currentTT.expected, received)
+			}
+		})
+	}
+}
+
+func TestParseHeritageIrelandSeason(t *testing.T) {
+	received, err := ParseHeritageIrelandSeason("27 October - 03 November ", []string{"Daily 10:00 – 17:00", "Last admission: 16:15"}, 2024)
+	if err != nil {
+		t.Fatalf("expected to have err nil, got %v", err)
+	}
+	expected := `2024 Oct 27-Nov 03 10:00-17:00 "last admission 16:15"`
+	if received.String() != expected {
+		t.Errorf("expected [%s], got [%s]", expected, received)
+	}
+
+	if _, err := ParseHeritageIrelandSeason("15 March - 26 October", []string{"By appointment"}, 0); !errors.Is(err, ErrUnrecognizedOpeningHours) {
+		t.Errorf("expected err [%v], got [%v]", ErrUnrecognizedOpeningHours, err)
+	}
+}
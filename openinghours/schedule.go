@@ -0,0 +1,121 @@
+// Package openinghours models when castles can be visited, telling whether
+// they are open at a given time and writing it on the OpenStreetMap
+// opening_hours syntax.
+package openinghours
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+)
+
+// minutesOfDay is the end of the day, 24:00.
+const minutesOfDay = 24 * 60
+
+var (
+	ErrUnrecognizedOpeningHours = errors.New("unrecognized opening hours")
+)
+
+// Clock is a time of the day in minutes since midnight, up to 24:00.
+type Clock int
+
+func NewClock(hour, minute int) (Clock, error) {
+	c := Clock(hour*60 + minute)
+	if hour < 0 || minute < 0 || minute > 59 || c > minutesOfDay {
+		return 0, fmt.Errorf("%w: invalid time %02d:%02d", ErrUnrecognizedOpeningHours, hour, minute)
+	}
+	return c, nil
+}
+
+func (c Clock) String() string {
+	return fmt.Sprintf("%02d:%02d", int(c)/60, int(c)%60)
+}
+
+// TimeRange starts at From and ends right before To.
+type TimeRange struct {
+	From Clock `json:"from" bson:"from"`
+	To   Clock `json:"to" bson:"to"`
+}
+
+func (r TimeRange) contains(c Clock) bool {
+	return c >= r.From && c < r.To
+}
+
+// Date is a day of the year, of every year when Year is zero.
+type Date struct {
+	Year  int        `json:"year,omitempty" bson:"year,omitempty"`
+	Month time.Month `json:"month" bson:"month"`
+	Day   int        `json:"day" bson:"day"`
+}
+
+// DateRange includes both of its dates. Ranges of every year may go over the
+// new year, like from October to March.
+type DateRange struct {
+	From Date `json:"from" bson:"from"`
+	To   Date `json:"to" bson:"to"`
+}
+
+func (r DateRange) contains(year int, month time.Month, day int) bool {
+	if r.From.Year != 0 && r.To.Year != 0 {
+		date := dateKey(year, month, day)
+		return date >= dateKey(r.From.Year, r.From.Month, r.From.Day) && date <= dateKey(r.To.Year, r.To.Month, r.To.Day)
+	}
+	date := dateKey(0, month, day)
+	from, to := dateKey(0, r.From.Month, r.From.Day), dateKey(0, r.To.Month, r.To.Day)
+	if from <= to {
+		return date >= from && date <= to
+	}
+	return date >= from || date <= to
+}
+
+func dateKey(year int, month time.Month, day int) int {
+	return year*10000 + int(month)*100 + day
+}
+
+// Rule tells whether a castle is open on the dates and weekdays it selects,
+// all of them when not given. Rules without times are open the whole day.
+type Rule struct {
+	Dates    *DateRange     `json:"dates,omitempty" bson:"dates,omitempty"`
+	Weekdays []time.Weekday `json:"weekdays,omitempty" bson:"weekdays,omitempty"`
+	Times    []TimeRange    `json:"times,omitempty" bson:"times,omitempty"`
+	Closed   bool           `json:"closed,omitempty" bson:"closed,omitempty"`
+	// last time visitors are let in, before closing
+	LastAdmission *Clock `json:"lastAdmission,omitempty" bson:"lastAdmission,omitempty"`
+}
+
+func (r Rule) selects(t time.Time) bool {
+	if r.Dates != nil && !r.Dates.contains(t.Year(), t.Month(), t.Day()) {
+		return false
+	}
+	return len(r.Weekdays) == 0 || slices.Contains(r.Weekdays, t.Weekday())
+}
+
+// Schedule holds the rules of when a castle opens. As on OpenStreetMap, a
+// rule overrides the previous ones on the days it selects, so closures and
+// seasons come after the rules they change.
+type Schedule struct {
+	Rules []Rule `json:"rules" bson:"rules"`
+}
+
+// IsOpenAt tells whether the castle is open at the given time on the given
+// location, the one of the time when nil. Days no rule selects are closed.
+func (s Schedule) IsOpenAt(t time.Time, location *time.Location) bool {
+	if location != nil {
+		t = t.In(location)
+	}
+	var selected *Rule
+	for i := range s.Rules {
+		if s.Rules[i].selects(t) {
+			selected = &s.Rules[i]
+		}
+	}
+	if selected == nil || selected.Closed {
+		return false
+	}
+	if len(selected.Times) == 0 {
+		return true
+	}
+	now := Clock(t.Hour()*60 + t.Minute())
+	return slices.ContainsFunc(selected.Times, func(r TimeRange) bool { return r.contains(now) })
+}
@@ -0,0 +1,141 @@
+package openinghours
+
+import (
+	"testing"
+	"time"
+)
+
+func TestIsOpenAt(t *testing.T) {
+	dublin, err := time.LoadLocation("Europe/Dublin")
+	if err != nil {
+		t.Fatalf("expected to have err nil, got %v", err)
+	}
+	lastAdmission := Clock(17*60 + 15)
+	schedule := Schedule{Rules: []Rule{
+		{
+			Dates: &DateRange{From: Date{Month: time.March, Day: 15}, To: Date{Month: time.October, Day: 26}},
+			Times: []TimeRange{{From: 10 * 60, To: 18 * 60}},
+			// still open after the last admission
+			LastAdmission: &lastAdmission,
+		},
+		{
+			Dates: &DateRange{From: Date{Month: time.October, Day: 27}, To: Date{Month: time.March, Day: 14}},
+			Times: []TimeRange{{From: 10 * 60, To: 16 * 60}},
+		},
+		{Weekdays: []time.Weekday{time.Monday}, Closed: true},
+		{Dates: &DateRange{From: Date{Month: time.December, Day: 24}, To: Date{Month: time.December, Day: 26}}, Closed: true},
+	}}
+
+	testCases := []struct {
+		name     string
+		at       time.Time
+		expected bool
+	}{
+		{name: "saturday_in_march", at: time.Date(2024, time.March, 16, 11, 0, 0, 0, dublin), expected: true},
+		{name: "after_last_admission", at: time.Date(2024, time.March, 16, 17, 30, 0, 0, dublin), expected: true},
+		{name: "at_closing_time", at: time.Date(2024, time.March, 16, 18, 0, 0, 0, dublin), expected: false},
+		{name: "before_opening", at: time.Date(2024, time.March, 16, 9, 59, 0, 0, dublin), expected: false},
+		{name: "winter_season", at: time.Date(2024, time.January, 6, 15, 0, 0, 0, dublin), expected: true},
+		{name: "after_winter_closing", at: time.Date(2024, time.January, 6, 17, 0, 0, 0, dublin), expected: false},
+		{name: "closed_on_mondays", at: time.Date(2024, time.March, 18, 11, 0, 0, 0, dublin), expected: false},
+		{name: "christmas_closure", at: time.Date(2024, time.December, 25, 11, 0, 0, 0, dublin), expected: false},
+		// 10:30 in Dublin, open, while 09:30 UTC would be closed if the location was ignored
+		{name: "on_castle_location", at: time.Date(2024, time.July, 6, 9, 30, 0, 0, time.UTC), expected: true},
+	}
+
+	for _, tt := range testCases {
+		currentTT := tt
+		t.Run(currentTT.name, func(t *testing.T) {
+			t.Helper()
+			if received := schedule.IsOpenAt(currentTT.at, dublin); received != currentTT.expected {
+				t.Errorf("expected [%v], got [%v]", currentTT.expected, received)
+			}
+		})
+	}
+}
+
+func TestIsOpenAtWithYear(t *testing.T) {
+	schedule := Schedule{Rules: []Rule{
+		{Dates: &DateRange{From: Date{Year: 2024, Month: time.June, Day: 1}, To: Date{Year: 2024, Month: time.September, Day: 29}}},
+	}}
+
+	if !schedule.IsOpenAt(time.Date(2024, time.July, 1, 3, 0, 0, 0, time.UTC), nil) {
+		t.Errorf("expected to be open the whole day within the dates")
+	}
+	if schedule.IsOpenAt(time.Date(2025, time.July, 1, 12, 0, 0, 0, time.UTC), nil) {
+		t.Errorf("expected to be closed on other years")
+	}
+}
+
+func TestScheduleString(t *testing.T) {
+	lastAdmission := Clock(16*60 + 15)
+	testCases := []struct {
+		name     string
+		schedule Schedule
+		expected string
+	}{
+		{
+			name:     "always_open",
+			schedule: Schedule{Rules: []Rule{{}}},
+			expected: "24/7",
+		},
+		{
+			name: "seasons",
+			schedule: Schedule{Rules: []Rule{
+				{Dates: &summer, Times: []TimeRange{{From: 10 * 60, To: 16 * 60}}},
+				{Dates: &winter, Times: []TimeRange{{From: 10 * 60, To: 15 * 60}}},
+			}},
+			expected: "Apr 01-Sep 30 10:00-16:00; Oct 01-Mar 31 10:00-15:00",
+		},
+		{
+			name: "weekdays",
+			schedule: Schedule{Rules: []Rule{
+				{Weekdays: []time.Weekday{time.Tuesday, time.Wednesday, time.Thursday, time.Friday, time.Saturday, time.Sunday}, Times: []TimeRange{{From: 11 * 60, To: 16 * 60}}},
+				{Weekdays: []time.Weekday{time.Monday}, Closed: true},
+			}},
+			expected: "Tu-Su 11:00-16:00; Mo off",
+		},
+		{
+			name: "scattered_weekdays",
+			schedule: Schedule{Rules: []Rule{
+				{Weekdays: []time.Weekday{time.Sunday, time.Monday, time.Wednesday, time.Saturday}, Times: []TimeRange{{From: 9 * 60, To: 12 * 60}, {From: 13 * 60, To: 17 * 60}}},
+			}},
+			expected: "Mo,We,Sa,Su 09:00-12:00,13:00-17:00",
+		},
+		{
+			name: "with_year_and_last_admission",
+			schedule: Schedule{Rules: []Rule{
+				{
+					Dates:         &DateRange{From: Date{Year: 2024, Month: time.October, Day: 27}, To: Date{Year: 2024, Month: time.November, Day: 3}},
+					Times:         []TimeRange{{From: 10 * 60, To: 17 * 60}},
+					LastAdmission: &lastAdmission,
+				},
+			}},
+			expected: `2024 Oct 27-Nov 03 10:00-17:00 "last admission 16:15"`,
+		},
+		{
+			name: "only_last_admission",
+			schedule: Schedule{Rules: []Rule{
+				{LastAdmission: &lastAdmission},
+			}},
+			expected: `24/7 "last admission 16:15"`,
+		},
+		{
+			name: "over_new_year",
+			schedule: Schedule{Rules: []Rule{
+				{Dates: &DateRange{From: Date{Year: 2024, Month: time.December, Day: 20}, To: Date{Year: 2025, Month: time.January, Day: 5}}, Closed: true},
+			}},
+			expected: "2024 Dec 20-2025 Jan 05 off",
+		},
+	}
+
+	for _, tt := range testCases {
+		currentTT := tt
+		t.Run(currentTT.name, func(t *testing.T) {
+			t.Helper()
+			if received := currentTT.schedule.String(); received != currentTT.expected {
+				t.Errorf("expected [%s], got [%s]", currentTT.expected, received)
+			}
+		})
+	}
+}
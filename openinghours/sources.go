@@ -0,0 +1,135 @@
+package openinghours
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+var (
+	// the seasons of Historic Environment Scotland, which most castles of
+	// medievalbritain.com follow
+	summer = DateRange{From: Date{Month: time.April, Day: 1}, To: Date{Month: time.September, Day: 30}}
+	winter = DateRange{From: Date{Month: time.October, Day: 1}, To: Date{Month: time.March, Day: 31}}
+
+	alwaysOpenRegex      = regexp.MustCompile(`\b(24 hours|24/7|all reasonable times)\b`)
+	openAllYearRegex     = regexp.MustCompile(`\bopen (all year|year[ -]round)\b`)
+	heritageIrelandRegex = regexp.MustCompile(`^` + dateRangeMatcher + `(?:\s*-\s*` + timeRangeMatcher + `)?$`)
+	lastAdmissionRegex   = regexp.MustCompile(`^last admission:?\s*(` + clockPattern + `)$`)
+)
+
+// ParseMedievalBritain reads the opening hours as medievalbritain.com gives
+// them: comma separated seasons or weekdays with their hours, like "Summer:
+// 10:00 - 16:00,Winter: 10:00 - 15:00" and "Monday: Closed", or a sentence
+// telling the castle is always open.
+func ParseMedievalBritain(text string) (Schedule, error) {
+	normalized := normalizeText(text)
+	if alwaysOpenRegex.MatchString(normalized) || openAllYearRegex.MatchString(normalized) {
+		return Schedule{Rules: []Rule{{}}}, nil
+	}
+
+	var rules []Rule
+	for _, entry := range strings.Split(normalized, ",") {
+		if entry = strings.TrimSpace(entry); entry == "" {
+			continue
+		}
+		label, hours, found := strings.Cut(entry, ":")
+		if !found {
+			return Schedule{}, fmt.Errorf("%w: [%s]", ErrUnrecognizedOpeningHours, entry)
+		}
+		rule, err := medievalBritainRule(strings.TrimSpace(label))
+		if err != nil {
+			return Schedule{}, err
+		}
+		if hours = strings.TrimSpace(hours); hours == "closed" {
+			rule.Closed = true
+		} else {
+			timeRange, err := ParseTimeRange(hours)
+			if err != nil {
+				return Schedule{}, err
+			}
+			rule.Times = []TimeRange{timeRange}
+		}
+		rules = append(rules, rule)
+	}
+	if len(rules) == 0 {
+		return Schedule{}, fmt.Errorf("%w: [%s]", ErrUnrecognizedOpeningHours, text)
+	}
+	return Schedule{Rules: rules}, nil
+}
+
+// medievalBritainRule returns the rule selecting the days of the label.
+func medievalBritainRule(label string) (Rule, error) {
+	switch label {
+	case "summer":
+		dates := summer
+		return Rule{Dates: &dates}, nil
+	case "winter":
+		dates := winter
+		return Rule{Dates: &dates}, nil
+	case "daily", "all year", "year-round":
+		return Rule{}, nil
+	}
+	days, err := ParseWeekdays(label)
+	if err != nil {
+		return Rule{}, err
+	}
+	return Rule{Weekdays: days}, nil
+}
+
+// ParseHeritageIreland reads the opening hours as heritageireland.ie gives
+// them: a date range optionally followed by the time range, like "01 June -
+// 29 September 2024 - 09:30- 16:00". Without times it is open the whole day.
+func ParseHeritageIreland(text string) (Schedule, error) {
+	matches := heritageIrelandRegex.FindStringSubmatch(normalizeText(text))
+	if matches == nil {
+		return Schedule{}, fmt.Errorf("%w: [%s]", ErrUnrecognizedOpeningHours, text)
+	}
+	dates, err := dateRangeOf(matches[1:7], 0, text)
+	if err != nil {
+		return Schedule{}, err
+	}
+	rule := Rule{Dates: &dates}
+	if matches[7] != "" {
+		timeRange, err := timeRangeOf(matches[7], matches[8], text)
+		if err != nil {
+			return Schedule{}, err
+		}
+		rule.Times = []TimeRange{timeRange}
+	}
+	return Schedule{Rules: []Rule{rule}}, nil
+}
+
+// ParseHeritageIrelandSeason reads a season heritageireland.ie lists apart,
+// made of its dates, like "15 March - 26 October", and lines with its hours
+// and last admission, like "Daily 10:00 – 18:00" and "Last admission: 17:15".
+// The year is used when the dates have none, zero meaning every year.
+func ParseHeritageIrelandSeason(dates string, lines []string, year int) (Rule, error) {
+	dateRange, err := ParseDateRange(dates, year)
+	if err != nil {
+		return Rule{}, err
+	}
+	rule := Rule{Dates: &dateRange}
+	for _, line := range lines {
+		normalized := normalizeText(line)
+		if matches := lastAdmissionRegex.FindStringSubmatch(normalized); matches != nil {
+			lastAdmission, err := ParseClock(matches[1])
+			if err != nil {
+				return Rule{}, err
+			}
+			rule.LastAdmission = &lastAdmission
+			continue
+		}
+		if normalized == "closed" {
+			rule.Closed = true
+			continue
+		}
+		timeRange, err := ParseTimeRange(normalized)
+		if err != nil {
+			return Rule{}, err
+		}
+		rule.Times = append(rule.Times, timeRange)
+	}
+	return rule, nil
+}
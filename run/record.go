@@ -0,0 +1,85 @@
+package run
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"time"
+)
+
+type Status string
+
+const (
+	Running Status = "running"
+	// every source listed its castles and the run finished in time
+	Succeeded Status = "succeeded"
+	// the run finished but some source failed to list its castles
+	Partial Status = "partial"
+	// the run was stopped by its timeout, leaving castles of sources not
+	// enriched yet behind
+	TimedOut Status = "timedOut"
+	// the run was aborted by an error
+	Failed Status = "failed"
+)
+
+func (s Status) String() string {
+	return string(s)
+}
+
+type Config struct {
+	TimeoutInSeconds  int      `bson:"timeoutInSeconds" json:"timeoutInSeconds"`
+	BufferSize        int      `bson:"bufferSize" json:"bufferSize"`
+	CollectingWorkers int      `bson:"collectingWorkers" json:"collectingWorkers"`
+	ExtractingWorkers int      `bson:"extractingWorkers" json:"extractingWorkers"`
+	Sources           []string `bson:"sources" json:"sources"`
+}
+
+type SourceStats struct {
+	// castles listed by the source that reached the enrichment stage
+	Collected int `bson:"collected" json:"collected"`
+	Enriched  int `bson:"enriched" json:"enriched"`
+	Failed    int `bson:"failed" json:"failed"`
+	// whether the source failed to list its castles
+	CollectionFailed bool `bson:"collectionFailed" json:"collectionFailed"`
+	// first errors received, limited to avoid huge documents
+	Errors []string `bson:"errors" json:"errors"`
+	// states given by the source that no subdivision was found for, each
+	// one once and limited as errors are
+	UnresolvedStates []string `bson:"unresolvedStates" json:"unresolvedStates"`
+	// castles which coordinates were swapped or had a sign flipped, and were corrected
+	CorrectedCoordinates int `bson:"correctedCoordinates" json:"correctedCoordinates"`
+	// castles which coordinates are outside their country or can't be
+	// parsed, each one once and limited as errors are
+	InvalidCoordinates []string `bson:"invalidCoordinates" json:"invalidCoordinates"`
+}
+
+type Record struct {
+	ID         string                  `bson:"_id" json:"id"`
+	StartedAt  time.Time               `bson:"startedAt" json:"startedAt"`
+	FinishedAt time.Time               `bson:"finishedAt" json:"finishedAt"`
+	Status     Status                  `bson:"status" json:"status"`
+	Config     Config                  `bson:"config" json:"config"`
+	Sources    map[string]*SourceStats `bson:"sources" json:"sources"`
+	Inserted   int64                   `bson:"inserted" json:"inserted"`
+	Updated    int64                   `bson:"updated" json:"updated"`
+	Unchanged  int64                   `bson:"unchanged" json:"unchanged"`
+	Merges     int                     `bson:"merges" json:"merges"`
+	// castles which state, city or coordinates were filled by geocoding
+	Geocoded int      `bson:"geocoded" json:"geocoded"`
+	Errors   []string `bson:"errors" json:"errors"`
+}
+
+func (r Record) Duration() time.Duration {
+	if r.FinishedAt.IsZero() {
+		return 0
+	}
+	return r.FinishedAt.Sub(r.StartedAt)
+}
+
+func NewID(startedAt time.Time) (string, error) {
+	suffix := make([]byte, 4)
+	if _, err := rand.Read(suffix); err != nil {
+		return "", fmt.Errorf("failed to generate run ID, got %v", err)
+	}
+	return fmt.Sprintf("%s-%s", startedAt.UTC().Format("20060102T150405"), hex.EncodeToString(suffix)), nil
+}
@@ -0,0 +1,166 @@
+package run
+
+import (
+	"slices"
+	"sync"
+	"time"
+)
+
+const (
+	maxErrorsPerSource             = 20
+	maxUnresolvedStatesPerSource   = 50
+	maxInvalidCoordinatesPerSource = 50
+)
+
+// Recorder collects the stats of an enrichment run. It is safe for concurrent use.
+type Recorder struct {
+	mutex  sync.Mutex
+	record Record
+}
+
+func NewRecorder(id string, startedAt time.Time, config Config) *Recorder {
+	sources := make(map[string]*SourceStats, len(config.Sources))
+	for _, source := range config.Sources {
+		sources[source] = &SourceStats{}
+	}
+	return &Recorder{
+		record: Record{
+			ID:        id,
+			StartedAt: startedAt,
+			Status:    Running,
+			Config:    config,
+			Sources:   sources,
+		},
+	}
+}
+
+func (r *Recorder) ID() string {
+	return r.record.ID
+}
+
+func (r *Recorder) CastleEnriched(source string) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	stats := r.statsOf(source)
+	stats.Collected++
+	stats.Enriched++
+}
+
+func (r *Recorder) CastleFailed(source string, err error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	stats := r.statsOf(source)
+	stats.Collected++
+	stats.Failed++
+	stats.addError(err)
+}
+
+// StateUnresolved records a state of the source the gazetteer doesn't know,
+// which tells the aliases it misses.
+func (r *Recorder) StateUnresolved(source, state string) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	stats := r.statsOf(source)
+	if len(stats.UnresolvedStates) >= maxUnresolvedStatesPerSource || slices.Contains(stats.UnresolvedStates, state) {
+		return
+	}
+	stats.UnresolvedStates = append(stats.UnresolvedStates, state)
+}
+
+func (r *Recorder) CoordinatesCorrected(source string) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.statsOf(source).CorrectedCoordinates++
+}
+
+// CoordinatesInvalid records a castle of the source which coordinates
+// someone has to look at, like "castle [coordinates] problem".
+func (r *Recorder) CoordinatesInvalid(source, castle string) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	stats := r.statsOf(source)
+	if len(stats.InvalidCoordinates) >= maxInvalidCoordinatesPerSource || slices.Contains(stats.InvalidCoordinates, castle) {
+		return
+	}
+	stats.InvalidCoordinates = append(stats.InvalidCoordinates, castle)
+}
+
+func (r *Recorder) CollectionFailed(source string, err error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	stats := r.statsOf(source)
+	stats.CollectionFailed = true
+	stats.addError(err)
+}
+
+func (r *Recorder) CastlesSaved(inserted, updated, unchanged int64) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.record.Inserted += inserted
+	r.record.Updated += updated
+	r.record.Unchanged += unchanged
+}
+
+func (r *Recorder) CastlesMerged(merges int) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.record.Merges += merges
+}
+
+func (r *Recorder) CastlesGeocoded(geocoded int) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.record.Geocoded += geocoded
+}
+
+// Successful reports whether every source managed to list its castles so far.
+func (r *Recorder) Successful() bool {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	for _, stats := range r.record.Sources {
+		if stats.CollectionFailed {
+			return false
+		}
+	}
+	return true
+}
+
+// Finish closes the run. A run that timed out is marked as so, giving a non
+// nil err marks it as failed, otherwise it is marked as succeeded or partial
+// according to what was recorded.
+func (r *Recorder) Finish(finishedAt time.Time, timedOut bool, err error) Record {
+	successful := r.Successful()
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.record.FinishedAt = finishedAt
+	switch {
+	case timedOut:
+		r.record.Status = TimedOut
+		r.record.Errors = append(r.record.Errors, "run timed out")
+	case err != nil:
+		r.record.Status = Failed
+		r.record.Errors = append(r.record.Errors, err.Error())
+	case !successful:
+		r.record.Status = Partial
+	default:
+		r.record.Status = Succeeded
+	}
+	return r.record
+}
+
+func (r *Recorder) statsOf(source string) *SourceStats {
+	stats, found := r.record.Sources[source]
+	if !found {
+		stats = &SourceStats{}
+		r.record.Sources[source] = stats
+	}
+	return stats
+}
+
+func (s *SourceStats) addError(err error) {
+	if err == nil || len(s.Errors) >= maxErrorsPerSource {
+		return
+	}
+	s.Errors = append(s.Errors, err.Error())
+}
@@ -0,0 +1,127 @@
+package run
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRecorder(t *testing.T) {
+	testCases := []struct {
+		name           string
+		record         func(r *Recorder)
+		timedOut       bool
+		err            error
+		expectedStatus Status
+	}{
+		{
+			name: "when every source listed its castles",
+			record: func(r *Recorder) {
+				r.CastleEnriched("HeritageIreland")
+				r.CastleFailed("HeritageIreland", errors.New("timeout"))
+			},
+			expectedStatus: Succeeded,
+		},
+		{
+			name: "when a source failed to list its castles",
+			record: func(r *Recorder) {
+				r.CastleEnriched("HeritageIreland")
+				r.CollectionFailed("MedievalBritain", errors.New("503"))
+			},
+			expectedStatus: Partial,
+		},
+		{
+			name:           "when the run timed out",
+			record:         func(r *Recorder) {},
+			timedOut:       true,
+			err:            context.DeadlineExceeded,
+			expectedStatus: TimedOut,
+		},
+		{
+			name:           "when the run was aborted",
+			record:         func(r *Recorder) {},
+			err:            errors.New("db is down"),
+			expectedStatus: Failed,
+		},
+	}
+
+	for _, tt := range testCases {
+		currentTT := tt
+		t.Run(currentTT.name, func(t *testing.T) {
+			t.Helper()
+			startedAt := time.Now()
+			r := NewRecorder("some-id", startedAt, Config{Sources: []string{"HeritageIreland", "MedievalBritain"}})
+
+			currentTT.record(r)
+			record := r.Finish(startedAt.Add(time.Minute), currentTT.timedOut, currentTT.err)
+
+			if record.Status != currentTT.expectedStatus {
+				t.Errorf("expected status [%s], got [%s]", currentTT.expectedStatus, record.Status)
+			}
+			if record.Duration() != time.Minute {
+				t.Errorf("expected duration of 1m, got %v", record.Duration())
+			}
+		})
+	}
+}
+
+func TestRecorderCountsPerSource(t *testing.T) {
+	r := NewRecorder("some-id", time.Now(), Config{})
+
+	r.CastleEnriched("EDBIDAT")
+	r.CastleEnriched("EDBIDAT")
+	r.CastleFailed("EDBIDAT", errors.New("404"))
+	r.CastlesSaved(1, 2, 3)
+	r.CastlesMerged(2)
+	r.CastlesGeocoded(4)
+
+	record := r.Finish(time.Now(), false, nil)
+
+	stats := record.Sources["EDBIDAT"]
+	if stats == nil {
+		t.Fatalf("expected stats of EDBIDAT to be present")
+	}
+	if stats.Collected != 3 || stats.Enriched != 2 || stats.Failed != 1 {
+		t.Errorf("expected 3 collected, 2 enriched and 1 failed, got %+v", stats)
+	}
+	if len(stats.Errors) != 1 {
+		t.Errorf("expected 1 error, got %d", len(stats.Errors))
+	}
+	if record.Inserted != 1 || record.Updated != 2 || record.Unchanged != 3 || record.Merges != 2 || record.Geocoded != 4 {
+		t.Errorf("unexpected saving stats %+v", record)
+	}
+}
+
+func TestRecorderKeepsEachUnresolvedStateOnce(t *testing.T) {
+	r := NewRecorder("some-id", time.Now(), Config{})
+
+	r.StateUnresolved("MedievalBritain", "berkshire, greaterlondon")
+	r.StateUnresolved("MedievalBritain", "berkshire, greaterlondon")
+	r.StateUnresolved("MedievalBritain", "east riding of yorkshire")
+
+	record := r.Finish(time.Now(), false, nil)
+
+	states := record.Sources["MedievalBritain"].UnresolvedStates
+	if len(states) != 2 {
+		t.Errorf("expected 2 unresolved states, got %v", states)
+	}
+}
+
+func TestRecorderFlagsCoordinates(t *testing.T) {
+	r := NewRecorder("some-id", time.Now(), Config{})
+
+	r.CoordinatesCorrected("HeritageIreland")
+	r.CoordinatesInvalid("HeritageIreland", "trim [38.7223,-9.1393] outsideCountry")
+	r.CoordinatesInvalid("HeritageIreland", "trim [38.7223,-9.1393] outsideCountry")
+
+	record := r.Finish(time.Now(), false, nil)
+
+	stats := record.Sources["HeritageIreland"]
+	if stats.CorrectedCoordinates != 1 {
+		t.Errorf("expected 1 castle with corrected coordinates, got %d", stats.CorrectedCoordinates)
+	}
+	if len(stats.InvalidCoordinates) != 1 {
+		t.Errorf("expected 1 castle with invalid coordinates, got %v", stats.InvalidCoordinates)
+	}
+}
@@ -9,17 +9,26 @@ import PicnicIcon from '@mui/icons-material/Deck';
 import LocalParkingIcon from '@mui/icons-material/LocalParking';
 import MuseumIcon from '@mui/icons-material/Museum';
 import AccessibleIcon from '@mui/icons-material/Accessible';
+import HeadphonesIcon from '@mui/icons-material/Headphones';
+import GroupsIcon from '@mui/icons-material/Groups';
+import FamilyIcon from '@mui/icons-material/EscalatorWarning';
+import WcIcon from '@mui/icons-material/Wc';
+import EvStationIcon from '@mui/icons-material/EvStation';
+import PaymentsIcon from '@mui/icons-material/Payments';
 import InfoIcon from '@mui/icons-material/Info';
 import { MetadataProps } from "@find-castles/lib/metadata-props";
 import { ResolvingMetadata, Metadata } from "next";
 import { toTitleCase } from "@find-castles/lib/to-title-case";
 import { getCastles } from "@find-castles/lib/db/get-castles";
-import { getCastle } from "@find-castles/lib/db/get-castle";
-import { notFound } from "next/navigation";
+import { getCastle, getCastleByAlias } from "@find-castles/lib/db/get-castle";
+import { notFound, permanentRedirect } from "next/navigation";
+import { displayName, otherNames } from "@find-castles/lib/display-name";
+import { Facility, TicketCategory } from "@find-castles/lib/db/model";
 
 export const dynamicParams = true;
 
 const siteHost = process.env.SITE_HOST!;
+const siteLanguage = 'en';
 
 export async function generateMetadata(
   { params, searchParams }: MetadataProps,
@@ -28,14 +37,15 @@ export async function generateMetadata(
   const castleWebName = params.slug;
   const foundCastle = await getCastle(castleWebName);
   if (!foundCastle) {
-    notFound();
+    await redirectFromAlias(castleWebName);
   }
+  const name = displayName(foundCastle, siteLanguage);
 
   return {
     metadataBase: new URL('https://find-castles.vercel.app'),
-    title: toTitleCase(foundCastle.name),
-    description: `Discover ${foundCastle.name} castle on Find Castles`,
-    keywords: [foundCastle.name, foundCastle.country, "castles", "heritage", "european castles", "data sources", "historical castles", "tracked countries", "untracked countries"],
+    title: toTitleCase(name),
+    description: `Discover ${name} castle on Find Castles`,
+    keywords: [name, ...otherNames(foundCastle, siteLanguage), foundCastle.country, "castles", "heritage", "european castles", "data sources", "historical castles", "tracked countries", "untracked countries"],
     applicationName: 'Find Castles',
     robots: { index: true, follow: true },
     authors: {
@@ -43,8 +53,8 @@ export async function generateMetadata(
       url: 'https://buarki.com'
     },
     openGraph: {
-      title: toTitleCase(foundCastle.name),
-      description: `Discover ${foundCastle.name} castle on Find Castles`,
+      title: toTitleCase(name),
+      description: `Discover ${name} castle on Find Castles`,
       url: `${siteHost}/${foundCastle.webName}`,
       type: "website",
       images: [
@@ -59,25 +69,22 @@ export async function generateMetadata(
     twitter: {
       card: "summary_large_image",
       site: "@buarki",
-      title: toTitleCase(foundCastle.name),
-      description: `Discover ${foundCastle.name} castle on Find Castles`,
+      title: toTitleCase(name),
+      description: `Discover ${name} castle on Find Castles`,
       images: foundCastle.pictureURL,
     }
   };
 };
 
-interface Facilities {
-  assistanceDogsAllowed: boolean;
-  cafe: boolean;
-  restrooms: boolean;
-  giftshops: boolean;
-  pinicArea: boolean;
-  parking: boolean;
-  exhibitions: boolean;
-  wheelchairSupport: boolean;
-}
+const ticketCategoryLabels: { [key in TicketCategory]: string } = {
+  adult: "Adult",
+  child: "Child",
+  senior: "Senior",
+  family: "Family",
+  student: "Student",
+};
 
-const facilitiesIcons: { [key in keyof Facilities]: { icon: JSX.Element, label: string } } = {
+const facilitiesIcons: { [key in Facility]: { icon: JSX.Element, label: string } } = {
   assistanceDogsAllowed: { icon: <PetsIcon />, label: "Assistance Dogs Allowed" },
   cafe: { icon: <LocalCafeIcon />, label: "Cafe" },
   restrooms: { icon: <RestroomIcon />, label: "Restrooms" },
@@ -86,6 +93,12 @@ const facilitiesIcons: { [key in keyof Facilities]: { icon: JSX.Element, label:
   parking: { icon: <LocalParkingIcon />, label: "Parking" },
   exhibitions: { icon: <MuseumIcon />, label: "Exhibitions" },
   wheelchairSupport: { icon: <AccessibleIcon />, label: "Wheelchair Support" },
+  audioGuide: { icon: <HeadphonesIcon />, label: "Audio Guide" },
+  guidedTours: { icon: <GroupsIcon />, label: "Guided Tours" },
+  familyFriendly: { icon: <FamilyIcon />, label: "Family Friendly" },
+  accessibleToilets: { icon: <WcIcon />, label: "Accessible Toilets" },
+  evCharging: { icon: <EvStationIcon />, label: "EV Charging" },
+  admissionFee: { icon: <PaymentsIcon />, label: "Admission Fee" },
 };
 
 type CastlePageProps = {
@@ -99,25 +112,39 @@ export async function generateStaticParams() {
           .map((foundCastle) => ({ slug: foundCastle.webName, }));
 }
 
+// castles renamed by their sources keep their old links working
+async function redirectFromAlias(webName: string): Promise<never> {
+  const renamedCastle = await getCastleByAlias(webName);
+  if (!renamedCastle) {
+    notFound();
+  }
+  permanentRedirect(`/castles/${renamedCastle.webName}`);
+}
+
 export default async function CastlePage({ params }: CastlePageProps) {
   const webName = params.slug;
   const foundCastle = await getCastle(webName);
   if (!foundCastle) {
-    notFound();
+    await redirectFromAlias(webName);
   }
 
+  const name = displayName(foundCastle, siteLanguage);
+  const alsoKnownAs = otherNames(foundCastle, siteLanguage);
+  // names as sources give them may already say it is a castle
+  const title = /castle/i.test(name) ? toTitleCase(name) : `${toTitleCase(name)} Castle`;
   const {
-    name,
     pictureURL,
     contact,
     district,
     city,
     state,
+    address,
     visitingInfo,
     sources,
     coordinates,
     country,
     propertyCondition,
+    status,
   } = foundCastle;
 
   return (
@@ -127,7 +154,8 @@ export default async function CastlePage({ params }: CastlePageProps) {
         dangerouslySetInnerHTML={{ __html: JSON.stringify({
           "@context": "https://schema.org",
           "@type": "TouristAttraction",
-          "name": `${toTitleCase(name)} Castle`,
+          "name": title,
+          "alternateName": alsoKnownAs.length > 0 ? alsoKnownAs : undefined,
           "description": `Discover ${name} castle located in ${state}, ${country}.`,
           "image": pictureURL,
           "address": {
@@ -135,6 +163,8 @@ export default async function CastlePage({ params }: CastlePageProps) {
             "addressLocality": city,
             "addressRegion": state,
             "addressCountry": country,
+            "streetAddress": address?.street || undefined,
+            "postalCode": address?.postcode || undefined,
           },
           "geo": coordinates ? {
             "@type": "GeoCoordinates",
@@ -144,10 +174,10 @@ export default async function CastlePage({ params }: CastlePageProps) {
           "telephone": contact?.phone || undefined,
           "email": contact?.email || undefined,
           "openingHours": visitingInfo?.workingHours || undefined,
-          "amenityFeature": Object.entries(visitingInfo?.facilities ?? {}).map(([facilityName, facilityAvailability]) => ({
+          "amenityFeature": Object.entries(visitingInfo?.facilities ?? {}).map(([facilityName, facilityInfo]) => ({
             "@type": "LocationFeatureSpecification",
             "name": facilityName,
-            "value": facilityAvailability,
+            "value": facilityInfo?.availability === 'yes',
           })),
           "url": `${siteHost}/${webName}`,
           "sameAs": sources
@@ -155,9 +185,23 @@ export default async function CastlePage({ params }: CastlePageProps) {
       />
       <Box sx={{ my: 3 }}>
         <Typography variant="h4" align="center" gutterBottom>
-          {toTitleCase(name)} Castle
+          {title}
         </Typography>
 
+        {alsoKnownAs.length > 0 && (
+          <Typography variant="subtitle1" align="center" color="text.secondary" gutterBottom>
+            Also known as {alsoKnownAs.join(', ')}
+          </Typography>
+        )}
+
+        {(status === 'stale' || status === 'removed') && (
+          <Typography align="center" color="warning.main" gutterBottom>
+            {status === 'removed'
+              ? 'This castle is no longer listed by any of its sources.'
+              : 'This castle was not found on its sources recently, its data may be outdated.'}
+          </Typography>
+        )}
+
         <Card>
           <CardMedia
             component="img"
@@ -185,10 +229,10 @@ export default async function CastlePage({ params }: CastlePageProps) {
 
             <Grid container spacing={2} justifyContent="center">
               {
-                Object.values(visitingInfo?.facilities ?? {}).some((facility) => facility) ?
+                Object.values(visitingInfo?.facilities ?? {}).some((facility) => facility?.availability === 'yes') ?
                 Object.keys(visitingInfo?.facilities ?? {}).map((facility) => {
-                  const key = facility as keyof Facilities;
-                  return (visitingInfo?.facilities ?? {})[key] && (
+                  const key = facility as Facility;
+                  return (visitingInfo?.facilities ?? {})[key]?.availability === 'yes' && (
                     <Grid item key={key} sx={{ display: 'flex', flexDirection: 'column', alignItems: 'center' }}>
                       <Tooltip title={facilitiesIcons[key]?.label} arrow>
                         <IconButton aria-label={facilitiesIcons[key]?.label}>
@@ -233,6 +277,30 @@ export default async function CastlePage({ params }: CastlePageProps) {
               </Box>
             )}
 
+            {visitingInfo?.admission && (
+              <Box sx={{ mt: 3 }}>
+                <Typography variant="h6" align="center" gutterBottom>Admission</Typography>
+                {visitingInfo.admission.free && (
+                  <Typography align="center">Free admission</Typography>
+                )}
+                {(visitingInfo.admission.prices ?? []).map((price) => (
+                  <Typography align="center" key={price.category}>
+                    {ticketCategoryLabels[price.category]}: {price.currency
+                      ? new Intl.NumberFormat('en', { style: 'currency', currency: price.currency }).format(price.amount)
+                      : 'Free'}
+                  </Typography>
+                ))}
+                {visitingInfo.admission.membershipNotes && (
+                  <Typography align="center" color="text.secondary">{visitingInfo.admission.membershipNotes}</Typography>
+                )}
+                {visitingInfo.admission.bookingURL && (
+                  <Typography align="center">
+                    <Link target="_blank" rel="noopener noreferrer" href={visitingInfo.admission.bookingURL}>Book tickets</Link>
+                  </Typography>
+                )}
+              </Box>
+            )}
+
             {propertyCondition && (
               <Box sx={{ mt: 3 }}>
                 <Typography variant="h6" align="center" gutterBottom>Property Status</Typography>
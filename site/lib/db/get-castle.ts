@@ -9,3 +9,12 @@ export async function getCastle(webName: string): Promise<Castle> {
     webName,
   }) as any as Castle;
 }
+
+// finds the castle that had the given web name before being renamed
+export async function getCastleByAlias(webName: string): Promise<Castle> {
+  const dbClient = await getDBClient();
+  const collections = dbClient.db('find-castles').collection('castles');
+  return collections.findOne({
+    webNameAliases: webName,
+  }) as any as Castle;
+}
@@ -13,5 +13,8 @@ export async function getCastles({ contryCodes }: GetCastlesProps): Promise<Cast
     country: {
       $in: contryCodes,
     },
+    status: {
+      $ne: 'removed',
+    },
   }).toArray() as any as Castle[];
 }
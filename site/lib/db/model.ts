@@ -5,34 +5,131 @@ export interface Contact {
   email?: string;
 }
 
-export interface Facilities {
-  assistanceDogsAllowed: boolean;
-  cafe: boolean;
-  restrooms: boolean;
-  giftshops: boolean;
-  pinicArea: boolean;
-  parking: boolean;
-  exhibitions: boolean;
-  wheelchairSupport: boolean;
+export type Facility =
+  | 'assistanceDogsAllowed'
+  | 'cafe'
+  | 'restrooms'
+  | 'giftshops'
+  | 'pinicArea'
+  | 'parking'
+  | 'exhibitions'
+  | 'wheelchairSupport'
+  | 'audioGuide'
+  | 'guidedTours'
+  | 'familyFriendly'
+  | 'accessibleToilets'
+  | 'evCharging'
+  | 'admissionFee';
+
+// facilities nothing is known about are left out
+export interface FacilityInfo {
+  availability: 'yes' | 'no';
+  // link of the page that told it, empty when set by hand
+  source: string;
+}
+
+export type Facilities = Partial<Record<Facility, FacilityInfo>>;
+
+export type TicketCategory = 'adult' | 'child' | 'senior' | 'family' | 'student';
+
+// free tickets have no currency
+export interface Price {
+  category: TicketCategory;
+  amount: number;
+  currency: string;
+}
+
+export interface Admission {
+  prices?: Price[];
+  free: boolean;
+  membershipNotes: string;
+  bookingURL: string;
+}
+
+export interface OpeningHoursDate {
+  year?: number;
+  month: number;
+  day: number;
+}
+
+// times are in minutes since midnight and weekdays start on Sunday as 0
+export interface OpeningHoursRule {
+  dates?: { from: OpeningHoursDate; to: OpeningHoursDate };
+  weekdays?: number[];
+  times?: { from: number; to: number }[];
+  closed?: boolean;
+  lastAdmission?: number;
 }
 
 export interface VisitingInfo {
   workingHours: string;
+  openingHours?: { rules: OpeningHoursRule[] };
   facilities?: Facilities;
+  admission?: Admission;
+}
+
+export interface Period {
+  earliest: number;
+  latest: number;
+  precision: 'year' | 'decade' | 'century';
+  text: string;
+}
+
+export type Language = 'en' | 'pt' | 'sk' | 'de' | 'da' | 'ga';
+
+export type PropertyCondition =
+  | 'unknown'
+  | 'intact'
+  | 'restored'
+  | 'rebuilt'
+  | 'converted'
+  | 'damaged'
+  | 'partialRuins'
+  | 'ruins'
+  | 'foundationsOnly'
+  | 'archaeologicalSite'
+  | 'destroyed'
+  | 'submerged';
+
+export type CastleStatus = 'active' | 'stale' | 'removed';
+
+// city, state and district are derived from it when known
+export interface Address {
+  street: string;
+  locality: string;
+  town: string;
+  region: string;
+  postcode: string;
+  country: CountryCode;
+  // as the source gave them
+  lines?: string[];
 }
 
 export interface Castle {
   _id: string;
+  id: string;
   country: CountryCode;
   name: string;
+  localizedNames?: Partial<Record<Language, string>>;
+  alternateNames?: string[];
   city: string;
   contact?: Contact;
   coordinates: string;
+  // fields filled by offline geocoding instead of told by a source
+  derived?: ('state' | 'city' | 'coordinates')[];
   pictureURL: string;
   sources: string[];
   state: string;
+  stateCode?: string;
+  stateLabel?: string;
   district?: string;
+  address?: Address;
+  foundationPeriod?: string;
+  foundation?: Period;
   visitingInfo?: VisitingInfo;
   webName: string;
-  propertyCondition: string;
+  webNameAliases?: string[];
+  propertyCondition: PropertyCondition;
+  propertyConditionLabel?: string;
+  status?: CastleStatus;
 }
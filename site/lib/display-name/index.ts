@@ -0,0 +1,18 @@
+import { Castle, Language } from "../db/model";
+
+// name of the castle on the given language, falling back to its primary name
+export function displayName(castle: Castle, language: Language): string {
+  return castle.localizedNames?.[language] || castle.name;
+}
+
+// every other name the castle is known by
+export function otherNames(castle: Castle, language: Language): string[] {
+  const shown = displayName(castle, language).toLowerCase();
+  const names = [
+    ...Object.values(castle.localizedNames ?? {}),
+    ...(castle.alternateNames ?? []),
+  ];
+  return names.filter((name, i) =>
+    name.toLowerCase() !== shown &&
+    names.findIndex((other) => other.toLowerCase() === name.toLowerCase()) === i);
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"strings"
 	"unicode"
+	"unicode/utf8"
 
 	"golang.org/x/text/runes"
 	"golang.org/x/text/transform"
@@ -11,17 +12,45 @@ import (
 )
 
 var (
-	replacer = strings.NewReplacer(
-		`∅`, "o",
-		`ø`, "o",
-	)
+	stripMarks = transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFKC)
 )
 
+// From transliterates the string into ASCII with the rules every language
+// shares. Characters without a transliteration are removed, so the result is
+// always ASCII.
 func From(s string) (string, error) {
-	t := transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFKC)
-	result, _, err := transform.String(t, s)
+	return FromLanguage(s, "")
+}
+
+// FromLanguage transliterates the string into ASCII applying first the rules
+// of the given language code, ex: German turns ä into ae while the shared
+// rules turn it into a. Unknown languages only use the shared rules.
+func FromLanguage(s, language string) (string, error) {
+	s = norm.NFC.String(s)
+	if table, found := languageTables[language]; found {
+		s = transliterate(s, table, true)
+	}
+	s = transliterate(s, scriptsTable, true)
+	result, _, err := transform.String(stripMarks, s)
 	if err != nil {
 		return "", fmt.Errorf("failed to parse [%s] to ascii, got [%v]", s, err)
 	}
-	return replacer.Replace(result), nil
+	return transliterate(result, sharedTable, false), nil
+}
+
+// transliterate replaces the runes found on the table. Unless keepUnknown is
+// set, runes not found that aren't ASCII are removed.
+func transliterate(s string, table map[rune]string, keepUnknown bool) string {
+	var builder strings.Builder
+	builder.Grow(len(s))
+	for _, r := range s {
+		if replacement, found := table[r]; found {
+			builder.WriteString(replacement)
+			continue
+		}
+		if keepUnknown || (r < utf8.RuneSelf && r != utf8.RuneError) {
+			builder.WriteRune(r)
+		}
+	}
+	return builder.String()
 }
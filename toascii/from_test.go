@@ -2,6 +2,8 @@ package toascii_test
 
 import (
 	"testing"
+	"testing/quick"
+	"unicode/utf8"
 
 	"github.com/buarki/find-castles/toascii"
 )
@@ -66,3 +68,71 @@ func TestFrom(t *testing.T) {
 		})
 	}
 }
+
+func TestFromLanguage(t *testing.T) {
+	testCases := []struct {
+		input    string
+		language string
+		output   string
+	}{
+		{input: "Burg Altenstein", language: "de", output: "Burg Altenstein"},
+		{input: "Schloß Würzburg", language: "de", output: "Schloss Wuerzburg"},
+		{input: "Schloß Würzburg", language: "", output: "Schloss Wurzburg"},
+		{input: "Ötztal", language: "de", output: "Oetztal"},
+		{input: "Ötztal", language: "sk", output: "Otztal"},
+		{input: "Sæbygård", language: "da", output: "Saebygaard"},
+		{input: "Sæbygård", language: "", output: "Saebygard"},
+		{input: "Sorø", language: "da", output: "Soroe"},
+		{input: "Malbork Łańcut", language: "", output: "Malbork Lancut"},
+		{input: "Þingvellir", language: "", output: "Thingvellir"},
+		{input: "Đakovo", language: "", output: "Dakovo"},
+		{input: "Œuvre", language: "", output: "OEuvre"},
+		{input: "Замок Їжакевича", language: "", output: "Zamok Yizhakevicha"},
+		{input: "Мирский замок", language: "", output: "Mirskiy zamok"},
+		{input: "Κάστρο Μυστρά", language: "", output: "Kastro Mystra"},
+		{input: "O’Brien’s Castle – Ruins", language: "", output: "O'Brien's Castle - Ruins"},
+		{input: "城", language: "", output: ""},
+		{input: "invalid \xffutf8", language: "", output: "invalid utf8"},
+	}
+
+	for _, tt := range testCases {
+		currentTT := tt
+		t.Run(currentTT.input+"_"+currentTT.language, func(t *testing.T) {
+			t.Helper()
+
+			received, err := toascii.FromLanguage(currentTT.input, currentTT.language)
+
+			if err != nil {
+				t.Errorf("expected to have err nil, got %v", err)
+			}
+
+			if received != currentTT.output {
+				t.Errorf("expected to get [%s], got [%s]", currentTT.output, received)
+			}
+		})
+	}
+}
+
+func TestFromIsAlwaysASCII(t *testing.T) {
+	for _, language := range []string{"", "de", "da", "pt", "en"} {
+		currentLanguage := language
+		t.Run("language_"+currentLanguage, func(t *testing.T) {
+			t.Helper()
+			isASCII := func(s string) bool {
+				received, err := toascii.FromLanguage(s, currentLanguage)
+				if err != nil {
+					return false
+				}
+				for i := 0; i < len(received); i++ {
+					if received[i] >= utf8.RuneSelf {
+						return false
+					}
+				}
+				return true
+			}
+			if err := quick.Check(isASCII, &quick.Config{MaxCount: 5000}); err != nil {
+				t.Errorf("expected output to always be ASCII, got %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package toascii
+
+import (
+	"strings"
+	"unicode"
+)
+
+var (
+	// letters of Latin Extended that don't decompose into a base letter
+	// plus combining marks, and punctuation that has an ASCII counterpart
+	latinExtended = map[rune]string{
+		'ß': "ss", 'ẞ': "SS",
+		'æ': "ae", 'Æ': "AE",
+		'œ': "oe", 'Œ': "OE",
+		'ø': "o", 'Ø': "O", '∅': "o",
+		'ł': "l", 'Ł': "L",
+		'đ': "d", 'Đ': "D",
+		'ð': "d", 'Ð': "D",
+		'þ': "th", 'Þ': "Th",
+		'ħ': "h", 'Ħ': "H",
+		'ŧ': "t", 'Ŧ': "T",
+		'ı': "i",
+		'ŋ': "ng", 'Ŋ': "Ng",
+		'ſ': "s",
+		'ƒ': "f",
+		'‘': "'", '’': "'", '‚': "'",
+		'“': `"`, '”': `"`, '„': `"`, '«': `"`, '»': `"`,
+		'–': "-", '—': "-", '‐': "-",
+		'…': "...",
+	}
+
+	cyrillic = map[rune]string{
+		'а': "a", 'б': "b", 'в': "v", 'г': "g", 'д': "d", 'е': "e", 'ё': "yo",
+		'ж': "zh", 'з': "z", 'и': "i", 'й': "y", 'к': "k", 'л': "l", 'м': "m",
+		'н': "n", 'о': "o", 'п': "p", 'р': "r", 'с': "s", 'т': "t", 'у': "u",
+		'ф': "f", 'х': "kh", 'ц': "ts", 'ч': "ch", 'ш': "sh", 'щ': "shch",
+		'ъ': "", 'ы': "y", 'ь': "", 'э': "e", 'ю': "yu", 'я': "ya",
+		'і': "i", 'ї': "yi", 'є': "ye", 'ґ': "g",
+		'ђ': "dj", 'ј': "j", 'љ': "lj", 'њ': "nj", 'ћ': "c", 'џ': "dz", 'ў': "u",
+	}
+
+	greek = map[rune]string{
+		'α': "a", 'β': "v", 'γ': "g", 'δ': "d", 'ε': "e", 'ζ': "z", 'η': "i",
+		'θ': "th", 'ι': "i", 'κ': "k", 'λ': "l", 'μ': "m", 'ν': "n", 'ξ': "x",
+		'ο': "o", 'π': "p", 'ρ': "r", 'σ': "s", 'ς': "s", 'τ': "t", 'υ': "y",
+		'φ': "f", 'χ': "ch", 'ψ': "ps", 'ω': "o",
+	}
+
+	// scriptsTable is applied before combining marks are removed, as letters
+	// like й are transliterated differently from their base letter
+	scriptsTable = merge(withUpperCase(cyrillic), withUpperCase(greek))
+
+	// sharedTable is applied after combining marks are removed, so it only
+	// needs base letters, like the α left by ά
+	sharedTable = merge(latinExtended, scriptsTable)
+
+	// languageTables are applied before combining marks are removed, as
+	// their rules depend on them
+	languageTables = map[string]map[rune]string{
+		"de": withUpperCase(map[rune]string{
+			'ä': "ae", 'ö': "oe", 'ü': "ue", 'ß': "ss",
+		}),
+		"da": withUpperCase(map[rune]string{
+			'æ': "ae", 'ø': "oe", 'å': "aa",
+		}),
+	}
+)
+
+// withUpperCase adds to the table the upper case of each letter, capitalizing
+// the first letter of its transliteration.
+func withUpperCase(table map[rune]string) map[rune]string {
+	result := make(map[rune]string, len(table)*2)
+	for r, replacement := range table {
+		result[r] = replacement
+		if upper := unicode.ToUpper(r); upper != r {
+			if replacement != "" {
+				replacement = strings.ToUpper(replacement[:1]) + replacement[1:]
+			}
+			result[upper] = replacement
+		}
+	}
+	return result
+}
+
+func merge(tables ...map[rune]string) map[rune]string {
+	result := make(map[rune]string)
+	for _, table := range tables {
+		for r, replacement := range table {
+			result[r] = replacement
+		}
+	}
+	return result
+}